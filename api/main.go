@@ -1,7 +1,11 @@
 package main
 
 import (
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/NdoleStudio/httpmock/docs"
 
@@ -50,7 +54,20 @@ func main() {
 		go serveHTTPS(app, err)
 	}
 
-	container.Logger().Error(<-err)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case e := <-err:
+		container.Logger().Error(e)
+	case <-signals:
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if e := container.Shutdown(ctx); e != nil {
+			container.Logger().Error(e)
+		}
+	}
 }
 
 func serveHTTP(app *fiber.App, err chan<- error) {
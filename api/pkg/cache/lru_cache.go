@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// LRUCache is a concurrency safe cache which evicts the least recently used value when it contains more than its capacity
+type LRUCache[K comparable, V any] struct {
+	mutex    sync.Mutex
+	capacity int
+	items    map[K]*list.Element
+	order    *list.List
+}
+
+type lruCacheEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// NewLRUCache creates a new LRUCache which contains at most capacity values
+func NewLRUCache[K comparable, V any](capacity int) *LRUCache[K, V] {
+	return &LRUCache[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+// Get returns the value of a key and marks it as the most recently used value
+func (cache *LRUCache[K, V]) Get(key K) (value V, ok bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, ok := cache.items[key]
+	if !ok {
+		return value, false
+	}
+
+	cache.order.MoveToFront(element)
+	return element.Value.(*lruCacheEntry[K, V]).value, true
+}
+
+// Add stores the value of a key and evicts the least recently used value when the cache is full
+func (cache *LRUCache[K, V]) Add(key K, value V) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element, ok := cache.items[key]; ok {
+		element.Value.(*lruCacheEntry[K, V]).value = value
+		cache.order.MoveToFront(element)
+		return
+	}
+
+	cache.items[key] = cache.order.PushFront(&lruCacheEntry[K, V]{key: key, value: value})
+	if cache.order.Len() <= cache.capacity {
+		return
+	}
+
+	oldest := cache.order.Back()
+	cache.order.Remove(oldest)
+	delete(cache.items, oldest.Value.(*lruCacheEntry[K, V]).key)
+}
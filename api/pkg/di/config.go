@@ -1,13 +1,67 @@
 package di
 
 import (
+	"slices"
+	"time"
+
 	"github.com/gofiber/fiber/v2/log"
 	"github.com/joho/godotenv"
 )
 
+// ModeLocal runs the application as a single binary with in-memory storage, an in-process queue and API key authentication
+const ModeLocal = "local"
+
+const (
+	// StorageCouchbase persists entities in Couchbase
+	StorageCouchbase = "couchbase"
+
+	// StoragePostgres persists entities in PostgreSQL
+	StoragePostgres = "postgres"
+
+	// StorageMemory keeps entities in memory so they are lost when the application stops
+	StorageMemory = "memory"
+)
+
+const (
+	// QueueGoogleCloudTasks delivers events over HTTP using Google Cloud Tasks
+	QueueGoogleCloudTasks = "google"
+
+	// QueueMemory delivers events to the listeners using a pool of workers in the same process
+	QueueMemory = "memory"
+
+	// QueueRedis delivers events to all the instances of the application using Redis Streams
+	QueueRedis = "redis"
+
+	// QueueNats delivers events to all the instances of the application using NATS JetStream
+	QueueNats = "nats"
+)
+
 // Configuration is a struct that holds the configuration for the application.
 type Configuration struct {
-	UseOpenTelemetryLogger bool `env:"USE_OPEN_TELEMETRY_LOGGER"`
+	UseOpenTelemetryLogger   bool          `env:"USE_OPEN_TELEMETRY_LOGGER"`
+	Mode                     string        `env:"APP_MODE" envDefault:"cloud"`
+	StorageBackend           string        `env:"STORAGE_BACKEND"`
+	APIKey                   string        `env:"API_KEY"`
+	APIKeyUserID             string        `env:"API_KEY_USER_ID" envDefault:"user_local"`
+	APIKeyUserEmail          string        `env:"API_KEY_USER_EMAIL" envDefault:"local@httpmock.dev"`
+	QueueBackend             string        `env:"QUEUE_BACKEND"`
+	QueueWorkers             int           `env:"QUEUE_WORKERS" envDefault:"8"`
+	QueueBufferSize          int           `env:"QUEUE_BUFFER_SIZE" envDefault:"1000"`
+	QueueMaxAttempts         int           `env:"QUEUE_MAX_ATTEMPTS" envDefault:"5"`
+	QueueRetryDelay          time.Duration `env:"QUEUE_RETRY_DELAY" envDefault:"30s"`
+	QueueStream              string        `env:"QUEUE_STREAM" envDefault:"httpmock-events"`
+	QueueGroup               string        `env:"QUEUE_GROUP" envDefault:"httpmock-api"`
+	EventListenerMaxAttempts uint          `env:"EVENT_LISTENER_MAX_ATTEMPTS" envDefault:"3"`
+	EventListenerBackoff     time.Duration `env:"EVENT_LISTENER_BACKOFF" envDefault:"1s"`
+	AdminUserIDs             []string      `env:"ADMIN_USER_IDS" envSeparator:","`
+	DisableSubscriptionLimit bool          `env:"DISABLE_SUBSCRIPTION_LIMITS"`
+	RequestHardLimitPercent  uint          `env:"REQUEST_HARD_LIMIT_PERCENT" envDefault:"110"`
+	AllowPrivateNetworks     bool          `env:"ALLOW_PRIVATE_NETWORKS"`
+}
+
+// IsLocalMode checks if the application runs without any cloud service
+func (config *Configuration) IsLocalMode() bool {
+	return config.Mode == ModeLocal
 }
 
 // LoadEnv will read your .env file(s) and load them into ENV for this process.
@@ -17,3 +71,38 @@ func LoadEnv(filenames ...string) {
 		log.Fatalf("Fatal: cannot load .env file: %v", err)
 	}
 }
+
+// Storage returns the backend used to persist entities. It defaults to StorageMemory in local mode and StorageCouchbase otherwise.
+func (config *Configuration) Storage() string {
+	if config.StorageBackend != "" {
+		return config.StorageBackend
+	}
+	if config.IsLocalMode() {
+		return StorageMemory
+	}
+	return StorageCouchbase
+}
+
+// Queue returns the backend used to deliver events. It defaults to QueueMemory in local mode and QueueGoogleCloudTasks otherwise.
+func (config *Configuration) Queue() string {
+	if config.QueueBackend != "" {
+		return config.QueueBackend
+	}
+	if config.IsLocalMode() {
+		return QueueMemory
+	}
+	return QueueGoogleCloudTasks
+}
+
+// SubscriptionLimitsEnabled checks if the limits of the subscription plans are enforced. They are never enforced in local mode.
+func (config *Configuration) SubscriptionLimitsEnabled() bool {
+	return !config.IsLocalMode() && !config.DisableSubscriptionLimit
+}
+
+// IsAdmin checks if a user can manage the application e.g. replay dead letters. The API key user is an admin in local mode.
+func (config *Configuration) IsAdmin(userID string) bool {
+	if config.IsLocalMode() && userID == config.APIKeyUserID {
+		return true
+	}
+	return slices.Contains(config.AdminUserIDs, userID)
+}
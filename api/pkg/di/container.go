@@ -7,13 +7,16 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/clerk/clerk-sdk-go/v2/jwks"
 	"github.com/pusher/pusher-http-go/v5"
+	"github.com/valyala/fasthttp"
 
 	"github.com/NdoleStudio/httpmock/pkg/listeners"
 
@@ -35,6 +38,7 @@ import (
 	"github.com/clerk/clerk-sdk-go/v2"
 
 	"github.com/NdoleStudio/go-otelroundtripper"
+	"github.com/NdoleStudio/httpmock/pkg/entities"
 	"github.com/NdoleStudio/httpmock/pkg/handlers"
 	"github.com/NdoleStudio/httpmock/pkg/middlewares"
 	"github.com/NdoleStudio/httpmock/pkg/queue"
@@ -44,6 +48,10 @@ import (
 	"github.com/NdoleStudio/httpmock/pkg/validators"
 	"github.com/NdoleStudio/lemonsqueezy-go"
 	"github.com/hashicorp/go-retryablehttp"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/redis/go-redis/v9"
 
 	otelMetric "go.opentelemetry.io/otel/metric"
 
@@ -73,13 +81,28 @@ var configuration *Configuration
 
 // Container is used to resolve services at runtime
 type Container struct {
-	projectID       string
-	version         string
-	cluster         *gocb.Cluster
-	bucket          *gocb.Bucket
-	app             *fiber.App
-	eventDispatcher *services.EventDispatcher
-	logger          telemetry.Logger
+	projectID                        string
+	version                          string
+	cluster                          *gocb.Cluster
+	bucket                           *gocb.Bucket
+	postgres                         *pgxpool.Pool
+	app                              *fiber.App
+	eventDispatcher                  *services.EventDispatcher
+	eventBroker                      *services.EventBroker
+	eventsQueue                      queue.Client
+	eventsQueueWorker                queue.Worker
+	redis                            *redis.Client
+	natsJetStream                    jetstream.JetStream
+	projectRepository                repositories.ProjectRepository
+	projectEndpointRepository        repositories.ProjectEndpointRepository
+	projectEndpointRequestRepository repositories.ProjectEndpointRequestRepository
+	callbackAttemptRepository        repositories.ProjectEndpointCallbackAttemptRepository
+	webhookRepository                repositories.ProjectWebhookRepository
+	webhookDeliveryRepository        repositories.ProjectWebhookDeliveryRepository
+	userRepository                   repositories.UserRepository
+	deadLetterRepository             repositories.DeadLetterRepository
+	userUsageRepository              repositories.UserUsageRepository
+	logger                           telemetry.Logger
 }
 
 // NewLiteContainer creates a Container without any routes or listeners
@@ -111,7 +134,9 @@ func NewContainer(projectID string, version string) (container *Container) {
 		logger:    logger(3).WithCodeNamespace(fmt.Sprintf("%T", container)),
 	}
 
-	container.InitializeTraceProvider()
+	if !Config().IsLocalMode() || os.Getenv("AXIOM_API_KEY") != "" {
+		container.InitializeTraceProvider()
+	}
 
 	return container
 }
@@ -126,7 +151,10 @@ func (container *Container) App() (app *fiber.App) {
 
 	app = fiber.New()
 
-	app.Use(otelfiber.Middleware())
+	// the size of a streamed response is only known when the stream ends so the streams are not instrumented
+	app.Use(otelfiber.Middleware(otelfiber.WithNext(func(c *fiber.Ctx) bool {
+		return strings.HasPrefix(c.Path(), "/v1/projects/") && strings.HasSuffix(c.Path(), "/requests/stream")
+	})))
 
 	ctxLogger := logger(2).WithCodeNamespace(fmt.Sprintf("%T", container))
 	app.Use(fiberLogger.New(
@@ -144,6 +172,7 @@ func (container *Container) App() (app *fiber.App) {
 		container.Logger(),
 		os.Getenv("APP_HOSTNAME"),
 		container.ProjectEndpointRequestService(),
+		container.SubscriptionService(),
 		container.ServerHandler().Handle,
 		container.EchoHandler().Handle,
 	))
@@ -151,13 +180,22 @@ func (container *Container) App() (app *fiber.App) {
 
 	container.app = app
 
-	container.EnsureDBCollections()
-	container.EnsureDBIndexes()
+	switch Config().Storage() {
+	case StorageCouchbase:
+		container.EnsureDBCollections()
+		container.EnsureDBIndexes()
+	case StoragePostgres:
+		container.MigratePostgres()
+	}
 
 	container.RegisterEventRoutes()
 	container.RegisterProjectRoutes()
 	container.RegisterProjectEndpointRoutes()
 	container.RegisterProjectEndpointRequestRoutes()
+	container.RegisterProjectWebhookRoutes()
+	container.RegisterProjectRequestRoutes()
+	container.RegisterDeadLetterRoutes()
+	container.RegisterUserRoutes()
 	container.RegisterEchoRoutes()
 	container.RegisterServerRoutes()
 
@@ -168,8 +206,26 @@ func (container *Container) App() (app *fiber.App) {
 	container.RegisterSwaggerRoutes()
 
 	container.RegisterProjectEndpointRequestListeners()
+	container.RegisterProjectEndpointCallbackListeners()
+	container.RegisterProjectWebhookListeners()
+	container.RegisterEventBrokerListeners()
 	container.RegisterProjectEndpointListeners()
-	container.RegisterNotificationListeners()
+	container.RegisterProjectListeners()
+	container.RegisterSubscriptionListeners()
+	container.RegisterUserListeners()
+	if !Config().IsLocalMode() || os.Getenv("PUSHER_APP_ID") != "" {
+		container.RegisterNotificationListeners()
+	}
+
+	if worker := container.EventsQueueWorker(); worker != nil {
+		if err := worker.Start(context.Background()); err != nil {
+			container.logger.Fatal(stacktrace.Propagate(err, "cannot start the events queue worker"))
+		}
+	}
+
+	if err := container.EventBroker().Start(context.Background()); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, "cannot start the event broker"))
+	}
 
 	return app
 }
@@ -183,6 +239,50 @@ func (container *Container) GoogleAuthMiddlewares(audience string, subject strin
 	}
 }
 
+// AuthMiddlewares creates the middlewares for authenticated requests using an API key in local mode and Clerk otherwise
+func (container *Container) AuthMiddlewares() []fiber.Handler {
+	if Config().IsLocalMode() {
+		return container.APIKeyAuthMiddlewares()
+	}
+	return container.ClerkBearerAuthMiddlewares()
+}
+
+// APIKeyAuthMiddlewares creates router for requests authenticated with a static API key
+func (container *Container) APIKeyAuthMiddlewares() []fiber.Handler {
+	container.logger.Debug("creating APIKeyAuthRouter")
+	if Config().APIKey == "" {
+		container.logger.Warn(stacktrace.NewError("the [API_KEY] environment variable is empty so all authenticated requests will be rejected"))
+	}
+
+	return []fiber.Handler{
+		middlewares.APIKeyAuth(
+			container.Logger().WithCodeNamespace(fmt.Sprintf("%T", middlewares.APIKeyAuth)),
+			container.Tracer(),
+			Config().APIKey,
+			entities.AuthUser{
+				ID:    entities.UserID(Config().APIKeyUserID),
+				Email: Config().APIKeyUserEmail,
+			},
+		),
+		container.AuthenticatedMiddleware(),
+	}
+}
+
+// AdminMiddlewares creates the middlewares for requests which can only be made by admins
+func (container *Container) AdminMiddlewares() []fiber.Handler {
+	container.logger.Debug("creating AdminMiddlewares")
+	return append(
+		container.AuthMiddlewares(),
+		middlewares.Admin(
+			container.Logger().WithCodeNamespace(fmt.Sprintf("%T", middlewares.Admin)),
+			container.Tracer(),
+			func(userID entities.UserID) bool {
+				return Config().IsAdmin(string(userID))
+			},
+		),
+	)
+}
+
 // AuthenticatedMiddleware creates a new instance of middlewares.Authenticated
 func (container *Container) AuthenticatedMiddleware() fiber.Handler {
 	container.logger.Debug("creating middlewares.Authenticated")
@@ -245,6 +345,37 @@ func (container *Container) Bucket() *gocb.Bucket {
 	return container.bucket
 }
 
+// Postgres returns the PostgreSQL connection pool
+func (container *Container) Postgres() *pgxpool.Pool {
+	if container.postgres != nil {
+		return container.postgres
+	}
+
+	container.logger.Debug("creating *pgxpool.Pool")
+
+	pool, err := pgxpool.New(context.Background(), os.Getenv("DATABASE_URL"))
+	if err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, "cannot create PostgreSQL connection pool"))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err = pool.Ping(ctx); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, "cannot connect to PostgreSQL"))
+	}
+
+	container.postgres = pool
+	return pool
+}
+
+// MigratePostgres applies the pending PostgreSQL schema migrations
+func (container *Container) MigratePostgres() {
+	container.logger.Debug("applying PostgreSQL migrations")
+	if err := repositories.MigratePostgres(context.Background(), container.Logger(), container.Postgres()); err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, "cannot apply PostgreSQL migrations"))
+	}
+}
+
 // CouchbaseDBScope returns the default Couchbase scope name
 func (container *Container) CouchbaseDBScope() string {
 	return "_default"
@@ -265,17 +396,42 @@ func (container *Container) EndpointRequestsCollection() *gocb.Collection {
 	return container.Bucket().Scope(container.CouchbaseDBScope()).Collection("project_endpoint_requests")
 }
 
+// CallbackAttemptsCollection returns the project_endpoint_callback_attempts collection
+func (container *Container) CallbackAttemptsCollection() *gocb.Collection {
+	return container.Bucket().Scope(container.CouchbaseDBScope()).Collection("project_endpoint_callback_attempts")
+}
+
+// WebhooksCollection returns the project_webhooks collection
+func (container *Container) WebhooksCollection() *gocb.Collection {
+	return container.Bucket().Scope(container.CouchbaseDBScope()).Collection("project_webhooks")
+}
+
+// WebhookDeliveriesCollection returns the project_webhook_deliveries collection
+func (container *Container) WebhookDeliveriesCollection() *gocb.Collection {
+	return container.Bucket().Scope(container.CouchbaseDBScope()).Collection("project_webhook_deliveries")
+}
+
 // UsersCollection returns the users collection
 func (container *Container) UsersCollection() *gocb.Collection {
 	return container.Bucket().Scope(container.CouchbaseDBScope()).Collection("users")
 }
 
+// DeadLettersCollection returns the dead_letters collection
+func (container *Container) DeadLettersCollection() *gocb.Collection {
+	return container.Bucket().Scope(container.CouchbaseDBScope()).Collection("dead_letters")
+}
+
+// UserUsagesCollection returns the user_usages collection
+func (container *Container) UserUsagesCollection() *gocb.Collection {
+	return container.Bucket().Scope(container.CouchbaseDBScope()).Collection("user_usages")
+}
+
 // EnsureDBCollections creates Couchbase collections if they don't exist
 func (container *Container) EnsureDBCollections() {
 	container.logger.Debug("ensuring Couchbase collections exist")
 	collections := container.Bucket().CollectionsV2()
 
-	collectionNames := []string{"projects", "project_endpoints", "project_endpoint_requests", "users"}
+	collectionNames := []string{"projects", "project_endpoints", "project_endpoint_requests", "project_endpoint_callback_attempts", "project_webhooks", "project_webhook_deliveries", "users", "dead_letters", "user_usages"}
 	for _, name := range collectionNames {
 		err := collections.CreateCollection(container.CouchbaseDBScope(), name, nil, nil)
 		if err != nil && !errors.Is(err, gocb.ErrCollectionExists) {
@@ -298,7 +454,19 @@ func (container *Container) EnsureDBIndexes() {
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_requests_user_endpoint ON `%s`.`%s`.`project_endpoint_requests`(user_id, project_endpoint_id, id DESC)", bucket, container.CouchbaseDBScope()),
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_requests_user_project_created ON `%s`.`%s`.`project_endpoint_requests`(user_id, project_id, created_at)", bucket, container.CouchbaseDBScope()),
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_requests_user_endpoint_created ON `%s`.`%s`.`project_endpoint_requests`(user_id, project_endpoint_id, created_at)", bucket, container.CouchbaseDBScope()),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_requests_user_id ON `%s`.`%s`.`project_endpoint_requests`(user_id, id)", bucket, container.CouchbaseDBScope()),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_requests_user_project_id ON `%s`.`%s`.`project_endpoint_requests`(user_id, project_id, id DESC)", bucket, container.CouchbaseDBScope()),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_callback_attempts_user_request ON `%s`.`%s`.`project_endpoint_callback_attempts`(user_id, project_endpoint_request_id, created_at)", bucket, container.CouchbaseDBScope()),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_callback_attempts_user_project ON `%s`.`%s`.`project_endpoint_callback_attempts`(user_id, project_id)", bucket, container.CouchbaseDBScope()),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_webhooks_user_project ON `%s`.`%s`.`project_webhooks`(user_id, project_id, created_at)", bucket, container.CouchbaseDBScope()),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_user_webhook ON `%s`.`%s`.`project_webhook_deliveries`(user_id, project_webhook_id, created_at DESC)", bucket, container.CouchbaseDBScope()),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_user_project ON `%s`.`%s`.`project_webhook_deliveries`(user_id, project_id)", bucket, container.CouchbaseDBScope()),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_user_created ON `%s`.`%s`.`project_webhook_deliveries`(user_id, created_at)", bucket, container.CouchbaseDBScope()),
 		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_users_subscription_id ON `%s`.`%s`.`users`(subscription_id)", bucket, container.CouchbaseDBScope()),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_user_usages_user_id ON `%s`.`%s`.`user_usages`(user_id)", bucket, container.CouchbaseDBScope()),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_dead_letters_status_created ON `%s`.`%s`.`dead_letters`(status, created_at DESC)", bucket, container.CouchbaseDBScope()),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_dead_letters_created ON `%s`.`%s`.`dead_letters`(created_at DESC)", bucket, container.CouchbaseDBScope()),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_dead_letters_user ON `%s`.`%s`.`dead_letters`(user_id)", bucket, container.CouchbaseDBScope()),
 	}
 
 	for _, query := range indexes {
@@ -351,19 +519,43 @@ func (container *Container) ClerkBearerAuthMiddlewares() []fiber.Handler {
 // RegisterProjectRoutes registers routes for the /projects prefix
 func (container *Container) RegisterProjectRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.ProjectHandler{}))
-	container.ProjectHandler().RegisterRoutes(container.App(), container.ClerkBearerAuthMiddlewares())
+	container.ProjectHandler().RegisterRoutes(container.App(), container.AuthMiddlewares())
 }
 
 // RegisterProjectEndpointRoutes registers routes for the /projects/:projectId/endpoints prefix
 func (container *Container) RegisterProjectEndpointRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.ProjectEndpointHandler{}))
-	container.ProjectEndpointHandler().RegisterRoutes(container.App(), container.ClerkBearerAuthMiddlewares())
+	container.ProjectEndpointHandler().RegisterRoutes(container.App(), container.AuthMiddlewares())
 }
 
 // RegisterProjectEndpointRequestRoutes registers routes for the /projects/:projectId/endpoints/:projectEndpointId/requests prefix
 func (container *Container) RegisterProjectEndpointRequestRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.ProjectEndpointRequestHandler{}))
-	container.ProjectEndpointRequestHandler().RegisterRoutes(container.App(), container.ClerkBearerAuthMiddlewares())
+	container.ProjectEndpointRequestHandler().RegisterRoutes(container.App(), container.AuthMiddlewares())
+}
+
+// RegisterProjectWebhookRoutes registers routes for the /projects/:projectId/webhooks prefix
+func (container *Container) RegisterProjectWebhookRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.ProjectWebhookHandler{}))
+	container.ProjectWebhookHandler().RegisterRoutes(container.App(), container.AuthMiddlewares())
+}
+
+// RegisterProjectRequestRoutes registers routes for the /projects/:projectId/requests prefix
+func (container *Container) RegisterProjectRequestRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.ProjectRequestHandler{}))
+	container.ProjectRequestHandler().RegisterRoutes(container.App(), container.AuthMiddlewares())
+}
+
+// RegisterDeadLetterRoutes registers routes for the /dead-letters
+func (container *Container) RegisterDeadLetterRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.DeadLetterHandler{}))
+	container.DeadLetterHandler().RegisterRoutes(container.App(), container.AdminMiddlewares())
+}
+
+// RegisterUserRoutes registers routes for the /users prefix
+func (container *Container) RegisterUserRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.UserHandler{}))
+	container.UserHandler().RegisterRoutes(container.App(), container.AuthMiddlewares())
 }
 
 // RegisterEchoRoutes registers routes for the /echo
@@ -386,6 +578,7 @@ func (container *Container) ProjectHandler() (handler *handlers.ProjectHandler)
 		container.Tracer(),
 		container.ProjectHandlerValidator(),
 		container.ProjectService(),
+		container.ProjectExportService(),
 	)
 }
 
@@ -428,6 +621,54 @@ func (container *Container) ProjectEndpointRequestHandler() (handler *handlers.P
 		container.ProjectEndpointRequestHandlerValidator(),
 		container.ProjectEndpointRequestService(),
 		container.ProjectEndpointService(),
+		container.ProjectEndpointCallbackService(),
+	)
+}
+
+// ProjectWebhookHandler creates a new instance of handlers.ProjectWebhookHandler
+func (container *Container) ProjectWebhookHandler() (handler *handlers.ProjectWebhookHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return handlers.NewProjectWebhookHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.ProjectWebhookHandlerValidator(),
+		container.ProjectWebhookService(),
+		container.ProjectService(),
+	)
+}
+
+// ProjectRequestHandler creates a new instance of handlers.ProjectRequestHandler
+func (container *Container) ProjectRequestHandler() (handler *handlers.ProjectRequestHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return handlers.NewProjectRequestHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.ProjectRequestHandlerValidator(),
+		container.ProjectService(),
+		container.ProjectEndpointRequestService(),
+		container.EventBroker(),
+	)
+}
+
+// DeadLetterHandler creates a new instance of handlers.DeadLetterHandler
+func (container *Container) DeadLetterHandler() (handler *handlers.DeadLetterHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return handlers.NewDeadLetterHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.DeadLetterHandlerValidator(),
+		container.DeadLetterService(),
+	)
+}
+
+// UserHandler creates a new instance of handlers.UserHandler
+func (container *Container) UserHandler() (handler *handlers.UserHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return handlers.NewUserHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.SubscriptionService(),
+		container.UserService(),
 	)
 }
 
@@ -437,12 +678,48 @@ func (container *Container) RegisterProjectEndpointRequestListeners() {
 	container.ProjectEndpointRequestListener().Register(container.EventDispatcher())
 }
 
+// RegisterProjectEndpointCallbackListeners registers event listeners
+func (container *Container) RegisterProjectEndpointCallbackListeners() {
+	container.logger.Debug(fmt.Sprintf("registering %T", &listeners.ProjectEndpointCallbackListener{}))
+	container.ProjectEndpointCallbackListener().Register(container.EventDispatcher())
+}
+
+// RegisterProjectWebhookListeners registers event listeners
+func (container *Container) RegisterProjectWebhookListeners() {
+	container.logger.Debug(fmt.Sprintf("registering %T", &listeners.ProjectWebhookListener{}))
+	container.ProjectWebhookListener().Register(container.EventDispatcher())
+}
+
+// RegisterEventBrokerListeners registers event listeners
+func (container *Container) RegisterEventBrokerListeners() {
+	container.logger.Debug(fmt.Sprintf("registering %T", &listeners.EventBrokerListener{}))
+	container.EventBrokerListener().Register(container.EventDispatcher())
+}
+
 // RegisterProjectEndpointListeners registers event listeners
 func (container *Container) RegisterProjectEndpointListeners() {
 	container.logger.Debug(fmt.Sprintf("registering %T", &listeners.ProjectEndpointListener{}))
 	container.ProjectEndpointListener().Register(container.EventDispatcher())
 }
 
+// RegisterProjectListeners registers event listeners
+func (container *Container) RegisterProjectListeners() {
+	container.logger.Debug(fmt.Sprintf("registering %T", &listeners.ProjectListener{}))
+	container.ProjectListener().Register(container.EventDispatcher())
+}
+
+// RegisterSubscriptionListeners registers event listeners
+func (container *Container) RegisterSubscriptionListeners() {
+	container.logger.Debug(fmt.Sprintf("registering %T", &listeners.SubscriptionListener{}))
+	container.SubscriptionListener().Register(container.EventDispatcher())
+}
+
+// RegisterUserListeners registers event listeners
+func (container *Container) RegisterUserListeners() {
+	container.logger.Debug(fmt.Sprintf("registering %T", &listeners.UserListener{}))
+	container.UserListener().Register(container.EventDispatcher())
+}
+
 // RegisterNotificationListeners registers event listeners
 func (container *Container) RegisterNotificationListeners() {
 	container.logger.Debug(fmt.Sprintf("registering %T", &listeners.NotificationListener{}))
@@ -459,6 +736,36 @@ func (container *Container) ProjectEndpointRequestListener() (handler *listeners
 	)
 }
 
+// ProjectEndpointCallbackListener creates a new instance of listeners.ProjectEndpointCallbackListener
+func (container *Container) ProjectEndpointCallbackListener() (handler *listeners.ProjectEndpointCallbackListener) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return listeners.NewProjectEndpointCallbackListener(
+		container.Logger(),
+		container.Tracer(),
+		container.ProjectEndpointCallbackService(),
+	)
+}
+
+// ProjectWebhookListener creates a new instance of listeners.ProjectWebhookListener
+func (container *Container) ProjectWebhookListener() (handler *listeners.ProjectWebhookListener) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return listeners.NewProjectWebhookListener(
+		container.Logger(),
+		container.Tracer(),
+		container.ProjectWebhookService(),
+	)
+}
+
+// EventBrokerListener creates a new instance of listeners.EventBrokerListener
+func (container *Container) EventBrokerListener() (handler *listeners.EventBrokerListener) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return listeners.NewEventBrokerListener(
+		container.Logger(),
+		container.Tracer(),
+		container.EventBroker(),
+	)
+}
+
 // NotificationListener creates a new instance of listeners.NotificationListener
 func (container *Container) NotificationListener() (handler *listeners.NotificationListener) {
 	container.logger.Debug(fmt.Sprintf("creating %T", handler))
@@ -479,6 +786,36 @@ func (container *Container) ProjectEndpointListener() (handler *listeners.Projec
 	)
 }
 
+// ProjectListener creates a new instance of listeners.ProjectListener
+func (container *Container) ProjectListener() (handler *listeners.ProjectListener) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return listeners.NewProjectListener(
+		container.Logger(),
+		container.Tracer(),
+		container.ProjectService(),
+	)
+}
+
+// SubscriptionListener creates a new instance of listeners.SubscriptionListener
+func (container *Container) SubscriptionListener() (handler *listeners.SubscriptionListener) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return listeners.NewSubscriptionListener(
+		container.Logger(),
+		container.Tracer(),
+		container.SubscriptionService(),
+	)
+}
+
+// UserListener creates a new instance of listeners.UserListener
+func (container *Container) UserListener() (handler *listeners.UserListener) {
+	container.logger.Debug(fmt.Sprintf("creating %T", handler))
+	return listeners.NewUserListener(
+		container.Logger(),
+		container.Tracer(),
+		container.UserService(),
+	)
+}
+
 // ProjectHandlerValidator creates a new instance of validators.ProjectHandlerValidator
 func (container *Container) ProjectHandlerValidator() (validator *validators.ProjectHandlerValidator) {
 	container.logger.Debug(fmt.Sprintf("creating %T", validator))
@@ -508,6 +845,45 @@ func (container *Container) ProjectEndpointRequestHandlerValidator() (validator
 	)
 }
 
+// ProjectRequestHandlerValidator creates a new instance of validators.ProjectRequestHandlerValidator
+func (container *Container) ProjectRequestHandlerValidator() (validator *validators.ProjectRequestHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewProjectRequestHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// ProjectWebhookHandlerValidator creates a new instance of validators.ProjectWebhookHandlerValidator
+func (container *Container) ProjectWebhookHandlerValidator() (validator *validators.ProjectWebhookHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewProjectWebhookHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+		container.ProjectWebhookRepository(),
+	)
+}
+
+// DeadLetterHandlerValidator creates a new instance of validators.DeadLetterHandlerValidator
+func (container *Container) DeadLetterHandlerValidator() (validator *validators.DeadLetterHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewDeadLetterHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// DeadLetterService creates a new instance of services.DeadLetterService
+func (container *Container) DeadLetterService() (service *services.DeadLetterService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewDeadLetterService(
+		container.Logger(),
+		container.Tracer(),
+		container.DeadLetterRepository(),
+		container.EventDispatcher(),
+	)
+}
+
 // ProjectService creates a new instance of services.ProjectService
 func (container *Container) ProjectService() (service *services.ProjectService) {
 	container.logger.Debug(fmt.Sprintf("creating %T", service))
@@ -516,7 +892,57 @@ func (container *Container) ProjectService() (service *services.ProjectService)
 		container.Tracer(),
 		container.EventDispatcher(),
 		container.ProjectEndpointRequestRepository(),
+		container.ProjectEndpointRepository(),
+		container.ProjectEndpointCallbackAttemptRepository(),
+		container.ProjectWebhookRepository(),
+		container.ProjectWebhookDeliveryRepository(),
+		container.ProjectRepository(),
+		container.SubscriptionService(),
+	)
+}
+
+// SubscriptionService creates a new instance of services.SubscriptionService
+func (container *Container) SubscriptionService() (service *services.SubscriptionService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewSubscriptionService(
+		container.Logger(),
+		container.Tracer(),
+		container.UserRepository(),
+		container.ProjectRepository(),
+		container.ProjectEndpointRepository(),
+		container.UserUsageRepository(),
+		container.EventDispatcher(),
+		Config().SubscriptionLimitsEnabled(),
+		Config().RequestHardLimitPercent,
+	)
+}
+
+// UserService creates a new instance of services.UserService
+func (container *Container) UserService() (service *services.UserService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewUserService(
+		container.Logger(),
+		container.Tracer(),
+		container.UserRepository(),
+		container.UserUsageRepository(),
+		container.DeadLetterRepository(),
+		container.ProjectRepository(),
+		container.ProjectService(),
+		container.LemonsqueezyService(),
+		container.EventDispatcher(),
+	)
+}
+
+// ProjectExportService creates a new instance of services.ProjectExportService
+func (container *Container) ProjectExportService() (service *services.ProjectExportService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewProjectExportService(
+		container.Logger(),
+		container.Tracer(),
+		os.Getenv("APP_HOSTNAME"),
 		container.ProjectRepository(),
+		container.ProjectEndpointRepository(),
+		container.ProjectEndpointRequestRepository(),
 	)
 }
 
@@ -528,6 +954,7 @@ func (container *Container) ProjectEndpointService() (service *services.ProjectE
 		container.Tracer(),
 		container.ProjectEndpointRepository(),
 		container.ProjectEndpointRequestRepository(),
+		container.SubscriptionService(),
 	)
 }
 
@@ -537,14 +964,57 @@ func (container *Container) ProjectEndpointRequestService() (service *services.P
 	return services.NewProjectEndpointRequestService(
 		container.Logger(),
 		container.Tracer(),
+		container.UpstreamHTTPClient(),
 		container.ProjectEndpointRepository(),
 		container.ProjectEndpointRequestRepository(),
+		container.ProjectRepository(),
+		container.ProjectEndpointService(),
+		container.SubscriptionService(),
+		container.ProjectEndpointCallbackService(),
+		container.EventDispatcher(),
+	)
+}
+
+// ProjectEndpointCallbackService creates a new instance of services.ProjectEndpointCallbackService
+func (container *Container) ProjectEndpointCallbackService() (service *services.ProjectEndpointCallbackService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewProjectEndpointCallbackService(
+		container.Logger(),
+		container.Tracer(),
+		container.DeliveryHTTPClient("callbacks"),
+		container.ProjectEndpointCallbackAttemptRepository(),
+		container.EventDispatcher(),
+	)
+}
+
+// ProjectWebhookService creates a new instance of services.ProjectWebhookService
+func (container *Container) ProjectWebhookService() (service *services.ProjectWebhookService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewProjectWebhookService(
+		container.Logger(),
+		container.Tracer(),
+		container.DeliveryHTTPClient("webhooks"),
+		container.ProjectWebhookRepository(),
+		container.ProjectWebhookDeliveryRepository(),
+		container.SubscriptionService(),
 		container.EventDispatcher(),
 	)
 }
 
 // ProjectRepository registers a new instance of repositories.ProjectRepository
 func (container *Container) ProjectRepository() repositories.ProjectRepository {
+	switch Config().Storage() {
+	case StoragePostgres:
+		container.logger.Debug("creating PostgreSQL repositories.ProjectRepository")
+		return repositories.NewPostgresProjectRepository(container.Logger(), container.Tracer(), container.Postgres())
+	case StorageMemory:
+		if container.projectRepository == nil {
+			container.logger.Debug("creating in-memory repositories.ProjectRepository")
+			container.projectRepository = repositories.NewMemoryProjectRepository(container.Logger(), container.Tracer())
+		}
+		return container.projectRepository
+	}
+
 	container.logger.Debug("creating Couchbase repositories.ProjectRepository")
 	return repositories.NewCouchbaseProjectRepository(
 		container.Logger(),
@@ -554,8 +1024,66 @@ func (container *Container) ProjectRepository() repositories.ProjectRepository {
 	)
 }
 
+// DeadLetterRepository registers a new instance of repositories.DeadLetterRepository
+func (container *Container) DeadLetterRepository() repositories.DeadLetterRepository {
+	switch Config().Storage() {
+	case StoragePostgres:
+		container.logger.Debug("creating PostgreSQL repositories.DeadLetterRepository")
+		return repositories.NewPostgresDeadLetterRepository(container.Logger(), container.Tracer(), container.Postgres())
+	case StorageMemory:
+		if container.deadLetterRepository == nil {
+			container.logger.Debug("creating in-memory repositories.DeadLetterRepository")
+			container.deadLetterRepository = repositories.NewMemoryDeadLetterRepository(container.Logger(), container.Tracer())
+		}
+		return container.deadLetterRepository
+	}
+
+	container.logger.Debug("creating Couchbase repositories.DeadLetterRepository")
+	return repositories.NewCouchbaseDeadLetterRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DeadLettersCollection(),
+		container.Cluster(),
+	)
+}
+
+// UserUsageRepository registers a new instance of repositories.UserUsageRepository
+func (container *Container) UserUsageRepository() repositories.UserUsageRepository {
+	switch Config().Storage() {
+	case StoragePostgres:
+		container.logger.Debug("creating PostgreSQL repositories.UserUsageRepository")
+		return repositories.NewPostgresUserUsageRepository(container.Logger(), container.Tracer(), container.Postgres())
+	case StorageMemory:
+		if container.userUsageRepository == nil {
+			container.logger.Debug("creating in-memory repositories.UserUsageRepository")
+			container.userUsageRepository = repositories.NewMemoryUserUsageRepository(container.Logger(), container.Tracer())
+		}
+		return container.userUsageRepository
+	}
+
+	container.logger.Debug("creating Couchbase repositories.UserUsageRepository")
+	return repositories.NewCouchbaseUserUsageRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.UserUsagesCollection(),
+		container.Cluster(),
+	)
+}
+
 // ProjectEndpointRepository registers a new instance of repositories.ProjectEndpointRepository
 func (container *Container) ProjectEndpointRepository() repositories.ProjectEndpointRepository {
+	switch Config().Storage() {
+	case StoragePostgres:
+		container.logger.Debug("creating PostgreSQL repositories.ProjectEndpointRepository")
+		return repositories.NewPostgresProjectEndpointRepository(container.Logger(), container.Tracer(), container.Postgres())
+	case StorageMemory:
+		if container.projectEndpointRepository == nil {
+			container.logger.Debug("creating in-memory repositories.ProjectEndpointRepository")
+			container.projectEndpointRepository = repositories.NewMemoryProjectEndpointRepository(container.Logger(), container.Tracer())
+		}
+		return container.projectEndpointRepository
+	}
+
 	container.logger.Debug("creating Couchbase repositories.ProjectEndpointRepository")
 	return repositories.NewCouchbaseProjectEndpointRepository(
 		container.Logger(),
@@ -567,6 +1095,18 @@ func (container *Container) ProjectEndpointRepository() repositories.ProjectEndp
 
 // ProjectEndpointRequestRepository registers a new instance of repositories.ProjectEndpointRequestRepository
 func (container *Container) ProjectEndpointRequestRepository() repositories.ProjectEndpointRequestRepository {
+	switch Config().Storage() {
+	case StoragePostgres:
+		container.logger.Debug("creating PostgreSQL repositories.ProjectEndpointRequestRepository")
+		return repositories.NewPostgresProjectEndpointRequestRepository(container.Logger(), container.Tracer(), container.Postgres())
+	case StorageMemory:
+		if container.projectEndpointRequestRepository == nil {
+			container.logger.Debug("creating in-memory repositories.ProjectEndpointRequestRepository")
+			container.projectEndpointRequestRepository = repositories.NewMemoryProjectEndpointRequestRepository(container.Logger(), container.Tracer())
+		}
+		return container.projectEndpointRequestRepository
+	}
+
 	container.logger.Debug("creating Couchbase repositories.ProjectEndpointRequestRepository")
 	return repositories.NewCouchbaseProjectEndpointRequestRepository(
 		container.Logger(),
@@ -576,17 +1116,234 @@ func (container *Container) ProjectEndpointRequestRepository() repositories.Proj
 	)
 }
 
+// ProjectEndpointCallbackAttemptRepository registers a new instance of repositories.ProjectEndpointCallbackAttemptRepository
+func (container *Container) ProjectEndpointCallbackAttemptRepository() repositories.ProjectEndpointCallbackAttemptRepository {
+	switch Config().Storage() {
+	case StoragePostgres:
+		container.logger.Debug("creating PostgreSQL repositories.ProjectEndpointCallbackAttemptRepository")
+		return repositories.NewPostgresProjectEndpointCallbackAttemptRepository(container.Logger(), container.Tracer(), container.Postgres())
+	case StorageMemory:
+		if container.callbackAttemptRepository == nil {
+			container.logger.Debug("creating in-memory repositories.ProjectEndpointCallbackAttemptRepository")
+			container.callbackAttemptRepository = repositories.NewMemoryProjectEndpointCallbackAttemptRepository(container.Logger(), container.Tracer())
+		}
+		return container.callbackAttemptRepository
+	}
+
+	container.logger.Debug("creating Couchbase repositories.ProjectEndpointCallbackAttemptRepository")
+	return repositories.NewCouchbaseProjectEndpointCallbackAttemptRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.CallbackAttemptsCollection(),
+		container.Cluster(),
+	)
+}
+
+// ProjectWebhookRepository registers a new instance of repositories.ProjectWebhookRepository
+func (container *Container) ProjectWebhookRepository() repositories.ProjectWebhookRepository {
+	switch Config().Storage() {
+	case StoragePostgres:
+		container.logger.Debug("creating PostgreSQL repositories.ProjectWebhookRepository")
+		return repositories.NewPostgresProjectWebhookRepository(container.Logger(), container.Tracer(), container.Postgres())
+	case StorageMemory:
+		if container.webhookRepository == nil {
+			container.logger.Debug("creating in-memory repositories.ProjectWebhookRepository")
+			container.webhookRepository = repositories.NewMemoryProjectWebhookRepository(container.Logger(), container.Tracer())
+		}
+		return container.webhookRepository
+	}
+
+	container.logger.Debug("creating Couchbase repositories.ProjectWebhookRepository")
+	return repositories.NewCouchbaseProjectWebhookRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.WebhooksCollection(),
+		container.Cluster(),
+	)
+}
+
+// ProjectWebhookDeliveryRepository registers a new instance of repositories.ProjectWebhookDeliveryRepository
+func (container *Container) ProjectWebhookDeliveryRepository() repositories.ProjectWebhookDeliveryRepository {
+	switch Config().Storage() {
+	case StoragePostgres:
+		container.logger.Debug("creating PostgreSQL repositories.ProjectWebhookDeliveryRepository")
+		return repositories.NewPostgresProjectWebhookDeliveryRepository(container.Logger(), container.Tracer(), container.Postgres())
+	case StorageMemory:
+		if container.webhookDeliveryRepository == nil {
+			container.logger.Debug("creating in-memory repositories.ProjectWebhookDeliveryRepository")
+			container.webhookDeliveryRepository = repositories.NewMemoryProjectWebhookDeliveryRepository(container.Logger(), container.Tracer())
+		}
+		return container.webhookDeliveryRepository
+	}
+
+	container.logger.Debug("creating Couchbase repositories.ProjectWebhookDeliveryRepository")
+	return repositories.NewCouchbaseProjectWebhookDeliveryRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.WebhookDeliveriesCollection(),
+		container.Cluster(),
+	)
+}
+
 // EventsQueue creates a new instance of services.PushQueue
 func (container *Container) EventsQueue() queue.Client {
+	if container.eventsQueue != nil {
+		return container.eventsQueue
+	}
+
+	switch Config().Queue() {
+	case QueueRedis:
+		container.logger.Debug("creating redis queue.Client")
+		container.eventsQueue = queue.NewRedisQueue(container.Logger(), container.Tracer(), container.Redis(), Config().QueueStream)
+		return container.eventsQueue
+	case QueueNats:
+		container.logger.Debug("creating nats queue.Client")
+		container.eventsQueue = queue.NewNatsQueue(container.Logger(), container.Tracer(), container.NatsJetStream(), Config().QueueStream+".tasks")
+		return container.eventsQueue
+	case QueueMemory:
+		container.logger.Debug("creating in-memory queue.Client")
+		container.eventsQueue = queue.NewMemoryQueue(
+			container.Logger(),
+			container.Tracer(),
+			func(ctx context.Context, task *queue.Task) error {
+				return container.EventDispatcher().Consume(ctx, task)
+			},
+			queue.MemoryQueueConfig{
+				Workers:        Config().QueueWorkers,
+				BufferSize:     Config().QueueBufferSize,
+				MaxAttempts:    Config().QueueMaxAttempts,
+				InitialBackoff: 500 * time.Millisecond,
+				MaxBackoff:     30 * time.Second,
+			},
+		)
+		return container.eventsQueue
+	}
+
 	container.logger.Debug("creating queue.Client")
 
-	return queue.NewGooglePushQueue(
+	container.eventsQueue = queue.NewGooglePushQueue(
 		container.Logger(),
 		container.Tracer(),
 		container.CloudTasksClient(),
 		os.Getenv("EVENTS_QUEUE_NAME"),
 		os.Getenv("EVENTS_QUEUE_AUTH_EMAIL"),
 	)
+	return container.eventsQueue
+}
+
+// EventsQueueWorker creates the queue.Worker which consumes the events added to a shared queue. It is nil when the queue delivers the events itself.
+func (container *Container) EventsQueueWorker() queue.Worker {
+	if container.eventsQueueWorker != nil {
+		return container.eventsQueueWorker
+	}
+
+	consumer := func(ctx context.Context, task *queue.Task) error {
+		return container.EventDispatcher().Consume(ctx, task)
+	}
+
+	config := queue.WorkerConfig{
+		Group:       Config().QueueGroup,
+		Name:        container.hostname(),
+		Concurrency: Config().QueueWorkers,
+		MaxAttempts: Config().QueueMaxAttempts,
+		RetryDelay:  Config().QueueRetryDelay,
+	}
+
+	switch Config().Queue() {
+	case QueueRedis:
+		container.logger.Debug("creating redis queue.Worker")
+		config.DeadLetter = queue.RedisStreamKey(Config().QueueStream, "dead-letter")
+		container.eventsQueueWorker = queue.NewRedisQueueWorker(container.Logger(), container.Tracer(), container.Redis(), Config().QueueStream, config, consumer)
+	case QueueNats:
+		container.logger.Debug("creating nats queue.Worker")
+		config.DeadLetter = Config().QueueStream + ".dead-letter"
+		container.eventsQueueWorker = queue.NewNatsQueueWorker(
+			container.Logger(),
+			container.Tracer(),
+			container.NatsJetStream(),
+			Config().QueueStream,
+			Config().QueueStream+".tasks",
+			config,
+			consumer,
+		)
+	}
+
+	return container.eventsQueueWorker
+}
+
+// Redis creates a new instance of *redis.Client
+func (container *Container) Redis() *redis.Client {
+	if container.redis != nil {
+		return container.redis
+	}
+
+	container.logger.Debug("creating *redis.Client")
+
+	options, err := redis.ParseURL(os.Getenv("REDIS_URL"))
+	if err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, "cannot parse the [REDIS_URL] environment variable"))
+	}
+
+	container.redis = redis.NewClient(options)
+	return container.redis
+}
+
+// NatsJetStream creates a new instance of jetstream.JetStream
+func (container *Container) NatsJetStream() jetstream.JetStream {
+	if container.natsJetStream != nil {
+		return container.natsJetStream
+	}
+
+	container.logger.Debug("creating jetstream.JetStream")
+
+	connection, err := nats.Connect(os.Getenv("NATS_URL"), nats.Name(container.hostname()))
+	if err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, "cannot connect to NATS"))
+	}
+
+	js, err := jetstream.New(connection)
+	if err != nil {
+		container.logger.Fatal(stacktrace.Propagate(err, "cannot create NATS JetStream context"))
+	}
+
+	container.natsJetStream = js
+	return js
+}
+
+// hostname identifies this instance of the application
+func (container *Container) hostname() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		container.logger.Warn(stacktrace.Propagate(err, "cannot get the hostname"))
+		return "httpmock-api"
+	}
+	return hostname
+}
+
+// Shutdown stops the HTTP server and processes the pending events before the application exits
+func (container *Container) Shutdown(ctx context.Context) error {
+	container.logger.Info("shutting down the application")
+
+	// the open streams are closed first because the HTTP server waits for all the connections to be closed
+	container.EventBroker().Close()
+
+	if err := container.App().ShutdownWithContext(ctx); err != nil {
+		return stacktrace.Propagate(err, "cannot shutdown the HTTP server")
+	}
+
+	if drainer, ok := container.EventsQueue().(queue.Drainer); ok {
+		if err := drainer.Drain(ctx); err != nil {
+			return stacktrace.Propagate(err, "cannot drain the events queue")
+		}
+	}
+
+	if worker := container.EventsQueueWorker(); worker != nil {
+		if err := worker.Drain(ctx); err != nil {
+			return stacktrace.Propagate(err, "cannot drain the events queue worker")
+		}
+	}
+
+	return nil
 }
 
 // Tracer creates a new instance of telemetry.Tracer
@@ -595,6 +1352,31 @@ func (container *Container) Tracer() (t telemetry.Tracer) {
 	return telemetry.NewOtelLogger(container.logger)
 }
 
+// EventBroker creates a new instance of services.EventBroker
+func (container *Container) EventBroker() (broker *services.EventBroker) {
+	if container.eventBroker != nil {
+		return container.eventBroker
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating %T", broker))
+
+	// events consumed by Google Cloud Tasks are delivered to any instance so they cannot be streamed without a relay
+	switch Config().Queue() {
+	case QueueRedis:
+		pubSub := queue.NewRedisPubSub(container.Logger(), container.Tracer(), container.Redis())
+		container.eventBroker = services.NewEventBroker(container.Logger(), container.Tracer(), pubSub, Config().QueueStream+":broker", true)
+	case QueueNats:
+		pubSub := queue.NewNatsPubSub(container.Logger(), container.Tracer(), container.NatsJetStream().Conn())
+		container.eventBroker = services.NewEventBroker(container.Logger(), container.Tracer(), pubSub, Config().QueueStream+".broker", true)
+	case QueueMemory:
+		container.eventBroker = services.NewEventBroker(container.Logger(), container.Tracer(), nil, "", true)
+	default:
+		container.eventBroker = services.NewEventBroker(container.Logger(), container.Tracer(), nil, "", false)
+	}
+
+	return container.eventBroker
+}
+
 // EventDispatcher creates a new instance of services.EventDispatcher
 func (container *Container) EventDispatcher() (dispatcher *services.EventDispatcher) {
 	if container.eventDispatcher != nil {
@@ -612,6 +1394,9 @@ func (container *Container) EventDispatcher() (dispatcher *services.EventDispatc
 		),
 		container.EventsQueue(),
 		os.Getenv("EVENTS_QUEUE_WEBHOOK"),
+		container.DeadLetterRepository(),
+		Config().EventListenerMaxAttempts,
+		Config().EventListenerBackoff,
 	)
 
 	container.eventDispatcher = dispatcher
@@ -641,6 +1426,56 @@ func (container *Container) HTTPClient(name string) *http.Client {
 	}
 }
 
+// DeliveryHTTPClient creates the http.Client which sends the endpoint callbacks and the project webhooks.
+// It does not retry failed requests because every attempt is recorded and retried by the service which sends it.
+// Redirects are not followed so a 3xx response is recorded as a failed attempt instead of reaching another host.
+func (container *Container) DeliveryHTTPClient(name string) *http.Client {
+	container.logger.Debug(fmt.Sprintf("creating %s %T", name, http.DefaultClient))
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = container.OutboundDialer().DialContext
+
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		Transport: otelroundtripper.New(
+			otelroundtripper.WithName(name),
+			otelroundtripper.WithParent(transport),
+			otelroundtripper.WithMeter(otel.GetMeterProvider().Meter(container.projectID)),
+			otelroundtripper.WithAttributes(container.OtelResources(container.version, container.projectID).Attributes()...),
+		),
+	}
+}
+
+// OutboundDialer creates the net.Dialer used to connect to the URLs provided by users.
+// It refuses private, loopback and link-local IP addresses unless ALLOW_PRIVATE_NETWORKS is true e.g. to mock a local API.
+func (container *Container) OutboundDialer() *net.Dialer {
+	if Config().AllowPrivateNetworks {
+		return &net.Dialer{Timeout: 10 * time.Second}
+	}
+	return services.NewPublicDialer(10 * time.Second)
+}
+
+// UpstreamHTTPClient creates the fasthttp.Client which proxies requests to the upstream URL of a project
+func (container *Container) UpstreamHTTPClient() *fasthttp.Client {
+	container.logger.Debug(fmt.Sprintf("creating upstream %T", &fasthttp.Client{}))
+	dialer := container.OutboundDialer()
+	return &fasthttp.Client{
+		ReadTimeout:                   30 * time.Second,
+		WriteTimeout:                  30 * time.Second,
+		MaxResponseBodySize:           10 * 1024 * 1024,
+		NoDefaultUserAgentHeader:      true,
+		DisableHeaderNamesNormalizing: true,
+		DisablePathNormalizing:        true,
+		Dial: func(address string) (net.Conn, error) {
+			return dialer.Dial("tcp", address)
+		},
+	}
+}
+
 // HTTPRoundTripper creates an open telemetry http.RoundTripper
 func (container *Container) HTTPRoundTripper(name string) http.RoundTripper {
 	container.logger.Debug(fmt.Sprintf("Debug: initializing %s %T", name, http.DefaultTransport))
@@ -684,6 +1519,18 @@ func (container *Container) EventsHandler() (handler *handlers.EventsHandler) {
 
 // UserRepository registers a new instance of repositories.UserRepository
 func (container *Container) UserRepository() repositories.UserRepository {
+	switch Config().Storage() {
+	case StoragePostgres:
+		container.logger.Debug("creating PostgreSQL repositories.UserRepository")
+		return repositories.NewPostgresUserRepository(container.Logger(), container.Tracer(), container.Postgres())
+	case StorageMemory:
+		if container.userRepository == nil {
+			container.logger.Debug("creating in-memory repositories.UserRepository")
+			container.userRepository = repositories.NewMemoryUserRepository(container.Logger(), container.Tracer())
+		}
+		return container.userRepository
+	}
+
 	container.logger.Debug("creating Couchbase repositories.UserRepository")
 	return repositories.NewCouchbaseUserRepository(
 		container.Logger(),
@@ -701,6 +1548,7 @@ func (container *Container) LemonsqueezyService() (service *services.Lemonsqueez
 		container.Tracer(),
 		container.UserRepository(),
 		container.EventDispatcher(),
+		container.LemonsqueezyClient(),
 	)
 }
 
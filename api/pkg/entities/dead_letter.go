@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeadLetterStatus is the status of an entities.DeadLetter
+type DeadLetterStatus string
+
+const (
+	// DeadLetterStatusFailed means the listener could not handle the event
+	DeadLetterStatusFailed = DeadLetterStatus("failed")
+
+	// DeadLetterStatusReplayed means the listener handled the event when it was replayed
+	DeadLetterStatusReplayed = DeadLetterStatus("replayed")
+)
+
+// DeadLetter is an event which a listener could not handle after all the retries
+type DeadLetter struct {
+	ID         uuid.UUID        `json:"id" example:"8f9c71b8-b84e-4417-8408-a62274f65a08"`
+	EventID    string           `json:"event_id" example:"8f9c71b8-b84e-4417-8408-a62274f65a08"`
+	EventType  string           `json:"event_type" example:"project.endpoint.request"`
+	UserID     UserID           `json:"user_id" example:"user_2oeyIzOf9xxxxxxxxxxxxxx"`
+	Event      json.RawMessage  `json:"event" swaggertype:"object"`
+	Listener   string           `json:"listener" example:"listeners.(*ProjectEndpointRequestListener).onProjectEndpointRequest"`
+	Status     DeadLetterStatus `json:"status" example:"failed"`
+	Error      string           `json:"error" example:"cannot store project endpoint request"`
+	Attempts   uint             `json:"attempts" example:"3"`
+	ReplayedAt *time.Time       `json:"replayed_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	CreatedAt  time.Time        `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt  time.Time        `json:"updated_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
@@ -8,11 +8,25 @@ import (
 
 // Project is a  project belonging to a user
 type Project struct {
-	ID          uuid.UUID `json:"id" example:"8f9c71b8-b84e-4417-8408-a62274f65a08"`
-	UserID      UserID    `json:"user_id" example:"user_2oeyIzOf9xxxxxxxxxxxxxx"`
-	Subdomain   string    `json:"subdomain" example:"stripe-mock-api"`
-	Name        string    `json:"name" example:"Mock Stripe API"`
-	Description string    `json:"description" example:"Mock API for an online store for selling shoes"`
-	CreatedAt   time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
-	UpdatedAt   time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+	ID               uuid.UUID         `json:"id" example:"8f9c71b8-b84e-4417-8408-a62274f65a08"`
+	UserID           UserID            `json:"user_id" example:"user_2oeyIzOf9xxxxxxxxxxxxxx"`
+	Subdomain        string            `json:"subdomain" example:"stripe-mock-api"`
+	Name             string            `json:"name" example:"Mock Stripe API"`
+	Description      string            `json:"description" example:"Mock API for an online store for selling shoes"`
+	UpstreamURL      string            `json:"upstream_url" example:"https://api.stripe.com"`
+	RecordingEnabled bool              `json:"recording_enabled" example:"false"`
+	ScenarioStates   map[string]string `json:"scenario_states"`
+	CreatedAt        time.Time         `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt        time.Time         `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// ProjectScenarioStateStarted is the initial state of every scenario in a Project
+const ProjectScenarioStateStarted = "Started"
+
+// ScenarioState returns the current state of a scenario in the project
+func (project *Project) ScenarioState(scenario string) string {
+	if state, ok := project.ScenarioStates[scenario]; ok {
+		return state
+	}
+	return ProjectScenarioStateStarted
 }
@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectEndpointCallback is an HTTP request which is sent asynchronously after a ProjectEndpoint responds to a request
+// e.g. to simulate the webhook of a payment provider. The body and the header values are response templates.
+type ProjectEndpointCallback struct {
+	URL                 string  `json:"url" example:"https://example.com/webhooks/stripe"`
+	Method              string  `json:"method" example:"POST"`
+	Body                *string `json:"body" example:"{\"type\": \"payment_intent.succeeded\", \"id\": \"{{ .Body.id }}\"}"`
+	Headers             *string `json:"headers" example:"[{\"Content-Type\":\"application/json\"}]"`
+	DelayInMilliseconds uint    `json:"delay_in_milliseconds" example:"2000"`
+	Retries             uint    `json:"retries" example:"3"`
+}
+
+// ProjectEndpointCallbackAttempt is the result of sending a ProjectEndpointCallback for a ProjectEndpointRequest
+type ProjectEndpointCallbackAttempt struct {
+	ID                       uuid.UUID `json:"id" example:"8f9c71b8-b84e-4417-8408-a62274f65a08"`
+	ProjectID                uuid.UUID `json:"project_id" example:"8f9c71b8-b84e-4417-8408-a62274f65a08"`
+	ProjectEndpointID        uuid.UUID `json:"project_endpoint_id" example:"8f9c71b8-b84e-4417-8408-a62274f65a08"`
+	ProjectEndpointRequestID string    `json:"project_endpoint_request_id" example:"01HQ4WZ8B5V8J6X3T0N2K7M9PD"`
+	UserID                   UserID    `json:"user_id" example:"user_2oeyIzOf9xxxxxxxxxxxxxx"`
+	CallbackIndex            uint      `json:"callback_index" example:"0"`
+	Attempt                  uint      `json:"attempt" example:"1"`
+	RequestURL               string    `json:"request_url" example:"https://example.com/webhooks/stripe"`
+	RequestMethod            string    `json:"request_method" example:"POST"`
+	RequestHeaders           *string   `json:"request_headers" example:"[{\"Content-Type\":\"application/json\"}]"`
+	RequestBody              *string   `json:"request_body" example:"{\"type\": \"payment_intent.succeeded\"}"`
+	ResponseCode             *uint     `json:"response_code" example:"200"`
+	ResponseBody             *string   `json:"response_body" example:"{\"received\": true}"`
+	Error                    *string   `json:"error" example:"context deadline exceeded"`
+	Succeeded                bool      `json:"succeeded" example:"true"`
+	DurationInMilliseconds   uint      `json:"duration_in_milliseconds" example:"120"`
+	CreatedAt                time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
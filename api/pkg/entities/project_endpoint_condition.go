@@ -0,0 +1,49 @@
+package entities
+
+// ProjectEndpointConditionSource is the part of the HTTP request which is checked by a ProjectEndpointCondition
+type ProjectEndpointConditionSource string
+
+const (
+	// ProjectEndpointConditionSourceHeader checks a request header
+	ProjectEndpointConditionSourceHeader = ProjectEndpointConditionSource("header")
+
+	// ProjectEndpointConditionSourceQuery checks a query string parameter
+	ProjectEndpointConditionSourceQuery = ProjectEndpointConditionSource("query")
+
+	// ProjectEndpointConditionSourceJSONBody checks the value of a field in the JSON request body using a JSONPath expression
+	ProjectEndpointConditionSourceJSONBody = ProjectEndpointConditionSource("json_body")
+
+	// ProjectEndpointConditionSourceBody checks the raw request body
+	ProjectEndpointConditionSourceBody = ProjectEndpointConditionSource("body")
+)
+
+// ProjectEndpointConditionOperator is the comparison used by a ProjectEndpointCondition
+type ProjectEndpointConditionOperator string
+
+const (
+	// ProjectEndpointConditionOperatorEquals the value must be equal to the condition value
+	ProjectEndpointConditionOperatorEquals = ProjectEndpointConditionOperator("equals")
+
+	// ProjectEndpointConditionOperatorNotEquals the value must not be equal to the condition value
+	ProjectEndpointConditionOperatorNotEquals = ProjectEndpointConditionOperator("not_equals")
+
+	// ProjectEndpointConditionOperatorContains the value must contain the condition value
+	ProjectEndpointConditionOperatorContains = ProjectEndpointConditionOperator("contains")
+
+	// ProjectEndpointConditionOperatorMatches the value must match the regular expression in the condition value
+	ProjectEndpointConditionOperatorMatches = ProjectEndpointConditionOperator("matches")
+
+	// ProjectEndpointConditionOperatorPresent the value must be present in the request
+	ProjectEndpointConditionOperatorPresent = ProjectEndpointConditionOperator("present")
+
+	// ProjectEndpointConditionOperatorAbsent the value must not be present in the request
+	ProjectEndpointConditionOperatorAbsent = ProjectEndpointConditionOperator("absent")
+)
+
+// ProjectEndpointCondition is a rule which an HTTP request must satisfy for a ProjectEndpoint to be selected
+type ProjectEndpointCondition struct {
+	Source   ProjectEndpointConditionSource   `json:"source" example:"header"`
+	Key      string                           `json:"key" example:"Authorization"`
+	Operator ProjectEndpointConditionOperator `json:"operator" example:"equals"`
+	Value    string                           `json:"value" example:"Bearer sk_test_4eC39HqLyjWDarjtT1zdp7dc"`
+}
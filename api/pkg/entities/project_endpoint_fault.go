@@ -0,0 +1,48 @@
+package entities
+
+// ProjectEndpointFaultType is the type of failure simulated by a ProjectEndpointFault
+type ProjectEndpointFaultType string
+
+const (
+	// ProjectEndpointFaultTypeLatency adds a random delay before the response is sent
+	ProjectEndpointFaultTypeLatency = ProjectEndpointFaultType("latency")
+
+	// ProjectEndpointFaultTypeConnectionReset resets the TCP connection without sending a response
+	ProjectEndpointFaultTypeConnectionReset = ProjectEndpointFaultType("connection_reset")
+
+	// ProjectEndpointFaultTypeConnectionClose closes the TCP connection without sending a response
+	ProjectEndpointFaultTypeConnectionClose = ProjectEndpointFaultType("connection_close")
+
+	// ProjectEndpointFaultTypeEmptyResponse sends the response status and headers without a body
+	ProjectEndpointFaultTypeEmptyResponse = ProjectEndpointFaultType("empty_response")
+
+	// ProjectEndpointFaultTypeMalformedChunk sends an invalid chunked response body and closes the connection
+	ProjectEndpointFaultTypeMalformedChunk = ProjectEndpointFaultType("malformed_chunk")
+
+	// ProjectEndpointFaultTypeDrip sends the response body in small chunks over a period of time
+	ProjectEndpointFaultTypeDrip = ProjectEndpointFaultType("drip")
+)
+
+// ProjectEndpointLatencyDistribution is the probability distribution of the delay added by a ProjectEndpointFaultTypeLatency fault
+type ProjectEndpointLatencyDistribution string
+
+const (
+	// ProjectEndpointLatencyDistributionUniform every delay between the min and max is equally likely
+	ProjectEndpointLatencyDistributionUniform = ProjectEndpointLatencyDistribution("uniform")
+
+	// ProjectEndpointLatencyDistributionNormal delays are centered around the midpoint of the min and max
+	ProjectEndpointLatencyDistributionNormal = ProjectEndpointLatencyDistribution("normal")
+
+	// ProjectEndpointLatencyDistributionExponential short delays are common and long delays are rare
+	ProjectEndpointLatencyDistributionExponential = ProjectEndpointLatencyDistribution("exponential")
+)
+
+// ProjectEndpointFault is a failure which is simulated when a ProjectEndpoint receives a request
+type ProjectEndpointFault struct {
+	Type                       ProjectEndpointFaultType           `json:"type" example:"latency"`
+	Probability                float64                            `json:"probability" example:"0.25"`
+	LatencyMinInMilliseconds   uint                               `json:"latency_min_in_milliseconds" example:"100"`
+	LatencyMaxInMilliseconds   uint                               `json:"latency_max_in_milliseconds" example:"2000"`
+	LatencyDistribution        ProjectEndpointLatencyDistribution `json:"latency_distribution" example:"uniform"`
+	DripDurationInMilliseconds uint                               `json:"drip_duration_in_milliseconds" example:"5000"`
+}
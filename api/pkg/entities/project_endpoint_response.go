@@ -0,0 +1,23 @@
+package entities
+
+// ProjectEndpointResponseMode determines how a response is selected when a ProjectEndpoint has multiple responses
+type ProjectEndpointResponseMode string
+
+const (
+	// ProjectEndpointResponseModeRoundRobin returns the responses in order and starts again from the first response after the last one
+	ProjectEndpointResponseModeRoundRobin = ProjectEndpointResponseMode("round_robin")
+
+	// ProjectEndpointResponseModeSequential returns the responses in order and keeps returning the last response
+	ProjectEndpointResponseModeSequential = ProjectEndpointResponseMode("sequential")
+
+	// ProjectEndpointResponseModeWeightedRandom returns a random response using the weight of each response
+	ProjectEndpointResponseModeWeightedRandom = ProjectEndpointResponseMode("weighted_random")
+)
+
+// ProjectEndpointResponse is one of the responses which can be returned by a ProjectEndpoint
+type ProjectEndpointResponse struct {
+	ResponseCode    uint    `json:"response_code" example:"503"`
+	ResponseBody    *string `json:"response_body" example:"{\"message\": \"Service Unavailable\"}"`
+	ResponseHeaders *string `json:"response_headers" example:"[{\"Content-Type\":\"application/json\"}]"`
+	Weight          uint    `json:"weight" example:"1"`
+}
@@ -0,0 +1,8 @@
+package entities
+
+// ProjectScenario is the current state of a scenario used by the endpoints of a Project
+type ProjectScenario struct {
+	Name   string   `json:"name" example:"order-lifecycle"`
+	State  string   `json:"state" example:"Shipped"`
+	States []string `json:"states" example:"Started,Shipped"`
+}
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectWebhookMode is the CloudEvents HTTP content mode used to deliver events to a ProjectWebhook
+type ProjectWebhookMode string
+
+const (
+	// ProjectWebhookModeBinary sends the attributes of the event as ce-* headers and the data as the body
+	ProjectWebhookModeBinary = ProjectWebhookMode("binary")
+
+	// ProjectWebhookModeStructured sends the whole event as an application/cloudevents+json body
+	ProjectWebhookModeStructured = ProjectWebhookMode("structured")
+)
+
+// ProjectWebhook is an HTTPS URL which receives every request captured by a Project as a CloudEvent
+type ProjectWebhook struct {
+	ID            uuid.UUID          `json:"id" example:"8f9c71b8-b84e-4417-8408-a62274f65a08"`
+	ProjectID     uuid.UUID          `json:"project_id" example:"8f9c71b8-b84e-4417-8408-a62274f65a08"`
+	UserID        UserID             `json:"user_id" example:"user_2oeyIzOf9xxxxxxxxxxxxxx"`
+	URL           string             `json:"url" example:"https://ci.example.com/httpmock"`
+	Mode          ProjectWebhookMode `json:"mode" example:"binary"`
+	SigningSecret string             `json:"signing_secret" example:"whsec_4f9c71b8b84e44178408a62274f65a08"`
+	CreatedAt     time.Time          `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt     time.Time          `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// ProjectWebhookDelivery is the result of sending an event to a ProjectWebhook
+type ProjectWebhookDelivery struct {
+	ID                     uuid.UUID `json:"id" example:"8f9c71b8-b84e-4417-8408-a62274f65a08"`
+	ProjectWebhookID       uuid.UUID `json:"project_webhook_id" example:"8f9c71b8-b84e-4417-8408-a62274f65a08"`
+	ProjectID              uuid.UUID `json:"project_id" example:"8f9c71b8-b84e-4417-8408-a62274f65a08"`
+	UserID                 UserID    `json:"user_id" example:"user_2oeyIzOf9xxxxxxxxxxxxxx"`
+	EventID                string    `json:"event_id" example:"c3b7b1a4-4f0e-4b5e-9d7c-3f2a1b0c9d8e"`
+	EventType              string    `json:"event_type" example:"project.endpoint.request"`
+	Attempt                uint      `json:"attempt" example:"1"`
+	RequestURL             string    `json:"request_url" example:"https://ci.example.com/httpmock"`
+	ResponseCode           *uint     `json:"response_code" example:"200"`
+	ResponseBody           *string   `json:"response_body" example:"{\"received\": true}"`
+	Error                  *string   `json:"error" example:"context deadline exceeded"`
+	Succeeded              bool      `json:"succeeded" example:"true"`
+	DurationInMilliseconds uint      `json:"duration_in_milliseconds" example:"120"`
+	CreatedAt              time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
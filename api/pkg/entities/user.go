@@ -30,3 +30,75 @@ type User struct {
 	CreatedAt            time.Time        `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
 	UpdatedAt            time.Time        `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
 }
+
+// SubscriptionStatusExpired is the status of a subscription which has ended
+const SubscriptionStatusExpired = "expired"
+
+// SubscriptionLimits are the maximum resources which a user can use with a SubscriptionName
+type SubscriptionLimits struct {
+	Projects               uint `json:"projects" example:"3"`
+	EndpointsPerProject    uint `json:"endpoints_per_project" example:"20"`
+	MonthlyRequests        uint `json:"monthly_requests" example:"1000"`
+	RequestRetentionInDays uint `json:"request_retention_in_days" example:"7"`
+}
+
+// RequestRetention is the duration for which the request logs are kept
+func (limits SubscriptionLimits) RequestRetention() time.Duration {
+	return time.Duration(limits.RequestRetentionInDays) * 24 * time.Hour
+}
+
+// Limits returns the SubscriptionLimits of a subscription
+func (name SubscriptionName) Limits() SubscriptionLimits {
+	switch name {
+	case SubscriptionName10kMonthly:
+		return SubscriptionLimits{Projects: 10, EndpointsPerProject: 100, MonthlyRequests: 10_000, RequestRetentionInDays: 30}
+	case SubscriptionName10kYearly:
+		return SubscriptionLimits{Projects: 50, EndpointsPerProject: 500, MonthlyRequests: 100_000, RequestRetentionInDays: 90}
+	default:
+		return SubscriptionLimits{Projects: 3, EndpointsPerProject: 20, MonthlyRequests: 1_000, RequestRetentionInDays: 7}
+	}
+}
+
+// ActiveSubscriptionName returns the SubscriptionName which applies at a given time.
+// A subscription which has ended or expired falls back to SubscriptionNameFree.
+func (user *User) ActiveSubscriptionName(now time.Time) SubscriptionName {
+	if user.SubscriptionName == "" || user.SubscriptionStatus == SubscriptionStatusExpired {
+		return SubscriptionNameFree
+	}
+
+	if user.SubscriptionEndsAt != nil && !now.Before(*user.SubscriptionEndsAt) {
+		return SubscriptionNameFree
+	}
+
+	return user.SubscriptionName
+}
+
+// IsSubscriptionRenewing checks if the user will be billed again for a paid subscription
+func (user *User) IsSubscriptionRenewing() bool {
+	return user.SubscriptionID != "" && user.SubscriptionEndsAt == nil && user.SubscriptionStatus != SubscriptionStatusExpired
+}
+
+// BillingPeriod returns the start and the end of the billing period which contains a time.
+// A paid subscription is billed monthly from its renewal date while the free plan is billed per calendar month.
+func (user *User) BillingPeriod(now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+
+	anchor := user.SubscriptionRenewsAt
+	if anchor == nil {
+		// a cancelled subscription does not renew but it ends at the end of a billing period
+		anchor = user.SubscriptionEndsAt
+	}
+
+	if anchor == nil || user.ActiveSubscriptionName(now) == SubscriptionNameFree {
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	}
+
+	start := anchor.UTC()
+	months := (now.Year()-start.Year())*12 + int(now.Month()) - int(start.Month())
+	if start.AddDate(0, months, 0).After(now) {
+		months--
+	}
+
+	return start.AddDate(0, months, 0), start.AddDate(0, months+1, 0)
+}
@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// UserUsage stores the number of mock requests which a user made in a period
+type UserUsage struct {
+	UserID       UserID    `json:"user_id" example:"user_2oeyIzOf9xxxxxxxxxxxxxx"`
+	PeriodStart  time.Time `json:"period_start" example:"2022-06-01T00:00:00Z"`
+	RequestCount uint      `json:"request_count" example:"250"`
+	UpdatedAt    time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// UserUsageID is the unique ID of the UserUsage of a user in a period
+func UserUsageID(userID UserID, periodStart time.Time) string {
+	return fmt.Sprintf("%s-%s", userID, periodStart.UTC().Format("20060102"))
+}
@@ -0,0 +1,52 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+)
+
+// ProjectCleanupProgress is raised after a batch of documents of a deleted project is deleted
+const ProjectCleanupProgress = "project.cleanup.progress"
+
+// ProjectCleanupCompleted is raised when all the endpoints, requests, callback attempts and webhooks of a deleted project are deleted
+const ProjectCleanupCompleted = "project.cleanup.completed"
+
+// ProjectCleanupResourceEndpoints identifies the entities.ProjectEndpoint of a deleted project
+const ProjectCleanupResourceEndpoints = "endpoints"
+
+// ProjectCleanupResourceRequests identifies the entities.ProjectEndpointRequest of a deleted project
+const ProjectCleanupResourceRequests = "requests"
+
+// ProjectCleanupResourceCallbackAttempts identifies the entities.ProjectEndpointCallbackAttempt of a deleted project
+const ProjectCleanupResourceCallbackAttempts = "callback_attempts"
+
+// ProjectCleanupResourceWebhooks identifies the entities.ProjectWebhook of a deleted project
+const ProjectCleanupResourceWebhooks = "webhooks"
+
+// ProjectCleanupResourceWebhookDeliveries identifies the entities.ProjectWebhookDelivery of a deleted project
+const ProjectCleanupResourceWebhookDeliveries = "webhook_deliveries"
+
+// ProjectCleanupProgressPayload stores the data for the ProjectCleanupProgress event
+type ProjectCleanupProgressPayload struct {
+	UserID       entities.UserID `json:"user_id"`
+	ProjectID    uuid.UUID       `json:"project_id"`
+	Resource     string          `json:"resource"`
+	Deleted      uint            `json:"deleted"`
+	TotalDeleted uint            `json:"total_deleted"`
+	Timestamp    time.Time       `json:"timestamp"`
+}
+
+// ProjectCleanupCompletedPayload stores the data for the ProjectCleanupCompleted event
+type ProjectCleanupCompletedPayload struct {
+	UserID                   entities.UserID `json:"user_id"`
+	ProjectID                uuid.UUID       `json:"project_id"`
+	EndpointsDeleted         uint            `json:"endpoints_deleted"`
+	RequestsDeleted          uint            `json:"requests_deleted"`
+	CallbackAttemptsDeleted  uint            `json:"callback_attempts_deleted"`
+	WebhooksDeleted          uint            `json:"webhooks_deleted"`
+	WebhookDeliveriesDeleted uint            `json:"webhook_deliveries_deleted"`
+	Timestamp                time.Time       `json:"timestamp"`
+}
@@ -0,0 +1,31 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oklog/ulid/v2"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+)
+
+// ProjectEndpointCallback is raised when an entities.ProjectEndpointCallback must be sent
+const ProjectEndpointCallback = "project.endpoint.callback"
+
+// ProjectEndpointCallbackPayload stores the data for the ProjectEndpointCallback event.
+// The body and the headers have already been rendered with the request which triggered the callback.
+type ProjectEndpointCallbackPayload struct {
+	UserID                   entities.UserID `json:"user_id"`
+	ProjectID                uuid.UUID       `json:"project_id"`
+	ProjectEndpointID        uuid.UUID       `json:"project_endpoint_id"`
+	ProjectEndpointRequestID ulid.ULID       `json:"project_endpoint_request_id"`
+	CallbackIndex            uint            `json:"callback_index"`
+	Attempt                  uint            `json:"attempt"`
+	MaxAttempts              uint            `json:"max_attempts"`
+	URL                      string          `json:"url"`
+	Method                   string          `json:"method"`
+	Body                     *string         `json:"body"`
+	Headers                  *string         `json:"headers"`
+	ScheduledAt              time.Time       `json:"scheduled_at"`
+	Timestamp                time.Time       `json:"timestamp"`
+}
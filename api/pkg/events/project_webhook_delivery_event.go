@@ -0,0 +1,26 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+)
+
+// ProjectWebhookDelivery is raised when an event must be delivered to an entities.ProjectWebhook
+const ProjectWebhookDelivery = "project.webhook.delivery"
+
+// ProjectWebhookDeliveryPayload stores the data for the ProjectWebhookDelivery event.
+// The event which is delivered is stored in the structured CloudEvents JSON format.
+type ProjectWebhookDeliveryPayload struct {
+	UserID           entities.UserID `json:"user_id"`
+	ProjectID        uuid.UUID       `json:"project_id"`
+	ProjectWebhookID uuid.UUID       `json:"project_webhook_id"`
+	Event            json.RawMessage `json:"event"`
+	Attempt          uint            `json:"attempt"`
+	MaxAttempts      uint            `json:"max_attempts"`
+	ScheduledAt      time.Time       `json:"scheduled_at"`
+	Timestamp        time.Time       `json:"timestamp"`
+}
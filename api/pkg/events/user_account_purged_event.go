@@ -0,0 +1,17 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+)
+
+// UserAccountPurged is raised when all the data of a deleted user account has been erased
+const UserAccountPurged = "user.account.purged"
+
+// UserAccountPurgedPayload stores the data for the UserAccountPurged event
+type UserAccountPurgedPayload struct {
+	UserID          entities.UserID `json:"user_id"`
+	ProjectsDeleted uint            `json:"projects_deleted"`
+	Timestamp       time.Time       `json:"timestamp"`
+}
@@ -0,0 +1,23 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+)
+
+// UserUsageWarning is raised when a user has used 80% and 100% of the monthly requests of their subscription
+const UserUsageWarning = "user.usage.warning"
+
+// UserUsageWarningPayload stores the data for the UserUsageWarning event
+type UserUsageWarningPayload struct {
+	UserID           entities.UserID           `json:"user_id"`
+	SubscriptionName entities.SubscriptionName `json:"subscription_name"`
+	PeriodStart      time.Time                 `json:"period_start"`
+	PeriodEnd        time.Time                 `json:"period_end"`
+	RequestCount     uint                      `json:"request_count"`
+	SoftLimit        uint                      `json:"soft_limit"`
+	HardLimit        uint                      `json:"hard_limit"`
+	UsagePercentage  uint                      `json:"usage_percentage"`
+	Timestamp        time.Time                 `json:"timestamp"`
+}
@@ -0,0 +1,102 @@
+package exporters
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+)
+
+// anyMethods are the HTTP methods used to export an entities.ProjectEndpoint with the ANY request method
+var anyMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// header is a single HTTP header stored in the [{"key":"value"}] format
+type header struct {
+	Name  string
+	Value string
+}
+
+func decodeHeaders(headers *string) []header {
+	if headers == nil || *headers == "" {
+		return nil
+	}
+
+	var values []map[string]string
+	if err := json.Unmarshal([]byte(*headers), &values); err != nil {
+		return nil
+	}
+
+	var result []header
+	for _, value := range values {
+		for name, item := range value {
+			result = append(result, header{Name: name, Value: item})
+		}
+	}
+	return result
+}
+
+func contentType(headers []header) string {
+	for _, item := range headers {
+		if strings.EqualFold(item.Name, "Content-Type") {
+			return item.Value
+		}
+	}
+	return ""
+}
+
+func endpointMethods(endpoint *entities.ProjectEndpoint) []string {
+	if endpoint.RequestMethod == "ANY" {
+		return anyMethods
+	}
+	return []string{endpoint.RequestMethod}
+}
+
+// endpointPath converts the unnamed parameters and the trailing wildcard of a path template into named parameters
+// since they cannot be represented in OpenAPI and Postman
+func endpointPath(path string) string {
+	segments := strings.Split(path, "/")
+	for index, segment := range segments {
+		if segment != "*" {
+			continue
+		}
+		if index == len(segments)-1 {
+			segments[index] = "{wildcard}"
+		} else {
+			segments[index] = fmt.Sprintf("{param%d}", index)
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func endpointParameters(path string) []string {
+	var result []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			result = append(result, strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}"))
+		}
+	}
+	return result
+}
+
+func endpointResponses(endpoint *entities.ProjectEndpoint) []*entities.ProjectEndpointResponse {
+	if len(endpoint.Responses) > 0 {
+		return endpoint.Responses
+	}
+
+	return []*entities.ProjectEndpointResponse{
+		{
+			ResponseCode:    endpoint.ResponseCode,
+			ResponseBody:    endpoint.ResponseBody,
+			ResponseHeaders: endpoint.ResponseHeaders,
+		},
+	}
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
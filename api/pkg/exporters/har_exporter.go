@@ -0,0 +1,193 @@
+package exporters
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+)
+
+// HAR is an HTTP Archive 1.2 file
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+// HARLog is the root of a HAR file
+type HARLog struct {
+	Version string      `json:"version"`
+	Creator HARCreator  `json:"creator"`
+	Entries []*HAREntry `json:"entries"`
+}
+
+// HARCreator is the application which created a HAR file
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HAREntry is a single HTTP request in a HAR file
+type HAREntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            uint        `json:"time"`
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+// HARRequest is the HTTP request of a HAREntry
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// HARResponse is the HTTP response of a HAREntry
+type HARResponse struct {
+	Status      uint           `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// HARNameValue is a header, cookie or query string parameter
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARPostData is the body of a HARRequest
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// HARContent is the body of a HARResponse
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+// HARTimings are the timings of a HAREntry
+type HARTimings struct {
+	Send    uint `json:"send"`
+	Wait    uint `json:"wait"`
+	Receive uint `json:"receive"`
+}
+
+// HARCreatorName is the name of the application in the exported HAR files
+const HARCreatorName = "httpmock"
+
+// HTTPArchive exports the entities.ProjectEndpointRequest of an entities.Project as a HAR file ordered from the oldest request
+func HTTPArchive(requests []*entities.ProjectEndpointRequest) *HAR {
+	requests = append([]*entities.ProjectEndpointRequest{}, requests...)
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].CreatedAt.Before(requests[j].CreatedAt)
+	})
+
+	har := &HAR{
+		Log: HARLog{
+			Version: "1.2",
+			Creator: HARCreator{Name: HARCreatorName, Version: "1.0"},
+			Entries: make([]*HAREntry, 0, len(requests)),
+		},
+	}
+
+	for _, request := range requests {
+		har.Log.Entries = append(har.Log.Entries, harEntry(request))
+	}
+
+	return har
+}
+
+func harEntry(request *entities.ProjectEndpointRequest) *HAREntry {
+	requestHeaders := harHeaders(decodeHeaders(request.RequestHeaders))
+	responseHeaders := decodeHeaders(request.ResponseHeaders)
+	responseBody := stringValue(request.ResponseBody)
+
+	entry := &HAREntry{
+		StartedDateTime: request.CreatedAt.UTC().Format("2006-01-02T15:04:05.000Z07:00"),
+		Time:            request.ResponseDelayInMilliseconds,
+		Request: HARRequest{
+			Method:      request.RequestMethod,
+			URL:         request.RequestURL,
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []HARNameValue{},
+			Headers:     requestHeaders,
+			QueryString: harQueryString(request.RequestURL),
+			HeadersSize: -1,
+			BodySize:    len(stringValue(request.RequestBody)),
+		},
+		Response: HARResponse{
+			Status:      request.ResponseCode,
+			StatusText:  http.StatusText(int(request.ResponseCode)),
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []HARNameValue{},
+			Headers:     harHeaders(responseHeaders),
+			Content: HARContent{
+				Size:     len(responseBody),
+				MimeType: contentType(responseHeaders),
+				Text:     responseBody,
+			},
+			HeadersSize: -1,
+			BodySize:    len(responseBody),
+		},
+		Timings: HARTimings{Wait: request.ResponseDelayInMilliseconds},
+		Comment: request.ID,
+	}
+
+	if request.RequestBody != nil {
+		entry.Request.PostData = &HARPostData{
+			MimeType: contentType(decodeHeaders(request.RequestHeaders)),
+			Text:     *request.RequestBody,
+		}
+	}
+
+	return entry
+}
+
+func harHeaders(headers []header) []HARNameValue {
+	result := make([]HARNameValue, 0, len(headers))
+	for _, item := range headers {
+		result = append(result, HARNameValue{Name: item.Name, Value: item.Value})
+	}
+	return result
+}
+
+func harQueryString(requestURL string) []HARNameValue {
+	result := []HARNameValue{}
+
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return result
+	}
+
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range query[key] {
+			result = append(result, HARNameValue{Name: key, Value: value})
+		}
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+package exporters
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/openapi"
+)
+
+// OpenAPI exports the entities.ProjectEndpoint of an entities.Project as an OpenAPI 3.0 document.
+// Endpoints which have the same path and method are merged into a single operation with multiple responses.
+func OpenAPI(project *entities.Project, baseURL string, endpoints []*entities.ProjectEndpoint) *openapi.Document {
+	document := &openapi.Document{
+		OpenAPI: "3.0.3",
+		Info: openapi.Info{
+			Title:       project.Name,
+			Description: project.Description,
+			Version:     project.UpdatedAt.Format("2006-01-02"),
+		},
+		Servers: []*openapi.Server{{URL: baseURL, Description: "httpmock"}},
+		Paths:   map[string]*openapi.PathItem{},
+	}
+
+	endpoints = append([]*entities.ProjectEndpoint{}, endpoints...)
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		return endpoints[i].Priority < endpoints[j].Priority
+	})
+
+	for _, endpoint := range endpoints {
+		path := endpointPath(endpoint.RequestPath)
+		item, ok := document.Paths[path]
+		if !ok {
+			item = &openapi.PathItem{}
+			document.Paths[path] = item
+		}
+
+		for _, method := range endpointMethods(endpoint) {
+			operation := item.Operation(method)
+			if operation == nil {
+				operation = openAPIOperation(path, endpoint)
+				item.SetOperation(method, operation)
+			}
+
+			for _, response := range endpointResponses(endpoint) {
+				code := strconv.Itoa(int(response.ResponseCode))
+				if _, exists := operation.Responses[code]; !exists {
+					operation.Responses[code] = openAPIResponse(response)
+				}
+			}
+		}
+	}
+
+	return document
+}
+
+func openAPIOperation(path string, endpoint *entities.ProjectEndpoint) *openapi.Operation {
+	operation := &openapi.Operation{
+		Summary:   stringValue(endpoint.Description),
+		Responses: map[string]*openapi.Response{},
+	}
+
+	for _, name := range endpointParameters(path) {
+		operation.Parameters = append(operation.Parameters, &openapi.Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &openapi.Schema{Type: "string"},
+		})
+	}
+
+	return operation
+}
+
+func openAPIResponse(endpointResponse *entities.ProjectEndpointResponse) *openapi.Response {
+	response := &openapi.Response{
+		Description: http.StatusText(int(endpointResponse.ResponseCode)),
+	}
+	if response.Description == "" {
+		response.Description = "Response"
+	}
+
+	headers := decodeHeaders(endpointResponse.ResponseHeaders)
+	for _, item := range headers {
+		if strings.EqualFold(item.Name, "Content-Type") {
+			continue
+		}
+		if response.Headers == nil {
+			response.Headers = map[string]*openapi.Header{}
+		}
+		response.Headers[item.Name] = &openapi.Header{Schema: &openapi.Schema{Type: "string"}, Example: item.Value}
+	}
+
+	body := stringValue(endpointResponse.ResponseBody)
+	if body == "" {
+		return response
+	}
+
+	mediaType := contentType(headers)
+	if mediaType == "" {
+		mediaType = "text/plain"
+	}
+
+	var example interface{} = body
+	if strings.Contains(mediaType, "json") {
+		var value interface{}
+		if err := json.Unmarshal([]byte(body), &value); err == nil {
+			example = value
+		}
+	}
+
+	response.Content = map[string]*openapi.MediaType{
+		mediaType: {Example: example},
+	}
+
+	return response
+}
@@ -0,0 +1,154 @@
+package exporters
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+)
+
+// postmanSchema is the schema of a Postman v2.1 collection
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// PostmanCollection is a Postman v2.1 collection
+type PostmanCollection struct {
+	Info     PostmanInfo        `json:"info"`
+	Item     []*PostmanItem     `json:"item"`
+	Variable []*PostmanVariable `json:"variable"`
+}
+
+// PostmanInfo is the metadata of a PostmanCollection
+type PostmanInfo struct {
+	PostmanID   string `json:"_postman_id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+// PostmanItem is a request in a PostmanCollection
+type PostmanItem struct {
+	Name     string             `json:"name"`
+	Request  *PostmanRequest    `json:"request"`
+	Response []*PostmanResponse `json:"response"`
+}
+
+// PostmanRequest is the HTTP request of a PostmanItem
+type PostmanRequest struct {
+	Method      string           `json:"method"`
+	Header      []*PostmanHeader `json:"header"`
+	URL         *PostmanURL      `json:"url"`
+	Description string           `json:"description,omitempty"`
+}
+
+// PostmanURL is the URL of a PostmanRequest
+type PostmanURL struct {
+	Raw      string             `json:"raw"`
+	Host     []string           `json:"host"`
+	Path     []string           `json:"path"`
+	Variable []*PostmanVariable `json:"variable,omitempty"`
+}
+
+// PostmanResponse is an example response of a PostmanItem
+type PostmanResponse struct {
+	Name            string           `json:"name"`
+	OriginalRequest *PostmanRequest  `json:"originalRequest"`
+	Status          string           `json:"status"`
+	Code            uint             `json:"code"`
+	Header          []*PostmanHeader `json:"header"`
+	Body            string           `json:"body"`
+}
+
+// PostmanHeader is an HTTP header in a PostmanRequest or a PostmanResponse
+type PostmanHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// PostmanVariable is a variable in a PostmanCollection or a PostmanURL
+type PostmanVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Postman exports the entities.ProjectEndpoint of an entities.Project as a Postman v2.1 collection
+func Postman(project *entities.Project, baseURL string, endpoints []*entities.ProjectEndpoint) *PostmanCollection {
+	collection := &PostmanCollection{
+		Info: PostmanInfo{
+			PostmanID:   project.ID.String(),
+			Name:        project.Name,
+			Description: project.Description,
+			Schema:      postmanSchema,
+		},
+		Item:     []*PostmanItem{},
+		Variable: []*PostmanVariable{{Key: "baseUrl", Value: baseURL}},
+	}
+
+	endpoints = append([]*entities.ProjectEndpoint{}, endpoints...)
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		if endpoints[i].RequestPath != endpoints[j].RequestPath {
+			return endpoints[i].RequestPath < endpoints[j].RequestPath
+		}
+		return endpoints[i].RequestMethod < endpoints[j].RequestMethod
+	})
+
+	for _, endpoint := range endpoints {
+		for _, method := range endpointMethods(endpoint) {
+			request := postmanRequest(method, endpoint)
+
+			name := stringValue(endpoint.Description)
+			if name == "" {
+				name = method + " " + endpoint.RequestPath
+			}
+
+			item := &PostmanItem{Name: name, Request: request, Response: []*PostmanResponse{}}
+			for _, response := range endpointResponses(endpoint) {
+				item.Response = append(item.Response, postmanResponse(request, response))
+			}
+
+			collection.Item = append(collection.Item, item)
+		}
+	}
+
+	return collection
+}
+
+func postmanRequest(method string, endpoint *entities.ProjectEndpoint) *PostmanRequest {
+	path := endpointPath(endpoint.RequestPath)
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+
+	url := &PostmanURL{Host: []string{"{{baseUrl}}"}}
+	for index, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			name := strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}")
+			segments[index] = ":" + name
+			url.Variable = append(url.Variable, &PostmanVariable{Key: name, Value: ""})
+		}
+	}
+	url.Path = segments
+	url.Raw = "{{baseUrl}}/" + strings.Join(segments, "/")
+
+	return &PostmanRequest{
+		Method:      method,
+		Header:      []*PostmanHeader{},
+		URL:         url,
+		Description: stringValue(endpoint.Description),
+	}
+}
+
+func postmanResponse(request *PostmanRequest, endpointResponse *entities.ProjectEndpointResponse) *PostmanResponse {
+	response := &PostmanResponse{
+		Name:            http.StatusText(int(endpointResponse.ResponseCode)),
+		OriginalRequest: request,
+		Status:          http.StatusText(int(endpointResponse.ResponseCode)),
+		Code:            endpointResponse.ResponseCode,
+		Header:          []*PostmanHeader{},
+		Body:            stringValue(endpointResponse.ResponseBody),
+	}
+
+	for _, item := range decodeHeaders(endpointResponse.ResponseHeaders) {
+		response.Header = append(response.Header, &PostmanHeader{Key: item.Name, Value: item.Value})
+	}
+
+	return response
+}
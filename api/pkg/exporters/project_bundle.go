@@ -0,0 +1,133 @@
+package exporters
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/openapi"
+	"github.com/palantir/stacktrace"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectBundleVersion is the current version of the ProjectBundle format
+const ProjectBundleVersion = 1
+
+// ProjectBundle is a portable document containing an entities.Project and all its entities.ProjectEndpoint
+type ProjectBundle struct {
+	Version   int                      `json:"version" example:"1"`
+	Project   ProjectBundleProject     `json:"project"`
+	Endpoints []*ProjectBundleEndpoint `json:"endpoints"`
+}
+
+// ProjectBundleProject contains the settings of the entities.Project in a ProjectBundle
+type ProjectBundleProject struct {
+	Name        string `json:"name" example:"Mock Stripe API"`
+	Description string `json:"description,omitempty" example:"Mock API for an online store for selling shoes"`
+	UpstreamURL string `json:"upstream_url,omitempty" example:"https://api.stripe.com"`
+}
+
+// ProjectBundleEndpoint is an entities.ProjectEndpoint in a ProjectBundle
+type ProjectBundleEndpoint struct {
+	RequestMethod               string                               `json:"request_method" example:"GET"`
+	RequestPath                 string                               `json:"request_path" example:"/v1/products"`
+	RequestConditions           []*entities.ProjectEndpointCondition `json:"request_conditions,omitempty"`
+	ScenarioName                string                               `json:"scenario_name,omitempty" example:"order-lifecycle"`
+	ScenarioRequiredState       string                               `json:"scenario_required_state,omitempty" example:"Started"`
+	ScenarioNewState            string                               `json:"scenario_new_state,omitempty" example:"Shipped"`
+	Priority                    uint                                 `json:"priority,omitempty" example:"0"`
+	ResponseCode                uint                                 `json:"response_code" example:"200"`
+	ResponseBody                string                               `json:"response_body,omitempty" example:"{\"message\": \"Hello World\",\"status\": 200}"`
+	ResponseHeaders             string                               `json:"response_headers,omitempty" example:"[{\"Content-Type\":\"application/json\"}]"`
+	Responses                   []*entities.ProjectEndpointResponse  `json:"responses,omitempty"`
+	ResponseMode                string                               `json:"response_mode,omitempty" example:"round_robin"`
+	ResponseTemplateEnabled     bool                                 `json:"response_template_enabled,omitempty" example:"false"`
+	ResponseDelayInMilliseconds uint                                 `json:"response_delay_in_milliseconds,omitempty" example:"100"`
+	Faults                      []*entities.ProjectEndpointFault     `json:"faults,omitempty"`
+	Callbacks                   []*entities.ProjectEndpointCallback  `json:"callbacks,omitempty"`
+	Description                 string                               `json:"description,omitempty" example:"Mock API for an online store for the /v1/products endpoint"`
+}
+
+// NewProjectBundle creates a ProjectBundle from an entities.Project and its entities.ProjectEndpoint.
+// The endpoints are ordered by path and method so that the bundle can be versioned in a git repository.
+func NewProjectBundle(project *entities.Project, endpoints []*entities.ProjectEndpoint) *ProjectBundle {
+	bundle := &ProjectBundle{
+		Version: ProjectBundleVersion,
+		Project: ProjectBundleProject{
+			Name:        project.Name,
+			Description: project.Description,
+			UpstreamURL: project.UpstreamURL,
+		},
+		Endpoints: make([]*ProjectBundleEndpoint, 0, len(endpoints)),
+	}
+
+	for _, endpoint := range endpoints {
+		bundle.Endpoints = append(bundle.Endpoints, &ProjectBundleEndpoint{
+			RequestMethod:               endpoint.RequestMethod,
+			RequestPath:                 endpoint.RequestPath,
+			RequestConditions:           endpoint.RequestConditions,
+			ScenarioName:                endpoint.ScenarioName,
+			ScenarioRequiredState:       endpoint.ScenarioRequiredState,
+			ScenarioNewState:            endpoint.ScenarioNewState,
+			Priority:                    endpoint.Priority,
+			ResponseCode:                endpoint.ResponseCode,
+			ResponseBody:                stringValue(endpoint.ResponseBody),
+			ResponseHeaders:             stringValue(endpoint.ResponseHeaders),
+			Responses:                   endpoint.Responses,
+			ResponseMode:                string(endpoint.ResponseMode),
+			ResponseTemplateEnabled:     endpoint.ResponseTemplateEnabled,
+			ResponseDelayInMilliseconds: endpoint.ResponseDelayInMilliseconds,
+			Faults:                      endpoint.Faults,
+			Callbacks:                   endpoint.Callbacks,
+			Description:                 stringValue(endpoint.Description),
+		})
+	}
+
+	sort.SliceStable(bundle.Endpoints, func(i, j int) bool {
+		if bundle.Endpoints[i].RequestPath != bundle.Endpoints[j].RequestPath {
+			return bundle.Endpoints[i].RequestPath < bundle.Endpoints[j].RequestPath
+		}
+		if bundle.Endpoints[i].RequestMethod != bundle.Endpoints[j].RequestMethod {
+			return bundle.Endpoints[i].RequestMethod < bundle.Endpoints[j].RequestMethod
+		}
+		return bundle.Endpoints[i].Priority < bundle.Endpoints[j].Priority
+	})
+
+	return bundle
+}
+
+// ParseProjectBundle decodes a ProjectBundle in JSON or YAML format
+func ParseProjectBundle(content []byte) (*ProjectBundle, error) {
+	bundle := new(ProjectBundle)
+	if err := openapi.Unmarshal(content, bundle); err != nil {
+		return nil, stacktrace.Propagate(err, "cannot decode the project bundle")
+	}
+
+	if bundle.Version != ProjectBundleVersion {
+		return nil, stacktrace.NewError(fmt.Sprintf("the project bundle version [%d] is not supported", bundle.Version))
+	}
+
+	return bundle, nil
+}
+
+// YAML encodes the ProjectBundle in YAML format
+func (bundle *ProjectBundle) YAML() ([]byte, error) {
+	content, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot encode the project bundle as JSON")
+	}
+
+	// The bundle is converted from JSON so that the YAML keys are the same as the JSON keys
+	var value interface{}
+	if err = json.Unmarshal(content, &value); err != nil {
+		return nil, stacktrace.Propagate(err, "cannot decode the JSON project bundle")
+	}
+
+	result, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot encode the project bundle as YAML")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/repositories"
+	"github.com/NdoleStudio/httpmock/pkg/requests"
+	"github.com/NdoleStudio/httpmock/pkg/services"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/NdoleStudio/httpmock/pkg/validators"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// DeadLetterHandler handles entities.DeadLetter requests from admins.
+type DeadLetterHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	validator *validators.DeadLetterHandlerValidator
+	service   *services.DeadLetterService
+}
+
+// NewDeadLetterHandler creates a new DeadLetterHandler
+func NewDeadLetterHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.DeadLetterHandlerValidator,
+	service *services.DeadLetterService,
+) (h *DeadLetterHandler) {
+	return &DeadLetterHandler{
+		logger:    logger.WithCodeNamespace(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		validator: validator,
+		service:   service,
+	}
+}
+
+// RegisterRoutes registers the routes for the DeadLetterHandler
+func (h *DeadLetterHandler) RegisterRoutes(app *fiber.App, middlewares []fiber.Handler) {
+	router := app.Group("/v1/dead-letters")
+	router.Get("/", h.computeRoute(h.index, middlewares)...)
+	router.Post("/:deadLetterId/replay", h.computeRoute(h.replay, middlewares)...)
+}
+
+// @Summary      List of dead letters
+// @Description  Fetches the events which an event listener could not handle after all the retries. Only admins can call this API.
+// @Security	 BearerAuth
+// @Tags         DeadLetters
+// @Produce      json
+// @Param        status		query  string  	false	"status of the dead letters"			Enums(failed, replayed)
+// @Param        limit		query  int  	false	"number of dead letters to return"		minimum(1)	maximum(100)
+// @Param        skip		query  int  	false	"number of dead letters to skip"
+// @Success      200 		{object}	responses.Ok[[]entities.DeadLetter]
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 403    	{object}	responses.Forbidden
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /v1/dead-letters 	[get]
+func (h *DeadLetterHandler) index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.DeadLetterIndexRequest
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params in [%s] into [%T]", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateIndex(request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching dead letters with url [%s]", spew.Sdump(errors), c.OriginalURL())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching dead letters")
+	}
+
+	deadLetters, err := h.service.Index(ctx, request.DeadLetterStatus(), request.Limit, request.Skip)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch dead letters with params [%+#v]", request)
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "dead letters fetched successfully", deadLetters)
+}
+
+// @Summary      Replay a dead letter
+// @Description  Publishes the event of a dead letter to the event listener which could not handle it. Only admins can call this API.
+// @Security	 BearerAuth
+// @Tags         DeadLetters
+// @Produce      json
+// @Param 		 deadLetterId	path 		string true "Dead Letter ID"
+// @Success      200 			{object}	responses.Ok[entities.DeadLetter]
+// @Failure      400			{object}	responses.BadRequest
+// @Failure 	 401    		{object}	responses.Unauthorized
+// @Failure 	 403    		{object}	responses.Forbidden
+// @Failure 	 404    		{object}	responses.NotFound
+// @Failure      422			{object}	responses.UnprocessableEntity
+// @Failure      500			{object}	responses.InternalServerError
+// @Router       /v1/dead-letters/{deadLetterId}/replay [post]
+func (h *DeadLetterHandler) replay(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	if validationErrors := h.mergeErrors(h.validateUUID(c, "deadLetterId")); len(validationErrors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while replaying dead letter with url [%s]", spew.Sdump(validationErrors), c.OriginalURL())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, validationErrors, "validation errors while replaying dead letter")
+	}
+
+	deadLetterID := uuid.MustParse(c.Params("deadLetterId"))
+	deadLetter, err := h.service.Replay(ctx, deadLetterID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("dead letter not found with ID [%s]", deadLetterID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseNotFound(c, msg)
+	}
+
+	if err != nil && deadLetter != nil {
+		msg := fmt.Sprintf("listener [%s] cannot handle the event of dead letter with ID [%s]", deadLetter.Listener, deadLetterID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseUnprocessableEntity(c, map[string][]string{"listener": {deadLetter.Error}}, msg)
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot replay dead letter with ID [%s]", deadLetterID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "dead letter replayed successfully", deadLetter)
+}
@@ -59,6 +59,13 @@ func (h *handler) responseUnprocessableEntity(c *fiber.Ctx, errors url.Values, m
 	})
 }
 
+func (h *handler) responsePaymentRequired(c *fiber.Ctx, message string) error {
+	return c.Status(fiber.StatusPaymentRequired).JSON(fiber.Map{
+		"status":  "error",
+		"message": message,
+	})
+}
+
 func (h *handler) responseNotFound(c *fiber.Ctx, message string) error {
 	return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 		"status":  "error",
@@ -66,6 +73,13 @@ func (h *handler) responseNotFound(c *fiber.Ctx, message string) error {
 	})
 }
 
+func (h *handler) responseNotImplemented(c *fiber.Ctx, message string) error {
+	return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
+		"status":  "error",
+		"message": message,
+	})
+}
+
 func (h *handler) responseNoContent(c *fiber.Ctx, message string) error {
 	return c.Status(fiber.StatusNoContent).JSON(fiber.Map{
 		"status":  "success",
@@ -5,8 +5,11 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/exporters"
 	"github.com/NdoleStudio/httpmock/pkg/repositories"
 	"github.com/NdoleStudio/httpmock/pkg/requests"
+	"github.com/NdoleStudio/httpmock/pkg/responses"
 	"github.com/davecgh/go-spew/spew"
 
 	"github.com/NdoleStudio/httpmock/pkg/services"
@@ -52,6 +55,10 @@ func (h *ProjectEndpointHandler) RegisterRoutes(app *fiber.App, middlewares []fi
 	router.Put("/:projectEndpointId", h.computeRoute(h.update, middlewares)...)
 	router.Delete("/:projectEndpointId", h.computeRoute(h.delete, middlewares)...)
 	router.Get("/:projectEndpointId/traffic", h.computeRoute(h.traffic, middlewares)...)
+
+	app.Post("/v1/projects/:projectId/import", h.computeRoute(h.importDocument, middlewares)...)
+	app.Get("/v1/projects/:projectId/bundle", h.computeRoute(h.exportBundle, middlewares)...)
+	app.Post("/v1/projects/:projectId/bundle", h.computeRoute(h.applyBundle, middlewares)...)
 }
 
 // @Summary      List of project endpoints
@@ -95,6 +102,7 @@ func (h *ProjectEndpointHandler) index(c *fiber.Ctx) error {
 // @Success      200 		{object}	responses.Ok[entities.ProjectEndpoint]
 // @Failure      400		{object}	responses.BadRequest
 // @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      402		{object}	responses.PaymentRequired
 // @Failure      422		{object}	responses.UnprocessableEntity
 // @Failure      500		{object}	responses.InternalServerError
 // @Router       /v1/projects/:projectId/endpoints 	[post]
@@ -126,6 +134,11 @@ func (h *ProjectEndpointHandler) store(c *fiber.Ctx) error {
 	}
 
 	endpoint, err := h.service.Store(ctx, project, request.ToProjectEndpointStorePrams(authUser.ID))
+	if stacktrace.GetCode(err) == services.ErrCodeSubscriptionLimit {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("user [%s] cannot create endpoint in project [%s]", authUser.ID, request.ProjectID)))
+		return h.responsePaymentRequired(c, "You have reached the limit of endpoints per project on your subscription. Upgrade your subscription to create more endpoints.")
+	}
+
 	if err != nil {
 		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot store project endpoint for project ID [%s] for user ID [%s]", request.ProjectID, authUser.ID)))
 		return h.responseInternalServerError(c)
@@ -331,3 +344,229 @@ func (h *ProjectEndpointHandler) traffic(c *fiber.Ctx) error {
 
 	return h.responseOK(c, "project endpoint traffic fetched successfully", timeSeries)
 }
+
+// @Summary      Import an OpenAPI document
+// @Description  This endpoint creates a project endpoint for every operation in an OpenAPI 3.x or Swagger 2.0 document in JSON or YAML format.
+// @Security	 BearerAuth
+// @Tags         ProjectEndpoints
+// @Accept       json
+// @Accept       x-yaml
+// @Produce      json
+// @Param 		 projectId	path 		string true "Project ID"
+// @Param        payload	body 		string	true 	"OpenAPI 3.x or Swagger 2.0 document"
+// @Success      200 		{object}	responses.Ok[responses.ProjectEndpointImport]
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      402		{object}	responses.PaymentRequired
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /v1/projects/{projectId}/import 	[post]
+func (h *ProjectEndpointHandler) importDocument(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	request := &requests.ProjectEndpointImportRequest{
+		ProjectID: c.Params("projectId"),
+		Document:  string(c.Body()),
+	}
+
+	if errors := h.validator.ValidateImport(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while importing document into project [%s]", spew.Sdump(errors), request.ProjectID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while importing document")
+	}
+
+	authUser := h.userFromContext(c)
+
+	project, err := h.projectService.Load(ctx, authUser.ID, uuid.MustParse(request.ProjectID))
+	if err != nil {
+		msg := fmt.Sprintf("cannot find project with id [%s] for user [%s]", request.ProjectID, authUser.ID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseNotFound(c, msg)
+	}
+
+	endpointRequests, err := request.ToProjectEndpointStoreRequests()
+	if err != nil {
+		msg := fmt.Sprintf("cannot create endpoints from document for project [%s]", request.ProjectID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	result := &responses.ProjectEndpointImport{
+		Endpoints: []*entities.ProjectEndpoint{},
+		Errors:    []*responses.ProjectEndpointImportError{},
+	}
+
+	var params []*services.ProjectEndpointStoreParams
+	for index, errors := range h.validator.ValidateImportEndpoints(ctx, authUser.ID, endpointRequests) {
+		if len(errors) != 0 {
+			result.Errors = append(result.Errors, &responses.ProjectEndpointImportError{
+				RequestMethod: endpointRequests[index].RequestMethod,
+				RequestPath:   endpointRequests[index].RequestPath,
+				Errors:        errors,
+			})
+			continue
+		}
+		params = append(params, endpointRequests[index].ToProjectEndpointStorePrams(authUser.ID))
+	}
+
+	endpoints, err := h.service.Import(ctx, project, params)
+	if stacktrace.GetCode(err) == services.ErrCodeSubscriptionLimit {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("user [%s] cannot import [%d] endpoints into project [%s]", authUser.ID, len(params), request.ProjectID)))
+		return h.responsePaymentRequired(c, "You have reached the limit of endpoints per project on your subscription. Upgrade your subscription to create more endpoints.")
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot import [%d] endpoints into project [%s] for user [%s]", len(params), request.ProjectID, authUser.ID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	result.Endpoints = append(result.Endpoints, endpoints...)
+	return h.responseOK(c, fmt.Sprintf("[%d] endpoints imported successfully", len(endpoints)), result)
+}
+
+// @Summary      Export a project bundle
+// @Description  This endpoint exports a project and all its endpoints as a versioned JSON or YAML bundle.
+// @Security	 BearerAuth
+// @Tags         ProjectEndpoints
+// @Produce      json
+// @Produce      x-yaml
+// @Param 		 projectId	path 		string true "Project ID"
+// @Param        format		query 		string false "Bundle format" Enums(json, yaml) default(json)
+// @Success      200 		{object}	exporters.ProjectBundle
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /v1/projects/{projectId}/bundle 	[get]
+func (h *ProjectEndpointHandler) exportBundle(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ProjectBundleExportRequest
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.ProjectID = c.Params("projectId")
+	if errors := h.validator.ValidateBundleExport(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while exporting bundle with url [%s]", spew.Sdump(errors), c.OriginalURL())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while exporting project bundle")
+	}
+
+	authUser := h.userFromContext(c)
+	projectID := uuid.MustParse(request.ProjectID)
+
+	project, err := h.projectService.Load(ctx, authUser.ID, projectID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot find project with id [%s] for user [%s]", projectID, authUser.ID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseNotFound(c, msg)
+	}
+
+	endpoints, err := h.service.Index(ctx, authUser.ID, projectID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch endpoints for project [%s] and user [%s]", projectID, authUser.ID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	bundle := exporters.NewProjectBundle(project, endpoints)
+	if request.Format == "json" {
+		c.Attachment(fmt.Sprintf("%s.httpmock.json", project.Subdomain))
+		return c.JSON(bundle)
+	}
+
+	content, err := bundle.YAML()
+	if err != nil {
+		msg := fmt.Sprintf("cannot encode bundle for project [%s] as YAML", projectID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	c.Attachment(fmt.Sprintf("%s.httpmock.yaml", project.Subdomain))
+	c.Set(fiber.HeaderContentType, "application/x-yaml")
+	return c.Send(content)
+}
+
+// @Summary      Apply a project bundle
+// @Description  This endpoint creates, updates and deletes the project endpoints so that they are identical to the endpoints in a JSON or YAML bundle. Use dry_run to preview the changes.
+// @Security	 BearerAuth
+// @Tags         ProjectEndpoints
+// @Accept       json
+// @Accept       x-yaml
+// @Produce      json
+// @Param 		 projectId	path 		string true "Project ID"
+// @Param        dry_run	query 		bool false "Preview the changes without applying them"
+// @Param        payload	body 		exporters.ProjectBundle	true 	"project bundle"
+// @Success      200 		{object}	responses.Ok[services.ProjectEndpointSyncResult]
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      402		{object}	responses.PaymentRequired
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /v1/projects/{projectId}/bundle 	[post]
+func (h *ProjectEndpointHandler) applyBundle(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ProjectBundleApplyRequest
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.ProjectID = c.Params("projectId")
+	request.Document = string(c.Body())
+	if errors := h.validator.ValidateBundleApply(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while applying bundle to project [%s]", spew.Sdump(errors), request.ProjectID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while applying project bundle")
+	}
+
+	authUser := h.userFromContext(c)
+
+	project, err := h.projectService.Load(ctx, authUser.ID, uuid.MustParse(request.ProjectID))
+	if err != nil {
+		msg := fmt.Sprintf("cannot find project with id [%s] for user [%s]", request.ProjectID, authUser.ID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseNotFound(c, msg)
+	}
+
+	endpointRequests, err := request.ToProjectEndpointStoreRequests()
+	if err != nil {
+		msg := fmt.Sprintf("cannot create endpoints from bundle for project [%s]", request.ProjectID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	params := make([]*services.ProjectEndpointStoreParams, 0, len(endpointRequests))
+	for _, endpointRequest := range endpointRequests {
+		params = append(params, endpointRequest.ToProjectEndpointStorePrams(authUser.ID))
+	}
+
+	result, err := h.service.Sync(ctx, project, params, request.DryRun)
+	if stacktrace.GetCode(err) == services.ErrCodeSubscriptionLimit {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("user [%s] cannot apply bundle with [%d] endpoints to project [%s]", authUser.ID, len(params), request.ProjectID)))
+		return h.responsePaymentRequired(c, "You have reached the limit of endpoints per project on your subscription. Upgrade your subscription to create more endpoints.")
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot apply bundle to project [%s] for user [%s] with dry run [%t]", request.ProjectID, authUser.ID, request.DryRun)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	if request.DryRun {
+		return h.responseOK(c, "project bundle changes computed successfully", result)
+	}
+	return h.responseOK(c, "project bundle applied successfully", result)
+}
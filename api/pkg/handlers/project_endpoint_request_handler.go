@@ -26,6 +26,7 @@ type ProjectEndpointRequestHandler struct {
 	validator              *validators.ProjectEndpointRequestHandlerValidator
 	projectEndpointService *services.ProjectEndpointService
 	service                *services.ProjectEndpointRequestService
+	callbackService        *services.ProjectEndpointCallbackService
 }
 
 // NewProjectEndpointRequestHandler creates a new ProjectEndpointRequestHandler
@@ -35,6 +36,7 @@ func NewProjectEndpointRequestHandler(
 	validator *validators.ProjectEndpointRequestHandlerValidator,
 	service *services.ProjectEndpointRequestService,
 	projectEndpointService *services.ProjectEndpointService,
+	callbackService *services.ProjectEndpointCallbackService,
 ) (h *ProjectEndpointRequestHandler) {
 	return &ProjectEndpointRequestHandler{
 		logger:                 logger.WithCodeNamespace(fmt.Sprintf("%T", h)),
@@ -42,6 +44,7 @@ func NewProjectEndpointRequestHandler(
 		validator:              validator,
 		service:                service,
 		projectEndpointService: projectEndpointService,
+		callbackService:        callbackService,
 	}
 }
 
@@ -49,6 +52,7 @@ func NewProjectEndpointRequestHandler(
 func (h *ProjectEndpointRequestHandler) RegisterRoutes(app *fiber.App, middlewares []fiber.Handler) {
 	router := app.Group("/v1/projects/:projectId/endpoints/:projectEndpointId/requests")
 	router.Get("/", h.computeRoute(h.index, middlewares)...)
+	router.Get("/:projectEndpointRequestId/callbacks", h.computeRoute(h.callbacks, middlewares)...)
 	router.Delete("/:projectEndpointRequestId", h.computeRoute(h.delete, middlewares)...)
 }
 
@@ -96,6 +100,41 @@ func (h *ProjectEndpointRequestHandler) index(c *fiber.Ctx) error {
 	return h.responseOK(c, "project endpoint requests fetched successfully", endpointRequests)
 }
 
+// @Summary      List the callback attempts of a project endpoint request
+// @Description  Fetches the attempts to send the callbacks which were triggered by a project endpoint request, the oldest attempt first
+// @Security	 BearerAuth
+// @Tags         ProjectEndpointRequests
+// @Produce      json
+// @Param 		 projectId					path 		string true "Project ID"
+// @Param 		 projectEndpointId			path 		string true "Project Endpoint ID"
+// @Param 		 projectEndpointRequestId	path 		string true "Project Endpoint Request ID"
+// @Success      200 						{object}	responses.Ok[[]entities.ProjectEndpointCallbackAttempt]
+// @Failure      400						{object}	responses.BadRequest
+// @Failure 	 401    					{object}	responses.Unauthorized
+// @Failure      422						{object}	responses.UnprocessableEntity
+// @Failure      500						{object}	responses.InternalServerError
+// @Router       /v1/projects/{projectId}/endpoints/{projectEndpointId}/requests/{projectEndpointRequestId}/callbacks [get]
+func (h *ProjectEndpointRequestHandler) callbacks(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	if validationErrors := h.mergeErrors(h.validateULID(c, "projectEndpointRequestId")); len(validationErrors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching callback attempts with url [%s]", spew.Sdump(validationErrors), c.OriginalURL())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, validationErrors, "validation errors while fetching callback attempts")
+	}
+
+	requestID := ulid.MustParse(c.Params("projectEndpointRequestId"))
+	attempts, err := h.callbackService.Index(ctx, h.userIDFomContext(c), requestID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch callback attempts of project endpoint request with ID [%s] for user [%s]", requestID, h.userIDFomContext(c))
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "callback attempts fetched successfully", attempts)
+}
+
 // @Summary      Delete a project endpoint request
 // @Description  This API deletes a project endpoint request for a user
 // @Security	 BearerAuth
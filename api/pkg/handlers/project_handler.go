@@ -19,10 +19,11 @@ import (
 // ProjectHandler handles user http requests.
 type ProjectHandler struct {
 	handler
-	logger    telemetry.Logger
-	tracer    telemetry.Tracer
-	validator *validators.ProjectHandlerValidator
-	service   *services.ProjectService
+	logger        telemetry.Logger
+	tracer        telemetry.Tracer
+	validator     *validators.ProjectHandlerValidator
+	service       *services.ProjectService
+	exportService *services.ProjectExportService
 }
 
 // NewProjectHandler creates a new ProjectHandler
@@ -31,12 +32,14 @@ func NewProjectHandler(
 	tracer telemetry.Tracer,
 	validator *validators.ProjectHandlerValidator,
 	service *services.ProjectService,
+	exportService *services.ProjectExportService,
 ) (h *ProjectHandler) {
 	return &ProjectHandler{
-		logger:    logger.WithCodeNamespace(fmt.Sprintf("%T", h)),
-		tracer:    tracer,
-		validator: validator,
-		service:   service,
+		logger:        logger.WithCodeNamespace(fmt.Sprintf("%T", h)),
+		tracer:        tracer,
+		validator:     validator,
+		service:       service,
+		exportService: exportService,
 	}
 }
 
@@ -49,6 +52,11 @@ func (h *ProjectHandler) RegisterRoutes(app *fiber.App, middlewares []fiber.Hand
 	router.Put("/:projectId", h.computeRoute(h.update, middlewares)...)
 	router.Delete("/:projectId", h.computeRoute(h.delete, middlewares)...)
 	router.Get("/:projectId/traffic", h.computeRoute(h.traffic, middlewares)...)
+	router.Get("/:projectId/scenarios", h.computeRoute(h.scenarios, middlewares)...)
+	router.Post("/:projectId/scenarios/reset", h.computeRoute(h.resetScenarios, middlewares)...)
+	router.Post("/:projectId/record", h.computeRoute(h.record, middlewares)...)
+	router.Post("/:projectId/playback", h.computeRoute(h.playback, middlewares)...)
+	router.Get("/:projectId/export", h.computeRoute(h.export, middlewares)...)
 }
 
 // @Summary      List of projects
@@ -87,6 +95,7 @@ func (h *ProjectHandler) index(c *fiber.Ctx) error {
 // @Success      200 		{object}	responses.Ok[entities.Project]
 // @Failure      400		{object}	responses.BadRequest
 // @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      402		{object}	responses.PaymentRequired
 // @Failure      422		{object}	responses.UnprocessableEntity
 // @Failure      500		{object}	responses.InternalServerError
 // @Router       /v1/projects 	[post]
@@ -110,6 +119,11 @@ func (h *ProjectHandler) create(c *fiber.Ctx) error {
 	authUser := h.userFromContext(c)
 
 	project, err := h.service.Create(ctx, request.ToProjectCreateParams(c.OriginalURL(), authUser.ID))
+	if stacktrace.GetCode(err) == services.ErrCodeSubscriptionLimit {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("user [%s] cannot create project [%s]", authUser.ID, request.Name)))
+		return h.responsePaymentRequired(c, "You have reached the limit of projects on your subscription. Upgrade your subscription to create more projects.")
+	}
+
 	if err != nil {
 		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot store project [%s] for user [%s]", request.Name, authUser.ID)))
 		return h.responseInternalServerError(c)
@@ -289,3 +303,229 @@ func (h *ProjectHandler) traffic(c *fiber.Ctx) error {
 
 	return h.responseOK(c, "project traffic fetched successfully", timeSeries)
 }
+
+// @Summary      Get project scenarios
+// @Description  This endpoint returns the current state of all the scenarios used by the project endpoints.
+// @Security	 BearerAuth
+// @Tags         Projects
+// @Produce      json
+// @Param 		 projectId	path 		string true "Project ID"
+// @Success      200 		{object}	responses.Ok[[]entities.ProjectScenario]
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /v1/projects/{projectId}/scenarios 	[get]
+func (h *ProjectHandler) scenarios(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	if errors := h.validator.ValidateUUID(c, "projectId"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching project scenarios with url [%s]", spew.Sdump(errors), c.OriginalURL())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching project scenarios")
+	}
+
+	projectID := uuid.MustParse(c.Params("projectId"))
+	authUser := h.userFromContext(c)
+
+	scenarios, err := h.service.Scenarios(ctx, authUser.ID, projectID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("cannot load scenarios for project with id [%s] for user [%s]", projectID, authUser.ID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseNotFound(c, msg)
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load scenarios for project [%s] user with ID [%s]", projectID, authUser.ID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "project scenarios fetched successfully", scenarios)
+}
+
+// @Summary      Reset project scenarios
+// @Description  This endpoint resets all the scenarios used by the project endpoints to the "Started" state.
+// @Security	 BearerAuth
+// @Tags         Projects
+// @Produce      json
+// @Param 		 projectId	path 		string true "Project ID"
+// @Success      200 		{object}	responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /v1/projects/{projectId}/scenarios/reset 	[post]
+func (h *ProjectHandler) resetScenarios(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	if errors := h.validator.ValidateUUID(c, "projectId"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while resetting project scenarios with url [%s]", spew.Sdump(errors), c.OriginalURL())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while resetting project scenarios")
+	}
+
+	projectID := uuid.MustParse(c.Params("projectId"))
+	authUser := h.userFromContext(c)
+
+	err := h.service.ResetScenarios(ctx, authUser.ID, projectID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("cannot reset scenarios for project with id [%s] for user [%s]", projectID, authUser.ID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseNotFound(c, msg)
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot reset scenarios for project [%s] user with ID [%s]", projectID, authUser.ID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "project scenarios reset successfully")
+}
+
+// @Summary      Start recording a project
+// @Description  This endpoint forwards unmatched requests to the upstream URL and saves each new method and path as an endpoint.
+// @Security	 BearerAuth
+// @Tags         Projects
+// @Produce      json
+// @Param 		 projectId	path 		string true "Project ID"
+// @Success      200 		{object}	responses.Ok[entities.Project]
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /v1/projects/{projectId}/record 	[post]
+func (h *ProjectHandler) record(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	if errors := h.validator.ValidateUUID(c, "projectId"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while recording project with url [%s]", spew.Sdump(errors), c.OriginalURL())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while recording project")
+	}
+
+	projectID := uuid.MustParse(c.Params("projectId"))
+	authUser := h.userFromContext(c)
+
+	if errors := h.validator.ValidateRecord(ctx, authUser.ID, projectID); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while recording project [%s]", spew.Sdump(errors), projectID)
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while recording project")
+	}
+
+	project, err := h.service.Record(ctx, authUser.ID, projectID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("cannot start recording project with id [%s] for user [%s]", projectID, authUser.ID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseNotFound(c, msg)
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot start recording project [%s] user with ID [%s]", projectID, authUser.ID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "project recording started successfully", project)
+}
+
+// @Summary      Stop recording a project
+// @Description  This endpoint stops recording requests to the upstream URL so that the recorded endpoints are played back.
+// @Security	 BearerAuth
+// @Tags         Projects
+// @Produce      json
+// @Param 		 projectId	path 		string true "Project ID"
+// @Success      200 		{object}	responses.Ok[entities.Project]
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /v1/projects/{projectId}/playback 	[post]
+func (h *ProjectHandler) playback(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	if errors := h.validator.ValidateUUID(c, "projectId"); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while stopping project recording with url [%s]", spew.Sdump(errors), c.OriginalURL())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while stopping project recording")
+	}
+
+	projectID := uuid.MustParse(c.Params("projectId"))
+	authUser := h.userFromContext(c)
+
+	project, err := h.service.Playback(ctx, authUser.ID, projectID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("cannot stop recording project with id [%s] for user [%s]", projectID, authUser.ID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseNotFound(c, msg)
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot stop recording project [%s] user with ID [%s]", projectID, authUser.ID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "project recording stopped successfully", project)
+}
+
+// @Summary      Export a project
+// @Description  This endpoint exports the project endpoints as an OpenAPI 3.0 document or a Postman v2.1 collection and the recorded requests as a HAR file.
+// @Security	 BearerAuth
+// @Tags         Projects
+// @Produce      json
+// @Param 		 projectId	path 		string true "Project ID"
+// @Param        format		query 		string false "Export format" Enums(openapi, postman, har) default(openapi)
+// @Success      200 		{object}	object
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /v1/projects/{projectId}/export 	[get]
+func (h *ProjectHandler) export(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ProjectExportRequest
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.ProjectID = c.Params("projectId")
+	if errors := h.validator.ValidateExport(ctx, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while exporting project with url [%s]", spew.Sdump(errors), c.OriginalURL())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while exporting project")
+	}
+
+	authUser := h.userFromContext(c)
+	projectID := uuid.MustParse(request.ProjectID)
+
+	export, err := h.exportService.Export(ctx, authUser.ID, projectID, services.ProjectExportFormat(request.Format))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("cannot export project with id [%s] for user [%s]", projectID, authUser.ID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseNotFound(c, msg)
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot export project [%s] in format [%s] for user with ID [%s]", projectID, request.Format, authUser.ID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	c.Attachment(request.Filename())
+	return c.JSON(export)
+}
@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/davecgh/go-spew/spew"
+
+	"github.com/NdoleStudio/httpmock/pkg/requests"
+	"github.com/NdoleStudio/httpmock/pkg/services"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/NdoleStudio/httpmock/pkg/validators"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// streamBufferSize is the number of events which are kept for a slow stream before new events are dropped
+	streamBufferSize = 100
+
+	// streamKeepAliveInterval is the interval at which a comment is sent to keep an idle stream open
+	streamKeepAliveInterval = 15 * time.Second
+)
+
+// ProjectRequestHandler handles the entities.ProjectEndpointRequest of all the endpoints of an entities.Project
+type ProjectRequestHandler struct {
+	handler
+	logger         telemetry.Logger
+	tracer         telemetry.Tracer
+	validator      *validators.ProjectRequestHandlerValidator
+	projectService *services.ProjectService
+	requestService *services.ProjectEndpointRequestService
+	broker         *services.EventBroker
+}
+
+// NewProjectRequestHandler creates a new ProjectRequestHandler
+func NewProjectRequestHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.ProjectRequestHandlerValidator,
+	projectService *services.ProjectService,
+	requestService *services.ProjectEndpointRequestService,
+	broker *services.EventBroker,
+) (h *ProjectRequestHandler) {
+	return &ProjectRequestHandler{
+		logger:         logger.WithCodeNamespace(fmt.Sprintf("%T", h)),
+		tracer:         tracer,
+		validator:      validator,
+		projectService: projectService,
+		requestService: requestService,
+		broker:         broker,
+	}
+}
+
+// RegisterRoutes registers the routes for the ProjectRequestHandler
+func (h *ProjectRequestHandler) RegisterRoutes(app *fiber.App, middlewares []fiber.Handler) {
+	router := app.Group("/v1/projects/:projectId/requests")
+	router.Get("/", h.computeRoute(h.index, middlewares)...)
+	router.Get("/search", h.computeRoute(h.search, middlewares)...)
+	router.Get("/stream", h.computeRoute(h.stream, middlewares)...)
+}
+
+// @Summary      List the requests of a project
+// @Description  Fetches the requests of all the endpoints of a project including the requests which did not match any endpoint, the newest request first
+// @Security	 BearerAuth
+// @Tags         ProjectRequests
+// @Produce      json
+// @Param 		 projectId	path 	string	true	"Project ID"
+// @Param        unmatched	query	bool	false	"only return the requests which did not match any endpoint when true or which matched an endpoint when false"
+// @Param        prev		query	string	false	"ID of the last request returned in the previous page"
+// @Param        next		query	string	false	"ID of the first request returned in the current page"
+// @Param        limit		query	int		false	"number of requests to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.Ok[[]entities.ProjectEndpointRequest]
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /v1/projects/{projectId}/requests 	[get]
+func (h *ProjectRequestHandler) index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ProjectRequestIndexRequest
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params in [%s] into [%T]", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.ProjectID = c.Params("projectId")
+	if errors := h.validator.ValidateIndex(request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching project requests with url [%s]", spew.Sdump(errors), c.OriginalURL())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching project requests")
+	}
+
+	projectRequests, err := h.requestService.Search(ctx, h.userIDFomContext(c), request.ToSearchParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch requests for user with ID [%s] and project ID [%s]", h.userIDFomContext(c), request.ProjectID)
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "project requests fetched successfully", projectRequests)
+}
+
+// @Summary      Search the requests of a project
+// @Description  Fetches the requests of all the endpoints of a project which match the filters, the newest request first
+// @Security	 BearerAuth
+// @Tags         ProjectRequests
+// @Produce      json
+// @Param 		 projectId		path 	string	true	"Project ID"
+// @Param        method			query	string	false	"HTTP method of the request e.g. POST"
+// @Param        response_code	query	int		false	"status code of the response e.g. 404"	minimum(100)	maximum(599)
+// @Param        path_prefix	query	string	false	"prefix of the request path e.g. /v1/products"
+// @Param        ip_address		query	string	false	"IP address of the client"
+// @Param        from			query	string	false	"RFC3339 time at or after which the requests were created"
+// @Param        to				query	string	false	"RFC3339 time before which the requests were created"
+// @Param        header_key		query	string	false	"name of a request header, the case is ignored"
+// @Param        header_value	query	string	false	"value of the request header with the header_key"
+// @Param        query			query	string	false	"text in the request body, the case is ignored"
+// @Param        json_path		query	string	false	"JSONPath expression which selects a value in the JSON request body e.g. $.data.id"
+// @Param        json_value		query	string	false	"value selected by the json_path"
+// @Param        unmatched		query	bool	false	"only return the requests which did not match any endpoint when true or which matched an endpoint when false"
+// @Param        prev			query	string	false	"ID of the last request returned in the previous page"
+// @Param        next			query	string	false	"ID of the first request returned in the current page"
+// @Param        limit			query	int		false	"number of requests to return"	minimum(1)	maximum(100)
+// @Success      200 			{object}	responses.Ok[[]entities.ProjectEndpointRequest]
+// @Failure      400			{object}	responses.BadRequest
+// @Failure 	 401    		{object}	responses.Unauthorized
+// @Failure      422			{object}	responses.UnprocessableEntity
+// @Failure      500			{object}	responses.InternalServerError
+// @Router       /v1/projects/{projectId}/requests/search 	[get]
+func (h *ProjectRequestHandler) search(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ProjectRequestSearchRequest
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params in [%s] into [%T]", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.ProjectID = c.Params("projectId")
+	if errors := h.validator.ValidateSearch(request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while searching project requests with url [%s]", spew.Sdump(errors), c.OriginalURL())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while searching project requests")
+	}
+
+	projectRequests, err := h.requestService.Search(ctx, h.userIDFomContext(c), request.ToSearchParams())
+	if err != nil {
+		msg := fmt.Sprintf("cannot search requests for user with ID [%s] and project ID [%s]", h.userIDFomContext(c), request.ProjectID)
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "project requests fetched successfully", projectRequests)
+}
+
+// @Summary      Stream the requests of a project
+// @Description  Streams the project.endpoint.request CloudEvents of all the endpoints of a project as Server-Sent Events while the connection is open
+// @Security	 BearerAuth
+// @Tags         ProjectRequests
+// @Produce      text/event-stream
+// @Param 		 projectId	path 		string true "Project ID"
+// @Success      200 		{string}	string
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      500		{object}	responses.InternalServerError
+// @Failure      501		{object}	responses.NotImplemented
+// @Router       /v1/projects/{projectId}/requests/stream 	[get]
+func (h *ProjectRequestHandler) stream(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	if !h.broker.Enabled() {
+		ctxLogger.Warn(stacktrace.NewError(fmt.Sprintf("cannot stream requests for project [%s] because the event broker is disabled", c.Params("projectId"))))
+		return h.responseNotImplemented(c, "Streaming requests is only available with the memory, redis or nats queue backends.")
+	}
+
+	if errors := h.mergeErrors(h.validateUUID(c, "projectId")); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while streaming requests with url [%s]", spew.Sdump(errors), c.OriginalURL())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseNotFound(c, fmt.Sprintf("cannot stream requests for project with ID [%s]", c.Params("projectId")))
+	}
+
+	authUser := h.userFromContext(c)
+	project, err := h.projectService.Load(ctx, authUser.ID, uuid.MustParse(c.Params("projectId")))
+	if err != nil {
+		msg := fmt.Sprintf("cannot find project with id [%s] for user [%s]", c.Params("projectId"), authUser.ID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseNotFound(c, msg)
+	}
+
+	events, unsubscribe := h.broker.Subscribe(services.ProjectRequestsTopic(authUser.ID, project.ID), streamBufferSize)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		ticker := time.NewTicker(streamKeepAliveInterval)
+		defer ticker.Stop()
+
+		if _, err := w.WriteString(": connected\n\n"); err != nil || w.Flush() != nil {
+			return
+		}
+
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if err := h.writeEvent(w, event); err != nil {
+					ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot write event [%s] to the stream of project [%s]", event.ID(), project.ID)))
+					return
+				}
+			case <-ticker.C:
+				if _, err := w.WriteString(": keep-alive\n\n"); err != nil {
+					return
+				}
+			}
+
+			// flushing fails when the client closes the connection
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+// writeEvent writes a cloudevents.Event as a Server-Sent Event with the JSON encoded event as data
+func (h *ProjectRequestHandler) writeEvent(w *bufio.Writer, event cloudevents.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot marshal event with ID [%s]", event.ID()))
+	}
+
+	if _, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID(), event.Type(), data); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot write event with ID [%s]", event.ID()))
+	}
+
+	return nil
+}
@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpmock/pkg/repositories"
+	"github.com/NdoleStudio/httpmock/pkg/requests"
+	"github.com/davecgh/go-spew/spew"
+
+	"github.com/NdoleStudio/httpmock/pkg/services"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/NdoleStudio/httpmock/pkg/validators"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// ProjectWebhookHandler handles entities.ProjectWebhook requests.
+type ProjectWebhookHandler struct {
+	handler
+	logger         telemetry.Logger
+	tracer         telemetry.Tracer
+	validator      *validators.ProjectWebhookHandlerValidator
+	projectService *services.ProjectService
+	service        *services.ProjectWebhookService
+}
+
+// NewProjectWebhookHandler creates a new ProjectWebhookHandler
+func NewProjectWebhookHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.ProjectWebhookHandlerValidator,
+	service *services.ProjectWebhookService,
+	projectService *services.ProjectService,
+) (h *ProjectWebhookHandler) {
+	return &ProjectWebhookHandler{
+		logger:         logger.WithCodeNamespace(fmt.Sprintf("%T", h)),
+		tracer:         tracer,
+		validator:      validator,
+		service:        service,
+		projectService: projectService,
+	}
+}
+
+// RegisterRoutes registers the routes for the ProjectWebhookHandler
+func (h *ProjectWebhookHandler) RegisterRoutes(app *fiber.App, middlewares []fiber.Handler) {
+	router := app.Group("/v1/projects/:projectId/webhooks")
+	router.Get("/", h.computeRoute(h.index, middlewares)...)
+	router.Post("/", h.computeRoute(h.store, middlewares)...)
+	router.Put("/:projectWebhookId", h.computeRoute(h.update, middlewares)...)
+	router.Delete("/:projectWebhookId", h.computeRoute(h.delete, middlewares)...)
+	router.Get("/:projectWebhookId/deliveries", h.computeRoute(h.deliveries, middlewares)...)
+}
+
+// @Summary      List of project webhooks
+// @Description  Fetches the webhooks which receive the requests captured by a project
+// @Security	 BearerAuth
+// @Tags         ProjectWebhooks
+// @Produce      json
+// @Param 		 projectId	path 		string true "Project ID"
+// @Success      200 		{object}	responses.Ok[[]entities.ProjectWebhook]
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /v1/projects/{projectId}/webhooks 	[get]
+func (h *ProjectWebhookHandler) index(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	if errors := h.mergeErrors(h.validateUUID(c, "projectId")); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], fetching webhooks with url [%s]", spew.Sdump(errors), c.OriginalURL())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseNotFound(c, fmt.Sprintf("cannot list webhooks for project with ID [%s]", c.Params("projectId")))
+	}
+
+	authUser := h.userFromContext(c)
+	webhooks, err := h.service.Index(ctx, authUser.ID, uuid.MustParse(c.Params("projectId")))
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch project webhooks for user with ID [%s] and projectID [%s]", authUser.ID, c.Params("projectId"))
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "project webhooks fetched successfully", webhooks)
+}
+
+// @Summary      Store a new project webhook
+// @Description  Registers an HTTPS URL which receives every request captured by the project as a signed CloudEvent
+// @Security	 BearerAuth
+// @Tags         ProjectWebhooks
+// @Produce      json
+// @Param 		 projectId	path 		string true "Project ID"
+// @Param        payload	body 		requests.ProjectWebhookStoreRequest	true 	"project webhook store payload"
+// @Success      200 		{object}	responses.Ok[entities.ProjectWebhook]
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404    	{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /v1/projects/{projectId}/webhooks 	[post]
+func (h *ProjectWebhookHandler) store(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ProjectWebhookStoreRequest
+	if err := c.BodyParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	authUser := h.userFromContext(c)
+	request.ProjectID = c.Params("projectId")
+
+	if errors := h.validator.ValidateStore(ctx, authUser.ID, request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while storing project webhook with request [%s]", spew.Sdump(errors), c.Body())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing project webhook")
+	}
+
+	if _, err := h.projectService.Load(ctx, authUser.ID, uuid.MustParse(request.ProjectID)); err != nil {
+		msg := fmt.Sprintf("cannot find project with id [%s] for user [%s]", request.ProjectID, authUser.ID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseNotFound(c, msg)
+	}
+
+	webhook, err := h.service.Store(ctx, request.ToProjectWebhookStoreParams(authUser.ID))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot store project webhook for project ID [%s] for user ID [%s]", request.ProjectID, authUser.ID)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "webhook created successfully", webhook)
+}
+
+// @Summary      Update a project webhook
+// @Description  Updates the URL, the mode or the signing secret of a project webhook. The signing secret is not changed when it is empty.
+// @Security	 BearerAuth
+// @Tags         ProjectWebhooks
+// @Produce      json
+// @Param 		 projectId			path		string true "Project ID"
+// @Param 		 projectWebhookId	path		string true "Project Webhook ID"
+// @Param        payload			body		requests.ProjectWebhookUpdateRequest	true 	"project webhook update payload"
+// @Success      200 				{object}	responses.Ok[entities.ProjectWebhook]
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404    			{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /v1/projects/{projectId}/webhooks/{projectWebhookId}	[put]
+func (h *ProjectWebhookHandler) update(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	request := new(requests.ProjectWebhookUpdateRequest)
+	if err := c.BodyParser(request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params [%s] into %T", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	authUser := h.userFromContext(c)
+	request.ProjectID = c.Params("projectId")
+	request.ProjectWebhookID = c.Params("projectWebhookId")
+
+	if errors := h.validator.ValidateUpdate(request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while updating project webhook with request [%s]", spew.Sdump(errors), c.Body())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating project webhook")
+	}
+
+	webhook, err := h.service.Update(ctx, request.ToProjectWebhookUpdateParams(authUser.ID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("cannot find project webhook with ID [%s] and project id [%s] for user [%s]", request.ProjectWebhookID, request.ProjectID, authUser.ID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseNotFound(c, msg)
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot update project webhook with ID [%s] and project id [%s] for user [%s]", request.ProjectWebhookID, request.ProjectID, authUser.ID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "webhook updated successfully", webhook)
+}
+
+// @Summary      Delete a project webhook
+// @Description  Deletes a project webhook and its delivery log
+// @Security	 BearerAuth
+// @Tags         ProjectWebhooks
+// @Produce      json
+// @Param 		 projectId			path 		string true "Project ID"
+// @Param 		 projectWebhookId	path 		string true "Project Webhook ID"
+// @Success      204 				{object}	responses.NoContent
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404    			{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /v1/projects/{projectId}/webhooks/{projectWebhookId} [delete]
+func (h *ProjectWebhookHandler) delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	if errors := h.mergeErrors(h.validateUUID(c, "projectId"), h.validateUUID(c, "projectWebhookId")); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while deleting project webhook with url [%s]", spew.Sdump(errors), c.OriginalURL())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting project webhook")
+	}
+
+	authUser := h.userFromContext(c)
+	projectID := uuid.MustParse(c.Params("projectId"))
+	webhookID := uuid.MustParse(c.Params("projectWebhookId"))
+
+	err := h.service.Delete(ctx, authUser.ID, projectID, webhookID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("project webhook not found with ID [%s] and project id [%s] for user [%s]", webhookID, projectID, authUser.ID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseNotFound(c, msg)
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete project webhook with ID [%s] and project id [%s] for user [%s]", webhookID, projectID, authUser.ID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "project webhook deleted successfully")
+}
+
+// @Summary      List the deliveries of a project webhook
+// @Description  Fetches the latest attempts to deliver events to a project webhook, the newest attempt first
+// @Security	 BearerAuth
+// @Tags         ProjectWebhooks
+// @Produce      json
+// @Param 		 projectId			path 		string true "Project ID"
+// @Param 		 projectWebhookId	path 		string true "Project Webhook ID"
+// @Param        limit				query  		int  	false	"number of deliveries to return"	minimum(1)	maximum(100)
+// @Success      200 				{object}	responses.Ok[[]entities.ProjectWebhookDelivery]
+// @Failure      400				{object}	responses.BadRequest
+// @Failure 	 401    			{object}	responses.Unauthorized
+// @Failure 	 404    			{object}	responses.NotFound
+// @Failure      422				{object}	responses.UnprocessableEntity
+// @Failure      500				{object}	responses.InternalServerError
+// @Router       /v1/projects/{projectId}/webhooks/{projectWebhookId}/deliveries [get]
+func (h *ProjectWebhookHandler) deliveries(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ProjectWebhookDeliveryIndexRequest
+	if err := c.QueryParser(&request); err != nil {
+		msg := fmt.Sprintf("cannot marshall params in [%s] into [%T]", c.OriginalURL(), request)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.ProjectID = c.Params("projectId")
+	request.ProjectWebhookID = c.Params("projectWebhookId")
+
+	if errors := h.validator.ValidateDeliveries(request.Sanitize()); len(errors) != 0 {
+		msg := fmt.Sprintf("validation errors [%s], while fetching webhook deliveries with url [%s]", spew.Sdump(errors), c.OriginalURL())
+		ctxLogger.Warn(stacktrace.NewError(msg))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching webhook deliveries")
+	}
+
+	authUser := h.userFromContext(c)
+	projectID := uuid.MustParse(request.ProjectID)
+	webhookID := uuid.MustParse(request.ProjectWebhookID)
+
+	deliveries, err := h.service.Deliveries(ctx, authUser.ID, projectID, webhookID, request.Limit)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("project webhook not found with ID [%s] and project id [%s] for user [%s]", webhookID, projectID, authUser.ID)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return h.responseNotFound(c, msg)
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch deliveries of project webhook with ID [%s] for user [%s]", webhookID, authUser.ID)
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "webhook deliveries fetched successfully", deliveries)
+}
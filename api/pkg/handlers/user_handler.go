@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/services"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// UserHandler handles requests for the authenticated entities.User
+type UserHandler struct {
+	handler
+	logger              telemetry.Logger
+	tracer              telemetry.Tracer
+	subscriptionService *services.SubscriptionService
+	userService         *services.UserService
+}
+
+// NewUserHandler creates a new UserHandler
+func NewUserHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	subscriptionService *services.SubscriptionService,
+	userService *services.UserService,
+) (h *UserHandler) {
+	return &UserHandler{
+		logger:              logger.WithCodeNamespace(fmt.Sprintf("%T", h)),
+		tracer:              tracer,
+		subscriptionService: subscriptionService,
+		userService:         userService,
+	}
+}
+
+// RegisterRoutes registers the routes for the UserHandler
+func (h *UserHandler) RegisterRoutes(app *fiber.App, middlewares []fiber.Handler) {
+	router := app.Group("/v1/users")
+	router.Get("/me/usage", h.computeRoute(h.usage, middlewares)...)
+	router.Delete("/me", h.computeRoute(h.delete, middlewares)...)
+}
+
+// @Summary      Get the usage of the current user
+// @Description  Fetches the number of mock requests made by the authenticated user in the current billing period and the limits of their subscription
+// @Security	 BearerAuth
+// @Tags         Users
+// @Produce      json
+// @Success      200 		{object}	responses.Ok[services.SubscriptionUsage]
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /v1/users/me/usage 	[get]
+func (h *UserHandler) usage(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	userID := h.userIDFomContext(c)
+
+	usage, err := h.subscriptionService.Usage(ctx, userID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load usage of user with ID [%s]", userID)
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "usage fetched successfully", usage)
+}
+
+// @Summary      Delete the current user
+// @Description  Cancels the subscription of the authenticated user and deletes their account. All the projects, endpoints and requests of the user are erased in the background.
+// @Security	 BearerAuth
+// @Tags         Users
+// @Produce      json
+// @Success      200 		{object}	responses.NoContent
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /v1/users/me 	[delete]
+func (h *UserHandler) delete(c *fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	userID := h.userIDFomContext(c)
+
+	if err := h.userService.Delete(ctx, c.OriginalURL(), userID); err != nil {
+		msg := fmt.Sprintf("cannot delete user with ID [%s]", userID)
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "user deleted successfully")
+}
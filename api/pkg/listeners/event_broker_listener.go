@@ -0,0 +1,51 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/events"
+	"github.com/NdoleStudio/httpmock/pkg/services"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// EventBrokerListener publishes events.ProjectEndpointRequest events to the services.EventBroker
+type EventBrokerListener struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	broker *services.EventBroker
+}
+
+// NewEventBrokerListener creates a new EventBrokerListener
+func NewEventBrokerListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	broker *services.EventBroker,
+) *EventBrokerListener {
+	return &EventBrokerListener{
+		logger: logger.WithCodeNamespace(fmt.Sprintf("%T", &EventBrokerListener{})),
+		tracer: tracer,
+		broker: broker,
+	}
+}
+
+// Register the listener to the dispatcher
+func (listener *EventBrokerListener) Register(dispatcher *services.EventDispatcher) {
+	dispatcher.Subscribe(events.ProjectEndpointRequest, listener.onProjectEndpointRequest)
+}
+
+func (listener *EventBrokerListener) onProjectEndpointRequest(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.ProjectEndpointRequestPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	listener.broker.Publish(ctx, services.ProjectRequestsTopic(payload.UserID, payload.ProjectID), event)
+	return nil
+}
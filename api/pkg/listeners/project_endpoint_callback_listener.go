@@ -0,0 +1,54 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/events"
+	"github.com/NdoleStudio/httpmock/pkg/services"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// ProjectEndpointCallbackListener listens for events.ProjectEndpointCallback events
+type ProjectEndpointCallbackListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.ProjectEndpointCallbackService
+}
+
+// NewProjectEndpointCallbackListener creates a new ProjectEndpointCallbackListener
+func NewProjectEndpointCallbackListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.ProjectEndpointCallbackService,
+) *ProjectEndpointCallbackListener {
+	return &ProjectEndpointCallbackListener{
+		logger:  logger.WithCodeNamespace(fmt.Sprintf("%T", &ProjectEndpointCallbackListener{})),
+		tracer:  tracer,
+		service: service,
+	}
+}
+
+// Register the listener to the dispatcher
+func (listener *ProjectEndpointCallbackListener) Register(dispatcher *services.EventDispatcher) {
+	dispatcher.Subscribe(events.ProjectEndpointCallback, listener.onProjectEndpointCallback)
+}
+
+func (listener *ProjectEndpointCallbackListener) onProjectEndpointCallback(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.ProjectEndpointCallbackPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.Send(ctx, event.Source(), &payload); err != nil {
+		msg := fmt.Sprintf("cannot send callback for [%s] event with ID [%s] and user ID [%s]", event.Type(), event.ID(), payload.UserID)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	return nil
+}
@@ -61,6 +61,10 @@ func (listener *ProjectEndpointRequestListener) onProjectEndpointRequest(ctx con
 		ResponseBody:                payload.ResponseBody,
 		ResponseHeaders:             payload.ResponseHeaders,
 		ResponseDelayInMilliseconds: payload.ResponseDelayInMilliseconds,
+		ResponseIndex:               payload.ResponseIndex,
+		ResponseFault:               payload.ResponseFault,
+		Passthrough:                 payload.Passthrough,
+		Unmatched:                   payload.Unmatched,
 		CreatedAt:                   payload.Timestamp,
 	}
 
@@ -0,0 +1,54 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/events"
+	"github.com/NdoleStudio/httpmock/pkg/services"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// ProjectListener listens for events.ProjectDeleted events
+type ProjectListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.ProjectService
+}
+
+// NewProjectListener creates a new ProjectListener
+func NewProjectListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.ProjectService,
+) *ProjectListener {
+	return &ProjectListener{
+		logger:  logger.WithCodeNamespace(fmt.Sprintf("%T", &ProjectListener{})),
+		tracer:  tracer,
+		service: service,
+	}
+}
+
+// Register the listener to the dispatcher
+func (listener *ProjectListener) Register(dispatcher *services.EventDispatcher) {
+	dispatcher.Subscribe(events.ProjectDeleted, listener.onProjectDeleted)
+}
+
+func (listener *ProjectListener) onProjectDeleted(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.ProjectDeletedPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.Cleanup(ctx, event.Source(), payload.UserID, payload.ProjectID); err != nil {
+		msg := fmt.Sprintf("cannot cleanup project for [%s] event with ID [%s] and project ID [%s]", event.Type(), event.ID(), payload.ProjectID)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	return nil
+}
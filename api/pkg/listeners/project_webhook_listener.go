@@ -0,0 +1,72 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/events"
+	"github.com/NdoleStudio/httpmock/pkg/services"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// ProjectWebhookListener forwards events.ProjectEndpointRequest events to the entities.ProjectWebhook of a project
+type ProjectWebhookListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.ProjectWebhookService
+}
+
+// NewProjectWebhookListener creates a new ProjectWebhookListener
+func NewProjectWebhookListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.ProjectWebhookService,
+) *ProjectWebhookListener {
+	return &ProjectWebhookListener{
+		logger:  logger.WithCodeNamespace(fmt.Sprintf("%T", &ProjectWebhookListener{})),
+		tracer:  tracer,
+		service: service,
+	}
+}
+
+// Register the listener to the dispatcher
+func (listener *ProjectWebhookListener) Register(dispatcher *services.EventDispatcher) {
+	dispatcher.Subscribe(events.ProjectEndpointRequest, listener.onProjectEndpointRequest)
+	dispatcher.Subscribe(events.ProjectWebhookDelivery, listener.onProjectWebhookDelivery)
+}
+
+func (listener *ProjectWebhookListener) onProjectEndpointRequest(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.ProjectEndpointRequestPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.Schedule(ctx, payload.UserID, payload.ProjectID, event); err != nil {
+		msg := fmt.Sprintf("cannot schedule webhooks for [%s] event with ID [%s] and user ID [%s]", event.Type(), event.ID(), payload.UserID)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	return nil
+}
+
+func (listener *ProjectWebhookListener) onProjectWebhookDelivery(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.ProjectWebhookDeliveryPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.Deliver(ctx, event.Source(), &payload); err != nil {
+		msg := fmt.Sprintf("cannot deliver [%s] event with ID [%s] to webhook [%s]", event.Type(), event.ID(), payload.ProjectWebhookID)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/events"
+	"github.com/NdoleStudio/httpmock/pkg/services"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// SubscriptionListener listens for events.UserSubscriptionCreated and events.UserSubscriptionCancelled events
+type SubscriptionListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.SubscriptionService
+}
+
+// NewSubscriptionListener creates a new SubscriptionListener
+func NewSubscriptionListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.SubscriptionService,
+) *SubscriptionListener {
+	return &SubscriptionListener{
+		logger:  logger.WithCodeNamespace(fmt.Sprintf("%T", &SubscriptionListener{})),
+		tracer:  tracer,
+		service: service,
+	}
+}
+
+// Register the listener to the dispatcher
+func (listener *SubscriptionListener) Register(dispatcher *services.EventDispatcher) {
+	dispatcher.Subscribe(events.UserSubscriptionCreated, listener.onUserSubscriptionCreated)
+	dispatcher.Subscribe(events.UserSubscriptionCancelled, listener.onUserSubscriptionCancelled)
+}
+
+func (listener *SubscriptionListener) onUserSubscriptionCreated(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.UserSubscriptionCreatedPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	_, err := listener.service.Update(ctx, &services.SubscriptionUpdateParams{
+		UserID:               payload.UserID,
+		SubscriptionID:       payload.SubscriptionID,
+		SubscriptionName:     payload.SubscriptionName,
+		SubscriptionStatus:   payload.SubscriptionStatus,
+		SubscriptionRenewsAt: &payload.SubscriptionRenewsAt,
+		SubscriptionEndsAt:   nil,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot update subscription for [%s] event with ID [%s] and user ID [%s]", event.Type(), event.ID(), payload.UserID)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	return nil
+}
+
+func (listener *SubscriptionListener) onUserSubscriptionCancelled(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.UserSubscriptionCancelledPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	// a cancelled subscription stays active until it ends so it does not renew anymore
+	_, err := listener.service.Update(ctx, &services.SubscriptionUpdateParams{
+		UserID:               payload.UserID,
+		SubscriptionID:       payload.SubscriptionID,
+		SubscriptionName:     payload.SubscriptionName,
+		SubscriptionStatus:   payload.SubscriptionStatus,
+		SubscriptionRenewsAt: nil,
+		SubscriptionEndsAt:   &payload.SubscriptionEndsAt,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot update subscription for [%s] event with ID [%s] and user ID [%s]", event.Type(), event.ID(), payload.UserID)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	return nil
+}
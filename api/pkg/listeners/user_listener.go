@@ -0,0 +1,54 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/events"
+	"github.com/NdoleStudio/httpmock/pkg/services"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// UserListener listens for events.UserAccountDeleted events
+type UserListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.UserService
+}
+
+// NewUserListener creates a new UserListener
+func NewUserListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.UserService,
+) *UserListener {
+	return &UserListener{
+		logger:  logger.WithCodeNamespace(fmt.Sprintf("%T", &UserListener{})),
+		tracer:  tracer,
+		service: service,
+	}
+}
+
+// Register the listener to the dispatcher
+func (listener *UserListener) Register(dispatcher *services.EventDispatcher) {
+	dispatcher.Subscribe(events.UserAccountDeleted, listener.onUserAccountDeleted)
+}
+
+func (listener *UserListener) onUserAccountDeleted(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.UserAccountDeletedPayload
+	if err := event.DataAs(&payload); err != nil {
+		msg := fmt.Sprintf("cannot decode [%s] into [%T]", event.Data(), payload)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := listener.service.Purge(ctx, event.Source(), payload.UserID); err != nil {
+		msg := fmt.Sprintf("cannot purge user account for [%s] event with ID [%s] and user ID [%s]", event.Type(), event.ID(), payload.UserID)
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package matchers
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NdoleStudio/httpmock/pkg/cache"
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/palantir/stacktrace"
+)
+
+// regexCacheSize is the maximum number of compiled regular expressions which are kept in memory
+const regexCacheSize = 1024
+
+var regexCache = cache.NewLRUCache[string, *regexp.Regexp](regexCacheSize)
+
+// ValidateCondition checks that an entities.ProjectEndpointCondition can be evaluated.
+// The error message is meant to be displayed to the user.
+func ValidateCondition(condition *entities.ProjectEndpointCondition) error {
+	switch condition.Source {
+	case entities.ProjectEndpointConditionSourceHeader, entities.ProjectEndpointConditionSourceQuery:
+		if strings.TrimSpace(condition.Key) == "" {
+			return fmt.Errorf("the key is required for a condition with source [%s]", condition.Source)
+		}
+	case entities.ProjectEndpointConditionSourceJSONBody:
+		if _, err := ParseJSONPath(condition.Key); err != nil {
+			return fmt.Errorf("the key [%s] must be a valid JSONPath expression like $.user.email", condition.Key)
+		}
+	case entities.ProjectEndpointConditionSourceBody:
+		break
+	default:
+		return fmt.Errorf("the source [%s] must be one of [header, query, json_body, body]", condition.Source)
+	}
+
+	switch condition.Operator {
+	case entities.ProjectEndpointConditionOperatorEquals,
+		entities.ProjectEndpointConditionOperatorNotEquals,
+		entities.ProjectEndpointConditionOperatorContains,
+		entities.ProjectEndpointConditionOperatorPresent,
+		entities.ProjectEndpointConditionOperatorAbsent:
+		return nil
+	case entities.ProjectEndpointConditionOperatorMatches:
+		if _, err := regexp.Compile(condition.Value); err != nil {
+			return fmt.Errorf("the value [%s] must be a valid regular expression", condition.Value)
+		}
+		return nil
+	default:
+		return fmt.Errorf("the operator [%s] must be one of [equals, not_equals, contains, matches, present, absent]", condition.Operator)
+	}
+}
+
+// MatchConditions checks if the request satisfies all the conditions
+func MatchConditions(conditions []*entities.ProjectEndpointCondition, request *Request) bool {
+	for _, condition := range conditions {
+		if !matchCondition(condition, request) {
+			return false
+		}
+	}
+	return true
+}
+
+// ConditionsKey returns a normalized representation of the conditions which does not depend on their order.
+func ConditionsKey(conditions []*entities.ProjectEndpointCondition) string {
+	keys := make([]string, 0, len(conditions))
+	for _, condition := range conditions {
+		key := condition.Key
+		if condition.Source == entities.ProjectEndpointConditionSourceHeader {
+			key = strings.ToLower(key)
+		}
+		keys = append(keys, strings.Join([]string{string(condition.Source), key, string(condition.Operator), condition.Value}, "\x00"))
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, "\x01")
+}
+
+func matchCondition(condition *entities.ProjectEndpointCondition, request *Request) bool {
+	values := conditionValues(condition, request)
+
+	switch condition.Operator {
+	case entities.ProjectEndpointConditionOperatorPresent:
+		return len(values) > 0
+	case entities.ProjectEndpointConditionOperatorAbsent:
+		return len(values) == 0
+	case entities.ProjectEndpointConditionOperatorNotEquals:
+		for _, value := range values {
+			if value == condition.Value {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, value := range values {
+		switch condition.Operator {
+		case entities.ProjectEndpointConditionOperatorEquals:
+			if value == condition.Value {
+				return true
+			}
+		case entities.ProjectEndpointConditionOperatorContains:
+			if strings.Contains(value, condition.Value) {
+				return true
+			}
+		case entities.ProjectEndpointConditionOperatorMatches:
+			if regex, err := compileRegex(condition.Value); err == nil && regex.MatchString(value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func conditionValues(condition *entities.ProjectEndpointCondition, request *Request) []string {
+	switch condition.Source {
+	case entities.ProjectEndpointConditionSourceHeader:
+		return request.Headers.Values(condition.Key)
+	case entities.ProjectEndpointConditionSourceQuery:
+		values, ok := request.Query[condition.Key]
+		if ok && len(values) == 0 {
+			return []string{""}
+		}
+		return values
+	case entities.ProjectEndpointConditionSourceBody:
+		if len(request.Body) == 0 {
+			return nil
+		}
+		return []string{string(request.Body)}
+	case entities.ProjectEndpointConditionSourceJSONBody:
+		document, ok := request.JSONBody()
+		if !ok {
+			return nil
+		}
+		path, err := ParseJSONPath(condition.Key)
+		if err != nil {
+			return nil
+		}
+		return path.FindStrings(document)
+	default:
+		return nil
+	}
+}
+
+func jsonString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return "null"
+	default:
+		result, _ := json.Marshal(v)
+		return string(result)
+	}
+}
+
+func compileRegex(expression string) (*regexp.Regexp, error) {
+	if regex, ok := regexCache.Get(expression); ok {
+		return regex, nil
+	}
+
+	regex, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot compile regular expression [%s]", expression))
+	}
+
+	regexCache.Add(expression, regex)
+	return regex, nil
+}
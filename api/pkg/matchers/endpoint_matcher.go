@@ -0,0 +1,88 @@
+package matchers
+
+import (
+	"sort"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+)
+
+// EndpointMatch is an entities.ProjectEndpoint which matches an HTTP request
+type EndpointMatch struct {
+	Endpoint   *entities.ProjectEndpoint
+	PathParams map[string]string
+	template   *PathTemplate
+}
+
+// MatchEndpoints returns the entities.ProjectEndpoint which match the request ordered from the best match.
+// Endpoints are ordered by priority (lowest first), then by the specificity of the path,
+// then by an exact request method and finally by the number of request conditions and scenario requirements.
+func MatchEndpoints(endpoints []*entities.ProjectEndpoint, request *Request) []*EndpointMatch {
+	var matches []*EndpointMatch
+	for _, endpoint := range endpoints {
+		template, err := ParsePath(endpoint.RequestPath)
+		if err != nil {
+			continue
+		}
+
+		params, ok := template.Match(request.Path)
+		if !ok || !matchScenario(endpoint, request) || !MatchConditions(endpoint.RequestConditions, request) {
+			continue
+		}
+
+		matches = append(matches, &EndpointMatch{Endpoint: endpoint, PathParams: params, template: template})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Endpoint.Priority != matches[j].Endpoint.Priority {
+			return matches[i].Endpoint.Priority < matches[j].Endpoint.Priority
+		}
+		if diff := matches[i].template.Compare(matches[j].template); diff != 0 {
+			return diff > 0
+		}
+		if exactI, exactJ := matches[i].Endpoint.RequestMethod == request.Method, matches[j].Endpoint.RequestMethod == request.Method; exactI != exactJ {
+			return exactI
+		}
+		return requirements(matches[i].Endpoint) > requirements(matches[j].Endpoint)
+	})
+
+	return matches
+}
+
+// Conflicts checks if 2 endpoints in the same project can never be distinguished from each other
+// because they have equivalent paths, overlapping methods, the same request conditions and the same scenario state.
+func Conflicts(endpoint *entities.ProjectEndpoint, other *entities.ProjectEndpoint) bool {
+	if endpoint.RequestMethod != other.RequestMethod && endpoint.RequestMethod != "ANY" && other.RequestMethod != "ANY" {
+		return false
+	}
+
+	if endpoint.ScenarioName != other.ScenarioName || endpoint.ScenarioRequiredState != other.ScenarioRequiredState {
+		return false
+	}
+
+	template, err := ParsePath(endpoint.RequestPath)
+	if err != nil {
+		return false
+	}
+
+	otherTemplate, err := ParsePath(other.RequestPath)
+	if err != nil {
+		return false
+	}
+
+	return template.Key() == otherTemplate.Key() && ConditionsKey(endpoint.RequestConditions) == ConditionsKey(other.RequestConditions)
+}
+
+func matchScenario(endpoint *entities.ProjectEndpoint, request *Request) bool {
+	if endpoint.ScenarioName == "" || endpoint.ScenarioRequiredState == "" {
+		return true
+	}
+	return request.ScenarioState(endpoint.ScenarioName) == endpoint.ScenarioRequiredState
+}
+
+func requirements(endpoint *entities.ProjectEndpoint) int {
+	count := len(endpoint.RequestConditions)
+	if endpoint.ScenarioName != "" && endpoint.ScenarioRequiredState != "" {
+		count++
+	}
+	return count
+}
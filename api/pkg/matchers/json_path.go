@@ -0,0 +1,176 @@
+package matchers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/palantir/stacktrace"
+)
+
+// JSONPathSelector selects the children of a JSON value
+type JSONPathSelector struct {
+	// Key is the name of the selected object member
+	Key string
+
+	// Index is the position of the selected array element when IsIndex is true. A negative index counts from the end.
+	Index   int
+	IsIndex bool
+
+	// Wildcard selects all the members of an object or all the elements of an array
+	Wildcard bool
+}
+
+type jsonPathToken struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// JSONPath is a parsed JSONPath expression like $.data.items[0].name
+// Only child (.key or ['key']), array index ([0]) and wildcard ([*] or .*) selectors are supported.
+type JSONPath struct {
+	expression string
+	tokens     []jsonPathToken
+}
+
+// ParseJSONPath parses a JSONPath expression
+func ParseJSONPath(expression string) (*JSONPath, error) {
+	input := strings.TrimSpace(expression)
+	if input == "" {
+		return nil, stacktrace.NewError("the JSONPath expression cannot be empty")
+	}
+
+	input = strings.TrimPrefix(input, "$")
+	if input != "" && input[0] != '.' && input[0] != '[' {
+		input = "." + input
+	}
+
+	var tokens []jsonPathToken
+	for len(input) > 0 {
+		switch input[0] {
+		case '.':
+			input = input[1:]
+			end := strings.IndexAny(input, ".[")
+			if end == -1 {
+				end = len(input)
+			}
+			key := input[:end]
+			if key == "" {
+				return nil, stacktrace.NewError(fmt.Sprintf("the JSONPath expression [%s] contains an empty key", expression))
+			}
+			tokens = append(tokens, jsonPathToken{key: key, wildcard: key == "*"})
+			input = input[end:]
+		case '[':
+			end := strings.Index(input, "]")
+			if end == -1 {
+				return nil, stacktrace.NewError(fmt.Sprintf("the JSONPath expression [%s] contains an unclosed [", expression))
+			}
+			token, err := parseJSONPathBracket(input[1:end])
+			if err != nil {
+				return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot parse the JSONPath expression [%s]", expression))
+			}
+			tokens = append(tokens, token)
+			input = input[end+1:]
+		default:
+			return nil, stacktrace.NewError(fmt.Sprintf("the JSONPath expression [%s] is not valid", expression))
+		}
+	}
+
+	return &JSONPath{expression: expression, tokens: tokens}, nil
+}
+
+func parseJSONPathBracket(value string) (jsonPathToken, error) {
+	value = strings.TrimSpace(value)
+	if value == "*" {
+		return jsonPathToken{wildcard: true}, nil
+	}
+
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		return jsonPathToken{key: value[1 : len(value)-1]}, nil
+	}
+
+	index, err := strconv.Atoi(value)
+	if err != nil {
+		return jsonPathToken{}, stacktrace.Propagate(err, fmt.Sprintf("the selector [%s] must be a quoted key, an array index or [*]", value))
+	}
+
+	return jsonPathToken{index: index, isIndex: true}, nil
+}
+
+// String returns the original JSONPath expression
+func (path *JSONPath) String() string {
+	return path.expression
+}
+
+// Selectors returns the selectors of the JSONPath expression starting from the root of the document
+func (path *JSONPath) Selectors() []JSONPathSelector {
+	selectors := make([]JSONPathSelector, 0, len(path.tokens))
+	for _, token := range path.tokens {
+		selectors = append(selectors, JSONPathSelector{
+			Key:      token.key,
+			Index:    token.index,
+			IsIndex:  token.isIndex,
+			Wildcard: token.wildcard,
+		})
+	}
+	return selectors
+}
+
+// FindStrings returns the values in the document selected by the JSONPath expression formatted as strings.
+// Strings are returned as is, numbers, booleans and null are formatted as JSON and objects and arrays are JSON encoded.
+func (path *JSONPath) FindStrings(document interface{}) []string {
+	var values []string
+	for _, value := range path.Find(document) {
+		values = append(values, jsonString(value))
+	}
+	return values
+}
+
+// Find returns all the values in the document selected by the JSONPath expression
+func (path *JSONPath) Find(document interface{}) []interface{} {
+	values := []interface{}{document}
+	for _, token := range path.tokens {
+		var next []interface{}
+		for _, value := range values {
+			next = append(next, token.selectFrom(value)...)
+		}
+		if len(next) == 0 {
+			return nil
+		}
+		values = next
+	}
+	return values
+}
+
+func (token jsonPathToken) selectFrom(value interface{}) []interface{} {
+	switch node := value.(type) {
+	case map[string]interface{}:
+		if token.wildcard {
+			result := make([]interface{}, 0, len(node))
+			for _, child := range node {
+				result = append(result, child)
+			}
+			return result
+		}
+		if child, ok := node[token.key]; ok && !token.isIndex {
+			return []interface{}{child}
+		}
+	case []interface{}:
+		if token.wildcard {
+			return node
+		}
+		if !token.isIndex {
+			return nil
+		}
+		index := token.index
+		if index < 0 {
+			index += len(node)
+		}
+		if index >= 0 && index < len(node) {
+			return []interface{}{node[index]}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package matchers
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/palantir/stacktrace"
+)
+
+// PathParameterWildcard is the key used to store the value matched by a trailing wildcard e.g. /files/*
+const PathParameterWildcard = "*"
+
+type pathSegmentKind int
+
+// The order of the kinds is important since it is used to determine which path is the most specific.
+const (
+	pathSegmentWildcard pathSegmentKind = iota
+	pathSegmentParameter
+	pathSegmentGlob
+	pathSegmentLiteral
+)
+
+var pathParameterName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_\-]*$`)
+
+type pathSegment struct {
+	kind  pathSegmentKind
+	value string
+}
+
+// PathTemplate is a parsed request path which can contain parameters like /v1/products/{id}
+// glob segments like /reports/*.csv and a trailing wildcard like /files/*
+type PathTemplate struct {
+	template string
+	segments []pathSegment
+}
+
+// ParsePath parses a request path template into a PathTemplate
+func ParsePath(template string) (*PathTemplate, error) {
+	if !strings.HasPrefix(template, "/") {
+		return nil, stacktrace.NewError(fmt.Sprintf("the path [%s] must start with [/]", template))
+	}
+
+	parts := strings.Split(strings.TrimPrefix(template, "/"), "/")
+	segments := make([]pathSegment, 0, len(parts))
+	names := map[string]bool{}
+
+	for index, part := range parts {
+		segment, err := parsePathSegment(part, index == len(parts)-1)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot parse the path [%s]", template))
+		}
+
+		if segment.kind == pathSegmentParameter && segment.value != "" {
+			if names[segment.value] {
+				return nil, stacktrace.NewError(fmt.Sprintf("the parameter {%s} is used more than once in the path [%s]", segment.value, template))
+			}
+			names[segment.value] = true
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return &PathTemplate{template: template, segments: segments}, nil
+}
+
+func parsePathSegment(part string, isLast bool) (pathSegment, error) {
+	if part == "*" {
+		if isLast {
+			return pathSegment{kind: pathSegmentWildcard}, nil
+		}
+		return pathSegment{kind: pathSegmentParameter}, nil
+	}
+
+	if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+		name := strings.TrimSuffix(strings.TrimPrefix(part, "{"), "}")
+		if !pathParameterName.MatchString(name) {
+			return pathSegment{}, stacktrace.NewError(fmt.Sprintf("the parameter [%s] must be a valid name like {id}", part))
+		}
+		return pathSegment{kind: pathSegmentParameter, value: name}, nil
+	}
+
+	if strings.ContainsAny(part, "{}") {
+		return pathSegment{}, stacktrace.NewError(fmt.Sprintf("the segment [%s] must be a parameter like {id} or must not contain [{] and [}]", part))
+	}
+
+	if strings.ContainsAny(part, "*?[") {
+		if _, err := path.Match(part, ""); err != nil {
+			return pathSegment{}, stacktrace.Propagate(err, fmt.Sprintf("the segment [%s] is not a valid glob pattern", part))
+		}
+		return pathSegment{kind: pathSegmentGlob, value: part}, nil
+	}
+
+	return pathSegment{kind: pathSegmentLiteral, value: part}, nil
+}
+
+// String returns the original path template
+func (template *PathTemplate) String() string {
+	return template.template
+}
+
+// Key returns a normalized version of the template where parameter names are removed.
+// Two templates with the same key match exactly the same request paths e.g. /products/{id} and /products/{productId}
+func (template *PathTemplate) Key() string {
+	parts := make([]string, 0, len(template.segments))
+	for _, segment := range template.segments {
+		switch segment.kind {
+		case pathSegmentWildcard:
+			parts = append(parts, "*")
+		case pathSegmentParameter:
+			parts = append(parts, "{}")
+		default:
+			parts = append(parts, segment.value)
+		}
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// Match checks if a request path matches the template and returns the path parameters
+func (template *PathTemplate) Match(requestPath string) (map[string]string, bool) {
+	parts := strings.Split(strings.TrimPrefix(requestPath, "/"), "/")
+	params := map[string]string{}
+
+	for index, segment := range template.segments {
+		if segment.kind == pathSegmentWildcard {
+			params[PathParameterWildcard] = strings.Join(parts[min(index, len(parts)):], "/")
+			return params, true
+		}
+
+		if index >= len(parts) {
+			return nil, false
+		}
+
+		switch segment.kind {
+		case pathSegmentLiteral:
+			if parts[index] != segment.value {
+				return nil, false
+			}
+		case pathSegmentGlob:
+			if ok, _ := path.Match(segment.value, parts[index]); !ok {
+				return nil, false
+			}
+		case pathSegmentParameter:
+			if parts[index] == "" {
+				return nil, false
+			}
+			if segment.value != "" {
+				params[segment.value] = parts[index]
+			}
+		}
+	}
+
+	if len(parts) != len(template.segments) {
+		return nil, false
+	}
+
+	return params, true
+}
+
+// Compare returns a positive number if the template is more specific than the other template,
+// a negative number if it is less specific and 0 if both templates are equally specific.
+func (template *PathTemplate) Compare(other *PathTemplate) int {
+	for index := 0; index < min(len(template.segments), len(other.segments)); index++ {
+		if diff := int(template.segments[index].kind) - int(other.segments[index].kind); diff != 0 {
+			return diff
+		}
+	}
+
+	// A trailing wildcard can also match an empty path so /files is more specific than /files/*
+	if len(template.segments) > len(other.segments) && template.segments[len(other.segments)].kind == pathSegmentWildcard {
+		return -1
+	}
+	if len(other.segments) > len(template.segments) && other.segments[len(template.segments)].kind == pathSegmentWildcard {
+		return 1
+	}
+
+	return len(template.segments) - len(other.segments)
+}
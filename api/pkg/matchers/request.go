@@ -0,0 +1,42 @@
+package matchers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+)
+
+// Request is the HTTP request which is matched against the entities.ProjectEndpoint of a project
+type Request struct {
+	Method  string
+	Path    string
+	Headers http.Header
+	Query   url.Values
+	Body    []byte
+
+	// ScenarioStates are the current states of the scenarios in the project
+	ScenarioStates map[string]string
+
+	jsonBody    interface{}
+	jsonDecoded bool
+	jsonValid   bool
+}
+
+// JSONBody decodes the request body as JSON. The boolean is false when the body is not valid JSON.
+func (request *Request) JSONBody() (interface{}, bool) {
+	if !request.jsonDecoded {
+		request.jsonDecoded = true
+		request.jsonValid = json.Unmarshal(request.Body, &request.jsonBody) == nil
+	}
+	return request.jsonBody, request.jsonValid
+}
+
+// ScenarioState returns the current state of a scenario
+func (request *Request) ScenarioState(scenario string) string {
+	if state, ok := request.ScenarioStates[scenario]; ok {
+		return state
+	}
+	return entities.ProjectScenarioStateStarted
+}
@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Admin allows only the authenticated users who can manage the application. It must be used after an authentication middleware.
+func Admin(logger telemetry.Logger, tracer telemetry.Tracer, isAdmin func(userID entities.UserID) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		_, span, ctxLogger := tracer.StartFromFiberCtxWithLogger(c, logger, "middlewares.Admin")
+		defer span.End()
+
+		user, ok := c.Locals(ContextKeyAuthUserID).(*entities.AuthUser)
+		if !ok || !isAdmin(user.ID) {
+			span.AddEvent("the authenticated user is not an admin")
+			if ok {
+				ctxLogger.Info(fmt.Sprintf("user with ID [%s] is not an admin", user.ID))
+			}
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status":  "error",
+				"message": "You are not allowed to carry out this request.",
+			})
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,37 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIKeyAuth authenticates the requests which have a static API key as the bearer token as the authUser
+func APIKeyAuth(logger telemetry.Logger, tracer telemetry.Tracer, apiKey string, authUser entities.AuthUser) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		_, span, ctxLogger := tracer.StartFromFiberCtxWithLogger(c, logger, "middlewares.APIKeyAuth")
+		defer span.End()
+
+		authToken := c.Get(authHeaderBearer)
+		if !strings.HasPrefix(authToken, bearerPrefix) {
+			span.AddEvent(fmt.Sprintf("the request header has no [%s] token", bearerScheme))
+			return c.Next()
+		}
+
+		authToken = strings.TrimPrefix(authToken, bearerPrefix)
+		if apiKey == "" || subtle.ConstantTimeCompare([]byte(authToken), []byte(apiKey)) != 1 {
+			span.AddEvent(fmt.Sprintf("invalid API key [%s]", tracer.Redact(authToken)))
+			return c.Next()
+		}
+
+		user := authUser
+		c.Locals(ContextKeyAuthUserID, &user)
+
+		ctxLogger.Info(fmt.Sprintf("[%T] set successfully for user with ID [%s]", &user, user.ID))
+		return c.Next()
+	}
+}
@@ -1,11 +1,13 @@
 package middlewares
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/NdoleStudio/httpmock/pkg/entities"
 	"github.com/NdoleStudio/httpmock/pkg/repositories"
 	"github.com/NdoleStudio/httpmock/pkg/services"
 	"github.com/NdoleStudio/httpmock/pkg/telemetry"
@@ -19,6 +21,7 @@ func RequestRouter(
 	logger telemetry.Logger,
 	hostname string,
 	requestService *services.ProjectEndpointRequestService,
+	subscriptionService *services.SubscriptionService,
 	serverHandler fiber.Handler,
 	echoHandler fiber.Handler,
 ) fiber.Handler {
@@ -42,22 +45,40 @@ func RequestRouter(
 			return handleNamedSubdomains(c, strings.TrimSpace(c.Subdomains()[0]), serverHandler, echoHandler)
 		}
 
-		endpoint, err := requestService.LoadByRequest(ctx, c.Subdomains()[0], c.Method(), c.Path())
+		project, err := requestService.LoadProject(ctx, c.Subdomains()[0])
 		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  "error",
-				"message": fmt.Sprintf("We cannot find a registered mock for URL [%s] and HTTP method [%s]", c.BaseURL()+c.OriginalURL(), c.Method()),
-			})
+			return responseNotFound(c)
+		}
+
+		if err != nil {
+			msg := fmt.Sprintf("error while fetching project for URL [%s] with method [%s]", c.BaseURL()+c.OriginalURL(), c.Method())
+			ctxLogger.Error(tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+			return responseInternalServerError(c)
+		}
+
+		endpoint, err := requestService.LoadByRequest(ctx, project, c)
+		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound && project.UpstreamURL != "" {
+			if !meterRequest(ctx, c, ctxLogger, subscriptionService, project.UserID) {
+				return responseTooManyRequests(c)
+			}
+			requestService.HandlePassthroughRequest(ctx, c, stopwatch, project)
+			return nil
+		}
+
+		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+			err = responseNotFound(c)
+			requestService.HandleUnmatchedRequest(ctx, c, stopwatch, project)
+			return err
 		}
 
 		if err != nil {
 			msg := fmt.Sprintf("error while fetching endpoint [%s] with method [%s]", c.BaseURL()+c.OriginalURL(), c.Method())
 			ctxLogger.Error(tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+			return responseInternalServerError(c)
+		}
 
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"status":  "error",
-				"message": "We ran into an internal server error occurred while processing your request. We have been notified about it it already.",
-			})
+		if !meterRequest(ctx, c, ctxLogger, subscriptionService, project.UserID) {
+			return responseTooManyRequests(c)
 		}
 
 		requestService.HandleHTTPRequest(ctx, c, stopwatch, endpoint)
@@ -65,6 +86,23 @@ func RequestRouter(
 	}
 }
 
+// meterRequest counts the request against the subscription of the user and returns false when the user has no more requests.
+// The request is allowed when it cannot be counted so that a storage failure does not break the mocks.
+func meterRequest(ctx context.Context, c *fiber.Ctx, ctxLogger telemetry.Logger, subscriptionService *services.SubscriptionService, userID entities.UserID) bool {
+	err := subscriptionService.MeterRequest(ctx, c.BaseURL()+c.OriginalURL(), userID)
+	if stacktrace.GetCode(err) == services.ErrCodeRequestQuota {
+		ctxLogger.Info(fmt.Sprintf("user [%s] has no more requests for URL [%s] with method [%s]", userID, c.BaseURL()+c.OriginalURL(), c.Method()))
+		return false
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot meter request [%s] with method [%s] for user [%s]", c.BaseURL()+c.OriginalURL(), c.Method(), userID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+	}
+
+	return true
+}
+
 func handleNamedSubdomains(c *fiber.Ctx, subdomain string, serverHandler fiber.Handler, echoHandler fiber.Handler) error {
 	switch subdomain {
 	case "echo":
@@ -78,8 +116,26 @@ func handleNamedSubdomains(c *fiber.Ctx, subdomain string, serverHandler fiber.H
 		return serverHandler(c)
 	}
 
+	return responseNotFound(c)
+}
+
+func responseNotFound(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 		"status":  "error",
 		"message": fmt.Sprintf("We cannot find a registered mock for URL [%s] and HTTP method [%s]", c.BaseURL()+c.OriginalURL(), c.Method()),
 	})
 }
+
+func responseTooManyRequests(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+		"status":  "error",
+		"message": "The monthly requests of your subscription have been used. Upgrade your subscription on https://httpmock.dev to make more requests.",
+	})
+}
+
+func responseInternalServerError(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "We ran into an internal server error occurred while processing your request. We have been notified about it it already.",
+	})
+}
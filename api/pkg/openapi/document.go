@@ -0,0 +1,215 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/palantir/stacktrace"
+	"gopkg.in/yaml.v3"
+)
+
+// Document is an OpenAPI 3.x or a Swagger 2.0 document
+type Document struct {
+	OpenAPI     string               `json:"openapi,omitempty"`
+	Swagger     string               `json:"swagger,omitempty"`
+	Info        Info                 `json:"info"`
+	Servers     []*Server            `json:"servers,omitempty"`
+	BasePath    string               `json:"basePath,omitempty"`
+	Produces    []string             `json:"produces,omitempty"`
+	Paths       map[string]*PathItem `json:"paths"`
+	Components  *Components          `json:"components,omitempty"`
+	Definitions map[string]*Schema   `json:"definitions,omitempty"`
+	Responses   map[string]*Response `json:"responses,omitempty"`
+
+	// sampleSize is the approximate size in bytes of all the samples which have been generated for the document
+	sampleSize int
+}
+
+// Info is the metadata of a Document
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server is the URL of a server which hosts the API in an OpenAPI 3.x Document
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// Components are the reusable objects of an OpenAPI 3.x Document
+type Components struct {
+	Schemas   map[string]*Schema   `json:"schemas,omitempty"`
+	Responses map[string]*Response `json:"responses,omitempty"`
+	Examples  map[string]*Example  `json:"examples,omitempty"`
+}
+
+// PathItem contains the operations which are available on a path
+type PathItem struct {
+	Get     *Operation `json:"get,omitempty"`
+	Put     *Operation `json:"put,omitempty"`
+	Post    *Operation `json:"post,omitempty"`
+	Delete  *Operation `json:"delete,omitempty"`
+	Options *Operation `json:"options,omitempty"`
+	Head    *Operation `json:"head,omitempty"`
+	Patch   *Operation `json:"patch,omitempty"`
+}
+
+// Operation returns the Operation for an HTTP method
+func (item *PathItem) Operation(method string) *Operation {
+	if pointer := item.pointer(method); pointer != nil {
+		return *pointer
+	}
+	return nil
+}
+
+// SetOperation sets the Operation for an HTTP method
+func (item *PathItem) SetOperation(method string, operation *Operation) {
+	if pointer := item.pointer(method); pointer != nil {
+		*pointer = operation
+	}
+}
+
+func (item *PathItem) pointer(method string) **Operation {
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		return &item.Get
+	case http.MethodPut:
+		return &item.Put
+	case http.MethodPost:
+		return &item.Post
+	case http.MethodDelete:
+		return &item.Delete
+	case http.MethodOptions:
+		return &item.Options
+	case http.MethodHead:
+		return &item.Head
+	case http.MethodPatch:
+		return &item.Patch
+	default:
+		return nil
+	}
+}
+
+// Operation is a single API operation on a path
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Produces    []string             `json:"produces,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter is a parameter of an Operation
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema,omitempty"`
+}
+
+// Response is a single response of an Operation.
+// Content is used by OpenAPI 3.x while Schema and Examples are used by Swagger 2.0
+type Response struct {
+	Ref         string                 `json:"$ref,omitempty"`
+	Description string                 `json:"description"`
+	Headers     map[string]*Header     `json:"headers,omitempty"`
+	Content     map[string]*MediaType  `json:"content,omitempty"`
+	Schema      *Schema                `json:"schema,omitempty"`
+	Examples    map[string]interface{} `json:"examples,omitempty"`
+}
+
+// Header is a response header
+type Header struct {
+	Description string      `json:"description,omitempty"`
+	Type        string      `json:"type,omitempty"`
+	Schema      *Schema     `json:"schema,omitempty"`
+	Example     interface{} `json:"example,omitempty"`
+}
+
+// MediaType is the content of a Response for a specific content type
+type MediaType struct {
+	Schema   *Schema             `json:"schema,omitempty"`
+	Example  interface{}         `json:"example,omitempty"`
+	Examples map[string]*Example `json:"examples,omitempty"`
+}
+
+// Example is a named example of a MediaType
+type Example struct {
+	Ref     string      `json:"$ref,omitempty"`
+	Summary string      `json:"summary,omitempty"`
+	Value   interface{} `json:"value,omitempty"`
+}
+
+// Parse decodes an OpenAPI 3.x or a Swagger 2.0 document in JSON or YAML format
+func Parse(content []byte) (*Document, error) {
+	document := new(Document)
+	if err := Unmarshal(content, document); err != nil {
+		return nil, stacktrace.Propagate(err, "cannot decode the document into an OpenAPI document")
+	}
+
+	if !document.IsOpenAPI3() && !document.IsSwagger2() {
+		return nil, stacktrace.NewError(fmt.Sprintf("the document version [%s%s] is not supported", document.OpenAPI, document.Swagger))
+	}
+
+	return document, nil
+}
+
+// IsOpenAPI3 checks if the document is an OpenAPI 3.x document
+func (document *Document) IsOpenAPI3() bool {
+	return strings.HasPrefix(document.OpenAPI, "3.")
+}
+
+// IsSwagger2 checks if the document is a Swagger 2.0 document
+func (document *Document) IsSwagger2() bool {
+	return document.Swagger == "2.0"
+}
+
+// Unmarshal decodes a JSON or YAML document into a value using the JSON field tags of the value
+func Unmarshal(content []byte, value any) error {
+	var raw interface{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return stacktrace.Propagate(err, "cannot decode the document as JSON or YAML")
+	}
+
+	// YAML allows non string keys e.g. response codes so the document is normalized before decoding it as JSON
+	normalized, err := json.Marshal(normalize(raw))
+	if err != nil {
+		return stacktrace.Propagate(err, "cannot encode the normalized document as JSON")
+	}
+
+	if err = json.Unmarshal(normalized, value); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot decode the document into [%T]", value))
+	}
+
+	return nil
+}
+
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = normalize(item)
+		}
+		return result
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[fmt.Sprint(key)] = normalize(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for index, item := range v {
+			result[index] = normalize(item)
+		}
+		return result
+	default:
+		return v
+	}
+}
@@ -0,0 +1,262 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Endpoint is a mock endpoint generated from an Operation in a Document
+type Endpoint struct {
+	RequestMethod string
+	RequestPath   string
+	ResponseCode  uint
+	ResponseBody  string
+	ContentType   string
+	Description   string
+}
+
+// Endpoints generates an Endpoint for every Operation in the Document ordered by path and method
+func (document *Document) Endpoints() []*Endpoint {
+	paths := make([]string, 0, len(document.Paths))
+	for path := range document.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	prefix := document.pathPrefix()
+
+	var endpoints []*Endpoint
+	for _, path := range paths {
+		item := document.Paths[path]
+		if item == nil {
+			continue
+		}
+
+		for _, operation := range item.operations() {
+			endpoints = append(endpoints, document.endpoint(prefix+path, operation.method, operation.operation))
+		}
+	}
+
+	return endpoints
+}
+
+type methodOperation struct {
+	method    string
+	operation *Operation
+}
+
+func (item *PathItem) operations() []methodOperation {
+	var result []methodOperation
+	for _, operation := range []methodOperation{
+		{http.MethodGet, item.Get},
+		{http.MethodPost, item.Post},
+		{http.MethodPut, item.Put},
+		{http.MethodPatch, item.Patch},
+		{http.MethodDelete, item.Delete},
+		{http.MethodOptions, item.Options},
+		{http.MethodHead, item.Head},
+	} {
+		if operation.operation != nil {
+			result = append(result, operation)
+		}
+	}
+	return result
+}
+
+func (document *Document) endpoint(path string, method string, operation *Operation) *Endpoint {
+	endpoint := &Endpoint{
+		RequestMethod: method,
+		RequestPath:   path,
+		ResponseCode:  http.StatusOK,
+		Description:   operation.Summary,
+	}
+
+	if endpoint.Description == "" {
+		endpoint.Description = operation.OperationID
+	}
+	if endpoint.Description == "" {
+		endpoint.Description = operation.Description
+	}
+
+	code, response := document.selectResponse(operation.Responses)
+	endpoint.ResponseCode = code
+	if response == nil {
+		return endpoint
+	}
+
+	if document.IsOpenAPI3() {
+		endpoint.ContentType, endpoint.ResponseBody = document.openAPI3Body(response)
+	} else {
+		endpoint.ContentType, endpoint.ResponseBody = document.swagger2Body(operation, response)
+	}
+
+	return endpoint
+}
+
+// selectResponse picks the lowest successful response code, then the default response and then the lowest response code
+func (document *Document) selectResponse(responses map[string]*Response) (uint, *Response) {
+	var codes []int
+	for key := range responses {
+		if code, err := strconv.Atoi(strings.ReplaceAll(strings.ToUpper(key), "XX", "00")); err == nil {
+			codes = append(codes, code)
+		}
+	}
+	sort.Ints(codes)
+
+	get := func(code int) *Response {
+		if response, ok := responses[strconv.Itoa(code)]; ok {
+			return document.resolveResponse(response)
+		}
+		return document.resolveResponse(responses[strconv.Itoa(code/100)+"XX"])
+	}
+
+	for _, code := range codes {
+		if code >= 200 && code < 300 {
+			return uint(code), get(code)
+		}
+	}
+
+	if response, ok := responses["default"]; ok {
+		return http.StatusOK, document.resolveResponse(response)
+	}
+
+	if len(codes) > 0 {
+		return uint(codes[0]), get(codes[0])
+	}
+
+	return http.StatusOK, nil
+}
+
+func (document *Document) resolveResponse(response *Response) *Response {
+	if response == nil || response.Ref == "" {
+		return response
+	}
+
+	switch {
+	case strings.HasPrefix(response.Ref, "#/components/responses/") && document.Components != nil:
+		return document.Components.Responses[strings.TrimPrefix(response.Ref, "#/components/responses/")]
+	case strings.HasPrefix(response.Ref, "#/responses/"):
+		return document.Responses[strings.TrimPrefix(response.Ref, "#/responses/")]
+	default:
+		return nil
+	}
+}
+
+func (document *Document) openAPI3Body(response *Response) (string, string) {
+	contentTypes := make([]string, 0, len(response.Content))
+	for contentType := range response.Content {
+		contentTypes = append(contentTypes, contentType)
+	}
+
+	contentType := preferredContentType(contentTypes)
+	if contentType == "" {
+		return "", ""
+	}
+
+	media := response.Content[contentType]
+	if media == nil {
+		return contentType, ""
+	}
+
+	if media.Example != nil {
+		return contentType, encodeBody(contentType, media.Example)
+	}
+
+	names := make([]string, 0, len(media.Examples))
+	for name := range media.Examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if example := document.resolveExample(media.Examples[name]); example != nil && example.Value != nil {
+			return contentType, encodeBody(contentType, example.Value)
+		}
+	}
+
+	if media.Schema != nil {
+		return contentType, encodeBody(contentType, document.Sample(media.Schema))
+	}
+
+	return contentType, ""
+}
+
+func (document *Document) resolveExample(example *Example) *Example {
+	if example == nil || example.Ref == "" {
+		return example
+	}
+	if document.Components == nil || !strings.HasPrefix(example.Ref, "#/components/examples/") {
+		return nil
+	}
+	return document.Components.Examples[strings.TrimPrefix(example.Ref, "#/components/examples/")]
+}
+
+func (document *Document) swagger2Body(operation *Operation, response *Response) (string, string) {
+	produces := operation.Produces
+	if len(produces) == 0 {
+		produces = document.Produces
+	}
+
+	examples := make([]string, 0, len(response.Examples))
+	for contentType := range response.Examples {
+		examples = append(examples, contentType)
+	}
+	if contentType := preferredContentType(examples); response.Examples[contentType] != nil {
+		return contentType, encodeBody(contentType, response.Examples[contentType])
+	}
+
+	if response.Schema == nil {
+		return "", ""
+	}
+
+	contentType := preferredContentType(produces)
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	return contentType, encodeBody(contentType, document.Sample(response.Schema))
+}
+
+func (document *Document) pathPrefix() string {
+	prefix := document.BasePath
+	if document.IsOpenAPI3() && len(document.Servers) > 0 {
+		if u, err := url.Parse(document.Servers[0].URL); err == nil && !strings.Contains(u.Path, "{") {
+			prefix = u.Path
+		}
+	}
+	return strings.TrimRight(prefix, "/")
+}
+
+func preferredContentType(contentTypes []string) string {
+	contentTypes = append([]string{}, contentTypes...)
+	sort.Strings(contentTypes)
+	for _, contentType := range contentTypes {
+		if contentType == "application/json" {
+			return contentType
+		}
+	}
+	for _, contentType := range contentTypes {
+		if strings.Contains(contentType, "json") {
+			return contentType
+		}
+	}
+	if len(contentTypes) > 0 {
+		return contentTypes[0]
+	}
+	return ""
+}
+
+func encodeBody(contentType string, value interface{}) string {
+	if text, ok := value.(string); ok && !strings.Contains(contentType, "json") {
+		return text
+	}
+
+	body, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(body)
+}
@@ -0,0 +1,208 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// maxSampleDepth is the maximum depth of nested objects and arrays in a generated sample
+const maxSampleDepth = 6
+
+// maxSampleSize is the approximate size in bytes of all the samples which can be generated for a Document.
+// Schemas which are reached when the size is exceeded are sampled as null so a small document cannot expand into a huge body.
+const maxSampleSize = 4 * 1024 * 1024
+
+// Schema is the data type of a request or response body
+type Schema struct {
+	Ref        string             `json:"$ref,omitempty"`
+	Type       SchemaType         `json:"type,omitempty"`
+	Format     string             `json:"format,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+	AllOf      []*Schema          `json:"allOf,omitempty"`
+	OneOf      []*Schema          `json:"oneOf,omitempty"`
+	AnyOf      []*Schema          `json:"anyOf,omitempty"`
+	Enum       []interface{}      `json:"enum,omitempty"`
+	Example    interface{}        `json:"example,omitempty"`
+	Default    interface{}        `json:"default,omitempty"`
+}
+
+// SchemaType is the type of a Schema. OpenAPI 3.1 allows a list of types like ["string", "null"]
+type SchemaType string
+
+// UnmarshalJSON decodes the type from a string or from a list of types
+func (schemaType *SchemaType) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*schemaType = SchemaType(value)
+		return nil
+	}
+
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	for _, value = range values {
+		if value != "null" {
+			*schemaType = SchemaType(value)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// Sample generates an example value for a Schema
+func (document *Document) Sample(schema *Schema) interface{} {
+	return document.sample(schema, 0, map[string]bool{})
+}
+
+func (document *Document) sample(schema *Schema, depth int, refs map[string]bool) interface{} {
+	if !document.spend(4) || schema == nil || depth > maxSampleDepth {
+		return nil
+	}
+
+	if schema.Ref != "" {
+		// recursive schemas are only expanded once
+		if refs[schema.Ref] {
+			return nil
+		}
+		refs[schema.Ref] = true
+		defer delete(refs, schema.Ref)
+		return document.sample(document.resolveSchema(schema.Ref), depth, refs)
+	}
+
+	if schema.Example != nil {
+		return document.spendValue(schema.Example)
+	}
+	if schema.Default != nil {
+		return document.spendValue(schema.Default)
+	}
+	if len(schema.Enum) > 0 {
+		return document.spendValue(schema.Enum[0])
+	}
+
+	if len(schema.AllOf) > 0 {
+		result := map[string]interface{}{}
+		for _, item := range schema.AllOf {
+			if value, ok := document.sample(item, depth, refs).(map[string]interface{}); ok {
+				for key, property := range value {
+					result[key] = property
+				}
+			}
+		}
+		return result
+	}
+	if len(schema.OneOf) > 0 {
+		return document.sample(schema.OneOf[0], depth, refs)
+	}
+	if len(schema.AnyOf) > 0 {
+		return document.sample(schema.AnyOf[0], depth, refs)
+	}
+
+	switch {
+	case schema.Type == "object" || (schema.Type == "" && len(schema.Properties) > 0):
+		result := make(map[string]interface{}, len(schema.Properties))
+		for key, property := range schema.Properties {
+			if !document.spend(len(key) + 3) {
+				break
+			}
+			result[key] = document.sample(property, depth+1, refs)
+		}
+		return result
+	case schema.Type == "array":
+		if item := document.sample(schema.Items, depth+1, refs); item != nil {
+			return []interface{}{item}
+		}
+		return []interface{}{}
+	case schema.Type == "integer":
+		return 0
+	case schema.Type == "number":
+		return 0.0
+	case schema.Type == "boolean":
+		return true
+	case schema.Type == "string":
+		value := sampleString(schema.Format)
+		document.spend(len(value) + 2)
+		return value
+	default:
+		return nil
+	}
+}
+
+// spend adds a size to the size of the samples of the document, it returns false when the maximum size is exceeded
+func (document *Document) spend(size int) bool {
+	if document.sampleSize+size > maxSampleSize {
+		document.sampleSize = maxSampleSize
+		return false
+	}
+	document.sampleSize += size
+	return true
+}
+
+// spendValue adds the size of an example to the size of the samples, it returns nil when the maximum size is exceeded
+func (document *Document) spendValue(value interface{}) interface{} {
+	if !document.spend(valueSize(value, maxSampleSize-document.sampleSize+1)) {
+		return nil
+	}
+	return value
+}
+
+// valueSize returns the approximate size in bytes of a decoded JSON or YAML value, it stops counting after the limit
+func valueSize(value interface{}, limit int) int {
+	switch value := value.(type) {
+	case string:
+		return len(value) + 2
+	case map[string]interface{}:
+		size := 2
+		for key, item := range value {
+			if size > limit {
+				break
+			}
+			size += len(key) + 3 + valueSize(item, limit-size)
+		}
+		return size
+	case []interface{}:
+		size := 2
+		for _, item := range value {
+			if size > limit {
+				break
+			}
+			size += valueSize(item, limit-size)
+		}
+		return size
+	default:
+		return 8
+	}
+}
+
+func (document *Document) resolveSchema(ref string) *Schema {
+	switch {
+	case strings.HasPrefix(ref, "#/components/schemas/") && document.Components != nil:
+		return document.Components.Schemas[strings.TrimPrefix(ref, "#/components/schemas/")]
+	case strings.HasPrefix(ref, "#/definitions/"):
+		return document.Definitions[strings.TrimPrefix(ref, "#/definitions/")]
+	default:
+		return nil
+	}
+}
+
+func sampleString(format string) string {
+	switch format {
+	case "date-time":
+		return "2022-06-05T14:26:02Z"
+	case "date":
+		return "2022-06-05"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "8f9c71b8-b84e-4417-8408-a62274f65a08"
+	case "uri", "url":
+		return "https://example.com"
+	case "ipv4":
+		return "127.0.0.1"
+	default:
+		return "string"
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"cloud.google.com/go/cloudtasks/apiv2/cloudtaskspb"
 	"github.com/NdoleStudio/httpmock/pkg/telemetry"
 	"github.com/palantir/stacktrace"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type googlePushQueue struct {
@@ -64,6 +65,10 @@ func (queue *googlePushQueue) Enqueue(ctx context.Context, task *Task) (queueID
 	// Add a payload message if one is present.
 	req.Task.GetHttpRequest().Body = task.Body
 
+	if !task.DueAt.IsZero() {
+		req.Task.ScheduleTime = timestamppb.New(task.DueAt)
+	}
+
 	queueTask, err := queue.client.CreateTask(ctx, req)
 	if err != nil {
 		msg := fmt.Sprintf("cannot schedule task %s to URL: %s", string(task.Body), task.URL)
@@ -0,0 +1,260 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// Consumer processes a Task which was added to an in-process queue
+type Consumer func(ctx context.Context, task *Task) error
+
+// Drainer is a queue which processes the pending tasks before the application stops
+type Drainer interface {
+	// Drain stops accepting new tasks and waits until the pending tasks are processed or the context is done
+	Drain(ctx context.Context) error
+}
+
+// MemoryQueueConfig configures the in-process queue
+type MemoryQueueConfig struct {
+	// Workers is the number of tasks which are consumed concurrently
+	Workers int
+
+	// BufferSize is the maximum number of tasks waiting for a worker, Enqueue fails when the buffer is full
+	BufferSize int
+
+	// MaxAttempts is the number of times a task is consumed before it is discarded
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry, it doubles after every failed attempt
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum delay between two attempts
+	MaxBackoff time.Duration
+}
+
+type memoryQueueItem struct {
+	ctx     context.Context
+	id      string
+	task    *Task
+	attempt int
+}
+
+type memoryQueue struct {
+	logger   telemetry.Logger
+	tracer   telemetry.Tracer
+	consumer Consumer
+	config   MemoryQueueConfig
+	items    chan *memoryQueueItem
+	stop     chan struct{}
+	pending  sync.WaitGroup
+	mutex    sync.RWMutex
+	closed   bool
+
+	// delayed contains the timers of the tasks which are not due yet, they are not pending so Drain does not wait for them
+	delayed      map[string]*time.Timer
+	delayedMutex sync.Mutex
+	draining     bool
+}
+
+// NewMemoryQueue creates an in-process queue which delivers each Task to the Consumer using a pool of workers
+func NewMemoryQueue(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	consumer Consumer,
+	config MemoryQueueConfig,
+) Client {
+	config.Workers = max(config.Workers, 1)
+	config.BufferSize = max(config.BufferSize, 1)
+	config.MaxAttempts = max(config.MaxAttempts, 1)
+	config.MaxBackoff = max(config.MaxBackoff, config.InitialBackoff)
+
+	queue := &memoryQueue{
+		logger:   logger.WithCodeNamespace(fmt.Sprintf("%T", &memoryQueue{})),
+		tracer:   tracer,
+		consumer: consumer,
+		config:   config,
+		items:    make(chan *memoryQueueItem, config.BufferSize),
+		stop:     make(chan struct{}),
+		delayed:  make(map[string]*time.Timer),
+	}
+
+	for i := 0; i < config.Workers; i++ {
+		go queue.work()
+	}
+
+	return queue
+}
+
+// Enqueue a task to the queue
+func (queue *memoryQueue) Enqueue(ctx context.Context, task *Task) (queueID string, err error) {
+	ctx, span, ctxLogger := queue.tracer.StartWithLogger(ctx, queue.logger)
+	defer span.End()
+
+	queue.mutex.RLock()
+	defer queue.mutex.RUnlock()
+
+	if queue.closed {
+		return queueID, queue.tracer.WrapErrorSpan(span, stacktrace.NewError("cannot add task to in-memory queue because it is draining"))
+	}
+
+	// The task outlives the HTTP request which enqueued it so it must not be canceled with the request
+	item := &memoryQueueItem{ctx: context.WithoutCancel(ctx), id: uuid.NewString(), task: task, attempt: 1}
+
+	if delay := time.Until(task.DueAt); delay > 0 {
+		if !queue.delay(item, delay) {
+			return queueID, queue.tracer.WrapErrorSpan(span, stacktrace.NewError("cannot add delayed task to in-memory queue because it is draining"))
+		}
+		ctxLogger.Info(fmt.Sprintf("item added to in-memory queue with id [%s] and schedule [%s]", item.id, task.DueAt))
+		return item.id, nil
+	}
+
+	queue.pending.Add(1)
+	select {
+	case queue.items <- item:
+	default:
+		queue.pending.Done()
+		msg := fmt.Sprintf("cannot add task to in-memory queue because the buffer of [%d] tasks is full", queue.config.BufferSize)
+		return queueID, queue.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("item added to in-memory queue with id [%s]", item.id))
+	return item.id, nil
+}
+
+// Drain stops accepting new tasks and waits until the pending tasks are processed or the context is done.
+// The delayed tasks and the retries which are not due yet are dropped because they are only kept in memory.
+func (queue *memoryQueue) Drain(ctx context.Context) error {
+	queue.mutex.Lock()
+	if queue.closed {
+		queue.mutex.Unlock()
+		return nil
+	}
+	queue.closed = true
+	queue.mutex.Unlock()
+
+	queue.delayedMutex.Lock()
+	queue.draining = true
+	for _, timer := range queue.delayed {
+		timer.Stop()
+	}
+	dropped := len(queue.delayed)
+	clear(queue.delayed)
+	queue.delayedMutex.Unlock()
+
+	if dropped > 0 {
+		queue.logger.Warn(stacktrace.NewError(fmt.Sprintf("dropped [%d] tasks of the in-memory queue which are not due yet", dropped)))
+	}
+
+	done := make(chan struct{})
+	go func() {
+		queue.pending.Wait()
+		close(done)
+	}()
+
+	defer close(queue.stop)
+
+	select {
+	case <-done:
+		queue.logger.Info("in-memory queue is drained")
+		return nil
+	case <-ctx.Done():
+		return stacktrace.Propagate(ctx.Err(), "cannot drain the in-memory queue before the context is done")
+	}
+}
+
+func (queue *memoryQueue) work() {
+	for {
+		select {
+		case <-queue.stop:
+			return
+		case item := <-queue.items:
+			queue.consume(item)
+		}
+	}
+}
+
+func (queue *memoryQueue) consume(item *memoryQueueItem) {
+	ctx, span, ctxLogger := queue.tracer.StartWithLogger(item.ctx, queue.logger)
+	defer span.End()
+
+	err := queue.consumer(ctx, item.task)
+	if err == nil {
+		queue.pending.Done()
+		return
+	}
+
+	if item.attempt >= queue.config.MaxAttempts {
+		queue.pending.Done()
+		msg := fmt.Sprintf("cannot consume task with id [%s] and body [%s] after [%d] attempts", item.id, string(item.task.Body), item.attempt)
+		ctxLogger.Error(queue.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return
+	}
+
+	backoff := queue.backoff(item.attempt)
+	msg := fmt.Sprintf("cannot consume task with id [%s] on attempt [%d], retrying in [%s]", item.id, item.attempt, backoff)
+	ctxLogger.Warn(stacktrace.Propagate(err, msg))
+
+	item.attempt++
+	if !queue.delay(item, backoff) {
+		msg = fmt.Sprintf("cannot retry task with id [%s] and body [%s] because the in-memory queue is draining", item.id, string(item.task.Body))
+		ctxLogger.Error(stacktrace.NewError(msg))
+	}
+	queue.pending.Done()
+}
+
+// delay holds a task until it is due, it returns false when the queue is draining
+func (queue *memoryQueue) delay(item *memoryQueueItem, delay time.Duration) bool {
+	queue.delayedMutex.Lock()
+	defer queue.delayedMutex.Unlock()
+
+	if queue.draining {
+		return false
+	}
+
+	queue.delayed[item.id] = time.AfterFunc(delay, func() { queue.due(item) })
+	return true
+}
+
+// due makes a delayed task pending and adds it back to the queue, the task is skipped when it was dropped by Drain
+func (queue *memoryQueue) due(item *memoryQueueItem) {
+	queue.delayedMutex.Lock()
+	if _, ok := queue.delayed[item.id]; !ok {
+		queue.delayedMutex.Unlock()
+		return
+	}
+	delete(queue.delayed, item.id)
+	queue.pending.Add(1)
+	queue.delayedMutex.Unlock()
+
+	queue.requeue(item)
+}
+
+// requeue adds a task which is due back to the queue, it blocks when the buffer is full because the task is already pending
+func (queue *memoryQueue) requeue(item *memoryQueueItem) {
+	select {
+	case queue.items <- item:
+	case <-queue.stop:
+		queue.pending.Done()
+		msg := fmt.Sprintf("cannot requeue task with id [%s] and body [%s] because the in-memory queue is stopped", item.id, string(item.task.Body))
+		queue.logger.Error(stacktrace.NewError(msg))
+	}
+}
+
+// backoff returns the exponential delay before the next attempt with up to 20% jitter
+func (queue *memoryQueue) backoff(attempt int) time.Duration {
+	delay := queue.config.InitialBackoff << (attempt - 1)
+	if delay <= 0 || delay > queue.config.MaxBackoff {
+		delay = queue.config.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay + rand.N(delay/5+1)
+}
@@ -0,0 +1,60 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/nats-io/nats.go"
+	"github.com/palantir/stacktrace"
+)
+
+type natsPubSub struct {
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	connection *nats.Conn
+}
+
+// NewNatsPubSub creates a PubSub which uses core NATS subjects
+func NewNatsPubSub(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	connection *nats.Conn,
+) PubSub {
+	return &natsPubSub{
+		logger:     logger.WithCodeNamespace(fmt.Sprintf("%T", &natsPubSub{})),
+		tracer:     tracer,
+		connection: connection,
+	}
+}
+
+// Publish a message to the subscribers of a subject
+func (pubSub *natsPubSub) Publish(ctx context.Context, channel string, message []byte) error {
+	_, span := pubSub.tracer.Start(ctx)
+	defer span.End()
+
+	if err := pubSub.connection.Publish(channel, message); err != nil {
+		return pubSub.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot publish message to subject [%s]", channel)))
+	}
+	return nil
+}
+
+// Subscribe to a subject in the background until the context is done
+func (pubSub *natsPubSub) Subscribe(ctx context.Context, channel string, handler func(ctx context.Context, message []byte)) error {
+	subscription, err := pubSub.connection.Subscribe(channel, func(message *nats.Msg) {
+		handler(ctx, message.Data)
+	})
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot subscribe to subject [%s]", channel))
+	}
+
+	go func() {
+		<-ctx.Done()
+		if err := subscription.Unsubscribe(); err != nil {
+			pubSub.logger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot unsubscribe from subject [%s]", channel)))
+		}
+	}()
+
+	pubSub.logger.Info(fmt.Sprintf("subscribed to subject [%s]", channel))
+	return nil
+}
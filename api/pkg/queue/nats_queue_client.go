@@ -0,0 +1,67 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	natsHeaderMethod = "Task-Method"
+	natsHeaderURL    = "Task-URL"
+	natsHeaderDueAt  = "Task-Due-At"
+)
+
+type natsQueue struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	js      jetstream.JetStream
+	subject string
+}
+
+// NewNatsQueue creates a queue which publishes each Task to a NATS JetStream subject
+func NewNatsQueue(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	js jetstream.JetStream,
+	subject string,
+) Client {
+	return &natsQueue{
+		logger:  logger.WithCodeNamespace(fmt.Sprintf("%T", &natsQueue{})),
+		tracer:  tracer,
+		js:      js,
+		subject: subject,
+	}
+}
+
+// Enqueue a task to the queue
+func (queue *natsQueue) Enqueue(ctx context.Context, task *Task) (queueID string, err error) {
+	ctx, span, ctxLogger := queue.tracer.StartWithLogger(ctx, queue.logger)
+	defer span.End()
+
+	message := nats.NewMsg(queue.subject)
+	message.Header.Set(natsHeaderMethod, task.Method)
+	message.Header.Set(natsHeaderURL, task.URL)
+	message.Data = task.Body
+	if task.DueAt.After(time.Now()) {
+		message.Header.Set(natsHeaderDueAt, strconv.FormatInt(task.DueAt.UnixMilli(), 10))
+	}
+
+	// The message ID lets JetStream discard duplicates when the publish is retried
+	ack, err := queue.js.PublishMsg(ctx, message, jetstream.WithMsgID(uuid.NewString()))
+	if err != nil {
+		msg := fmt.Sprintf("cannot publish task with body [%s] to subject [%s]", string(task.Body), queue.subject)
+		return queueID, queue.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	queueID = fmt.Sprintf("%s:%d", ack.Stream, ack.Sequence)
+	ctxLogger.Info(fmt.Sprintf("item added to subject [%s] with id [%s]", queue.subject, queueID))
+	return queueID, nil
+}
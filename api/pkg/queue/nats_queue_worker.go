@@ -0,0 +1,212 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/palantir/stacktrace"
+)
+
+type natsQueueWorker struct {
+	logger   telemetry.Logger
+	tracer   telemetry.Tracer
+	js       jetstream.JetStream
+	stream   string
+	subject  string
+	config   WorkerConfig
+	consumer Consumer
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewNatsQueueWorker creates a Worker which consumes the tasks published on a NATS JetStream subject using a durable consumer.
+// A task is acknowledged after it is consumed so it is delivered at least once.
+func NewNatsQueueWorker(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	js jetstream.JetStream,
+	stream string,
+	subject string,
+	config WorkerConfig,
+	consumer Consumer,
+) Worker {
+	config.Concurrency = max(config.Concurrency, 1)
+	config.MaxAttempts = max(config.MaxAttempts, 1)
+
+	return &natsQueueWorker{
+		logger:   logger.WithCodeNamespace(fmt.Sprintf("%T", &natsQueueWorker{})),
+		tracer:   tracer,
+		js:       js,
+		stream:   stream,
+		subject:  subject,
+		config:   config,
+		consumer: consumer,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start creates the stream and the durable consumer if they do not exist and consumes the tasks in the background
+func (worker *natsQueueWorker) Start(ctx context.Context) error {
+	_, err := worker.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     worker.stream,
+		Subjects: []string{worker.subject, worker.config.DeadLetter},
+	})
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot create stream [%s] for subject [%s]", worker.stream, worker.subject))
+	}
+
+	consumer, err := worker.js.CreateOrUpdateConsumer(ctx, worker.stream, jetstream.ConsumerConfig{
+		Durable:       worker.config.Group,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       worker.config.RetryDelay,
+		MaxDeliver:    -1,
+		FilterSubject: worker.subject,
+	})
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot create consumer [%s] for stream [%s]", worker.config.Group, worker.stream))
+	}
+
+	ctx, worker.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	go worker.run(ctx, consumer)
+
+	worker.logger.Info(fmt.Sprintf("consuming subject [%s] as [%s] with consumer [%s]", worker.subject, worker.config.Name, worker.config.Group))
+	return nil
+}
+
+// Drain stops fetching new tasks and waits until the tasks which are being consumed are acknowledged
+func (worker *natsQueueWorker) Drain(ctx context.Context) error {
+	if worker.cancel == nil {
+		return nil
+	}
+
+	worker.cancel()
+
+	select {
+	case <-worker.done:
+		worker.logger.Info(fmt.Sprintf("subject [%s] worker is drained", worker.subject))
+		return nil
+	case <-ctx.Done():
+		return stacktrace.Propagate(ctx.Err(), fmt.Sprintf("cannot drain the worker for subject [%s] before the context is done", worker.subject))
+	}
+}
+
+func (worker *natsQueueWorker) run(ctx context.Context, consumer jetstream.Consumer) {
+	defer close(worker.done)
+
+	for ctx.Err() == nil {
+		batch, err := consumer.Fetch(worker.config.Concurrency, jetstream.FetchMaxWait(2*time.Second))
+		if err != nil {
+			worker.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot fetch messages from consumer [%s]", worker.config.Group)))
+			worker.sleep(ctx, time.Second)
+			continue
+		}
+
+		var wg sync.WaitGroup
+		for message := range batch.Messages() {
+			wg.Add(1)
+			go func(message jetstream.Msg) {
+				defer wg.Done()
+				worker.consume(context.WithoutCancel(ctx), message)
+			}(message)
+		}
+		wg.Wait()
+
+		if err = batch.Error(); err != nil && !errors.Is(err, nats.ErrTimeout) {
+			worker.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot fetch messages from consumer [%s]", worker.config.Group)))
+		}
+	}
+}
+
+func (worker *natsQueueWorker) consume(ctx context.Context, message jetstream.Msg) {
+	ctx, span, ctxLogger := worker.tracer.StartWithLogger(ctx, worker.logger)
+	defer span.End()
+
+	metadata, err := message.Metadata()
+	if err != nil {
+		msg := fmt.Sprintf("cannot load metadata of message on subject [%s]", message.Subject())
+		ctxLogger.Error(worker.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return
+	}
+
+	task := &Task{
+		Method: message.Headers().Get(natsHeaderMethod),
+		URL:    message.Headers().Get(natsHeaderURL),
+		Body:   message.Data(),
+	}
+
+	attempts := metadata.NumDelivered
+	if dueAt, parseErr := strconv.ParseInt(message.Headers().Get(natsHeaderDueAt), 10, 64); parseErr == nil {
+		task.DueAt = time.UnixMilli(dueAt)
+		if delay := time.Until(task.DueAt); delay > 0 {
+			if err = message.NakWithDelay(delay); err != nil {
+				ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot delay message with sequence [%d] until [%s]", metadata.Sequence.Stream, task.DueAt)))
+			}
+			return
+		}
+		// the first delivery of a delayed task is rejected until it is due
+		attempts = max(attempts-1, 1)
+	}
+
+	err = worker.consumer(ctx, task)
+	if err == nil {
+		if err = message.Ack(); err != nil {
+			msg := fmt.Sprintf("cannot acknowledge message with sequence [%d] in stream [%s]", metadata.Sequence.Stream, worker.stream)
+			ctxLogger.Error(worker.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		}
+		return
+	}
+
+	if attempts < uint64(worker.config.MaxAttempts) {
+		msg := fmt.Sprintf("cannot consume message with sequence [%d] on attempt [%d], retrying in [%s]", metadata.Sequence.Stream, attempts, worker.config.RetryDelay)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		if err = message.NakWithDelay(worker.config.RetryDelay); err != nil {
+			ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot reject message with sequence [%d]", metadata.Sequence.Stream)))
+		}
+		return
+	}
+
+	msg := fmt.Sprintf("cannot consume message with sequence [%d] in stream [%s] after [%d] attempts", metadata.Sequence.Stream, worker.stream, attempts)
+	ctxLogger.Error(worker.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+
+	if err = worker.deadLetter(ctx, message, metadata, err); err != nil {
+		msg = fmt.Sprintf("cannot move message with sequence [%d] to dead letter subject [%s]", metadata.Sequence.Stream, worker.config.DeadLetter)
+		ctxLogger.Error(worker.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+	}
+}
+
+// deadLetter publishes the message on the dead letter subject and terminates it so it is not delivered again
+func (worker *natsQueueWorker) deadLetter(ctx context.Context, message jetstream.Msg, metadata *jetstream.MsgMetadata, cause error) error {
+	deadLetter := nats.NewMsg(worker.config.DeadLetter)
+	for key, values := range message.Headers() {
+		deadLetter.Header[key] = values
+	}
+	deadLetter.Header.Set("Task-Attempts", strconv.FormatUint(metadata.NumDelivered, 10))
+	// header values cannot contain new lines
+	deadLetter.Header.Set("Task-Error", strings.Join(strings.Fields(cause.Error()), " "))
+	deadLetter.Data = message.Data()
+
+	msgID := fmt.Sprintf("%s-%d", worker.stream, metadata.Sequence.Stream)
+	if _, err := worker.js.PublishMsg(ctx, deadLetter, jetstream.WithMsgID(msgID)); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot publish message with sequence [%d] to subject [%s]", metadata.Sequence.Stream, worker.config.DeadLetter))
+	}
+
+	if err := message.Term(); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot terminate message with sequence [%d]", metadata.Sequence.Stream))
+	}
+	return nil
+}
+
+func (worker *natsQueueWorker) sleep(ctx context.Context, duration time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(duration):
+	}
+}
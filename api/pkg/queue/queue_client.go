@@ -2,6 +2,7 @@ package queue
 
 import (
 	"context"
+	"time"
 )
 
 // Client is a push queue client
@@ -9,3 +10,42 @@ type Client interface {
 	// Enqueue adds a message to the push queue
 	Enqueue(ctx context.Context, task *Task) (string, error)
 }
+
+// PubSub broadcasts messages to every instance of the application which subscribed to a channel.
+// Unlike a Client, a message is not stored and it is lost when no instance is subscribed.
+type PubSub interface {
+	// Publish a message to the subscribers of a channel
+	Publish(ctx context.Context, channel string, message []byte) error
+
+	// Subscribe to a channel in the background until the context is done
+	Subscribe(ctx context.Context, channel string, handler func(ctx context.Context, message []byte)) error
+}
+
+// Worker consumes the tasks which were added to a shared queue by any instance of the application
+type Worker interface {
+	Drainer
+
+	// Start consuming tasks in the background until the worker is drained
+	Start(ctx context.Context) error
+}
+
+// WorkerConfig configures a Worker
+type WorkerConfig struct {
+	// Group is shared by all the instances of the application so that each task is consumed by a single instance
+	Group string
+
+	// Name identifies this instance of the application in the Group
+	Name string
+
+	// Concurrency is the number of tasks which are consumed at the same time
+	Concurrency int
+
+	// MaxAttempts is the number of times a task is consumed before it is moved to the dead letter queue
+	MaxAttempts int
+
+	// RetryDelay is the time to wait before a task which failed is consumed again
+	RetryDelay time.Duration
+
+	// DeadLetter is the stream or subject where the tasks which failed MaxAttempts times are stored
+	DeadLetter string
+}
@@ -1,8 +1,13 @@
 package queue
 
+import "time"
+
 // Task represents a push queue task
 type Task struct {
 	Method string
 	URL    string
 	Body   []byte
+
+	// DueAt is the time before which the task is not consumed, the task is consumed as soon as possible when it is zero
+	DueAt time.Time
 }
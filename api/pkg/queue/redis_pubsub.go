@@ -0,0 +1,69 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"github.com/redis/go-redis/v9"
+)
+
+type redisPubSub struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	client *redis.Client
+}
+
+// NewRedisPubSub creates a PubSub which uses Redis Pub/Sub channels
+func NewRedisPubSub(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	client *redis.Client,
+) PubSub {
+	return &redisPubSub{
+		logger: logger.WithCodeNamespace(fmt.Sprintf("%T", &redisPubSub{})),
+		tracer: tracer,
+		client: client,
+	}
+}
+
+// Publish a message to the subscribers of a channel
+func (pubSub *redisPubSub) Publish(ctx context.Context, channel string, message []byte) error {
+	ctx, span := pubSub.tracer.Start(ctx)
+	defer span.End()
+
+	if err := pubSub.client.Publish(ctx, channel, message).Err(); err != nil {
+		return pubSub.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot publish message to redis channel [%s]", channel)))
+	}
+	return nil
+}
+
+// Subscribe to a channel in the background until the context is done
+func (pubSub *redisPubSub) Subscribe(ctx context.Context, channel string, handler func(ctx context.Context, message []byte)) error {
+	subscription := pubSub.client.Subscribe(ctx, channel)
+	if _, err := subscription.Receive(ctx); err != nil {
+		_ = subscription.Close()
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot subscribe to redis channel [%s]", channel))
+	}
+
+	go func() {
+		defer func() { _ = subscription.Close() }()
+
+		messages := subscription.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				handler(ctx, []byte(message.Payload))
+			}
+		}
+	}()
+
+	pubSub.logger.Info(fmt.Sprintf("subscribed to redis channel [%s]", channel))
+	return nil
+}
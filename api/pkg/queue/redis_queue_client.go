@@ -0,0 +1,120 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisFieldMethod = "method"
+	redisFieldURL    = "url"
+	redisFieldBody   = "body"
+)
+
+// redisDelayedTask is a Task which is stored in the sorted set of delayed tasks until it is due
+type redisDelayedTask struct {
+	ID     string `json:"id"`
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body"`
+}
+
+// redisDelayedKey is the sorted set which contains the tasks of a stream which are not due yet, the score is the due time in milliseconds
+func redisDelayedKey(stream string) string {
+	return RedisStreamKey(stream, "delayed")
+}
+
+// RedisStreamKey is the key of a suffix of a stream e.g. its dead letter stream. The key has the same hash tag as the stream so that
+// both keys are in the same slot of a Redis Cluster and can be used in one script or transaction.
+func RedisStreamKey(stream string, suffix string) string {
+	if start := strings.Index(stream, "{"); start >= 0 {
+		// a stream with a hash tag is hashed by its tag which the suffix does not change
+		if end := strings.Index(stream[start+1:], "}"); end > 0 {
+			return stream + ":" + suffix
+		}
+	}
+	// the hash tag of a stream without a tag is its full name which is the value used to hash the stream
+	return "{" + stream + "}:" + suffix
+}
+
+type redisQueue struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	client *redis.Client
+	stream string
+}
+
+// NewRedisQueue creates a queue which adds each Task to a Redis stream
+func NewRedisQueue(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	client *redis.Client,
+	stream string,
+) Client {
+	return &redisQueue{
+		logger: logger.WithCodeNamespace(fmt.Sprintf("%T", &redisQueue{})),
+		tracer: tracer,
+		client: client,
+		stream: stream,
+	}
+}
+
+// Enqueue a task to the queue
+func (queue *redisQueue) Enqueue(ctx context.Context, task *Task) (queueID string, err error) {
+	ctx, span, ctxLogger := queue.tracer.StartWithLogger(ctx, queue.logger)
+	defer span.End()
+
+	if task.DueAt.After(time.Now()) {
+		if queueID, err = queue.delay(ctx, task); err != nil {
+			return queueID, queue.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, "cannot delay task"))
+		}
+		ctxLogger.Info(fmt.Sprintf("item added to redis sorted set [%s] with id [%s] and schedule [%s]", redisDelayedKey(queue.stream), queueID, task.DueAt))
+		return queueID, nil
+	}
+
+	queueID, err = queue.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: queue.stream,
+		Values: map[string]any{
+			redisFieldMethod: task.Method,
+			redisFieldURL:    task.URL,
+			redisFieldBody:   task.Body,
+		},
+	}).Result()
+	if err != nil {
+		msg := fmt.Sprintf("cannot add task with body [%s] to redis stream [%s]", string(task.Body), queue.stream)
+		return queueID, queue.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("item added to redis stream [%s] with id [%s]", queue.stream, queueID))
+	return queueID, nil
+}
+
+// delay adds a task to the sorted set of delayed tasks, the task is added to the stream by a worker when it is due
+func (queue *redisQueue) delay(ctx context.Context, task *Task) (string, error) {
+	delayed := &redisDelayedTask{
+		ID:     uuid.NewString(),
+		Method: task.Method,
+		URL:    task.URL,
+		Body:   string(task.Body),
+	}
+
+	member, err := json.Marshal(delayed)
+	if err != nil {
+		return "", stacktrace.Propagate(err, fmt.Sprintf("cannot marshal delayed task with body [%s]", string(task.Body)))
+	}
+
+	key := redisDelayedKey(queue.stream)
+	if err = queue.client.ZAdd(ctx, key, redis.Z{Score: float64(task.DueAt.UnixMilli()), Member: member}).Err(); err != nil {
+		return "", stacktrace.Propagate(err, fmt.Sprintf("cannot add task with body [%s] to redis sorted set [%s]", string(task.Body), key))
+	}
+
+	return delayed.ID, nil
+}
@@ -0,0 +1,262 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+	"github.com/redis/go-redis/v9"
+)
+
+// redisPromoteScript moves the delayed tasks which are due from the sorted set KEYS[1] to the stream KEYS[2] in a single transaction
+var redisPromoteScript = redis.NewScript(`
+local members = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, member in ipairs(members) do
+	local task = cjson.decode(member)
+	redis.call('XADD', KEYS[2], '*', '` + redisFieldMethod + `', task.method, '` + redisFieldURL + `', task.url, '` + redisFieldBody + `', task.body)
+	redis.call('ZREM', KEYS[1], member)
+end
+return #members
+`)
+
+type redisQueueWorker struct {
+	logger   telemetry.Logger
+	tracer   telemetry.Tracer
+	client   *redis.Client
+	stream   string
+	config   WorkerConfig
+	consumer Consumer
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewRedisQueueWorker creates a Worker which consumes the tasks in a Redis stream using a consumer group.
+// A task is acknowledged after it is consumed so it is delivered at least once.
+func NewRedisQueueWorker(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	client *redis.Client,
+	stream string,
+	config WorkerConfig,
+	consumer Consumer,
+) Worker {
+	config.Concurrency = max(config.Concurrency, 1)
+	config.MaxAttempts = max(config.MaxAttempts, 1)
+
+	return &redisQueueWorker{
+		logger:   logger.WithCodeNamespace(fmt.Sprintf("%T", &redisQueueWorker{})),
+		tracer:   tracer,
+		client:   client,
+		stream:   stream,
+		config:   config,
+		consumer: consumer,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start creates the consumer group if it does not exist and consumes the tasks in the background
+func (worker *redisQueueWorker) Start(ctx context.Context) error {
+	err := worker.client.XGroupCreateMkStream(ctx, worker.stream, worker.config.Group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot create consumer group [%s] for redis stream [%s]", worker.config.Group, worker.stream))
+	}
+
+	ctx, worker.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	go worker.run(ctx)
+
+	worker.logger.Info(fmt.Sprintf("consuming redis stream [%s] as [%s] in group [%s]", worker.stream, worker.config.Name, worker.config.Group))
+	return nil
+}
+
+// Drain stops reading new tasks and waits until the tasks which are being consumed are acknowledged
+func (worker *redisQueueWorker) Drain(ctx context.Context) error {
+	if worker.cancel == nil {
+		return nil
+	}
+
+	worker.cancel()
+
+	select {
+	case <-worker.done:
+		worker.logger.Info(fmt.Sprintf("redis stream [%s] worker is drained", worker.stream))
+		return nil
+	case <-ctx.Done():
+		return stacktrace.Propagate(ctx.Err(), fmt.Sprintf("cannot drain the worker for redis stream [%s] before the context is done", worker.stream))
+	}
+}
+
+func (worker *redisQueueWorker) run(ctx context.Context) {
+	defer close(worker.done)
+
+	for ctx.Err() == nil {
+		if err := worker.promote(ctx); err != nil && ctx.Err() == nil {
+			worker.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot promote delayed tasks of redis stream [%s]", worker.stream)))
+		}
+
+		messages, err := worker.read(ctx)
+		if err != nil && ctx.Err() == nil {
+			worker.logger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot read messages from redis stream [%s]", worker.stream)))
+			worker.sleep(ctx, time.Second)
+			continue
+		}
+
+		// messages which were read are consumed even when the worker is draining so they are not redelivered
+		var wg sync.WaitGroup
+		for _, message := range messages {
+			wg.Add(1)
+			go func(message redis.XMessage) {
+				defer wg.Done()
+				worker.consume(context.WithoutCancel(ctx), message)
+			}(message)
+		}
+		wg.Wait()
+	}
+}
+
+// promote adds the delayed tasks which are due to the stream, it can be executed by every instance at the same time
+func (worker *redisQueueWorker) promote(ctx context.Context) error {
+	keys := []string{redisDelayedKey(worker.stream), worker.stream}
+	if err := redisPromoteScript.Run(ctx, worker.client, keys, time.Now().UnixMilli(), 100).Err(); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot move due tasks from [%s] to [%s]", keys[0], keys[1]))
+	}
+	return nil
+}
+
+// read claims the messages which failed or belonged to a stopped consumer before reading new messages
+func (worker *redisQueueWorker) read(ctx context.Context) ([]redis.XMessage, error) {
+	messages, _, err := worker.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   worker.stream,
+		Group:    worker.config.Group,
+		Consumer: worker.config.Name,
+		MinIdle:  worker.config.RetryDelay,
+		Start:    "0-0",
+		Count:    int64(worker.config.Concurrency),
+	}).Result()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot claim pending messages for group [%s]", worker.config.Group))
+	}
+	if len(messages) > 0 {
+		return messages, nil
+	}
+
+	streams, err := worker.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    worker.config.Group,
+		Consumer: worker.config.Name,
+		Streams:  []string{worker.stream, ">"},
+		Count:    int64(worker.config.Concurrency),
+		Block:    2 * time.Second,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot read new messages for group [%s]", worker.config.Group))
+	}
+
+	for _, stream := range streams {
+		messages = append(messages, stream.Messages...)
+	}
+	return messages, nil
+}
+
+func (worker *redisQueueWorker) consume(ctx context.Context, message redis.XMessage) {
+	ctx, span, ctxLogger := worker.tracer.StartWithLogger(ctx, worker.logger)
+	defer span.End()
+
+	err := worker.consumer(ctx, worker.task(message))
+	if err == nil {
+		if err = worker.client.XAck(ctx, worker.stream, worker.config.Group, message.ID).Err(); err != nil {
+			msg := fmt.Sprintf("cannot acknowledge message with id [%s] in redis stream [%s]", message.ID, worker.stream)
+			ctxLogger.Error(worker.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		}
+		return
+	}
+
+	attempts, pendingErr := worker.attempts(ctx, message.ID)
+	if pendingErr != nil {
+		msg := fmt.Sprintf("cannot load the number of attempts for message with id [%s] in redis stream [%s]", message.ID, worker.stream)
+		ctxLogger.Error(worker.tracer.WrapErrorSpan(span, stacktrace.Propagate(pendingErr, msg)))
+		return
+	}
+
+	if attempts < int64(worker.config.MaxAttempts) {
+		msg := fmt.Sprintf("cannot consume message with id [%s] on attempt [%d], retrying in [%s]", message.ID, attempts, worker.config.RetryDelay)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+		return
+	}
+
+	msg := fmt.Sprintf("cannot consume message with id [%s] in redis stream [%s] after [%d] attempts", message.ID, worker.stream, attempts)
+	ctxLogger.Error(worker.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+
+	if err = worker.deadLetter(ctx, message, attempts, err); err != nil {
+		msg = fmt.Sprintf("cannot move message with id [%s] to dead letter stream [%s]", message.ID, worker.config.DeadLetter)
+		ctxLogger.Error(worker.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+	}
+}
+
+// attempts returns the number of times a pending message was delivered
+func (worker *redisQueueWorker) attempts(ctx context.Context, messageID string) (int64, error) {
+	pending, err := worker.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: worker.stream,
+		Group:  worker.config.Group,
+		Start:  messageID,
+		End:    messageID,
+		Count:  1,
+	}).Result()
+	if err != nil {
+		return 0, stacktrace.Propagate(err, fmt.Sprintf("cannot load pending message with id [%s]", messageID))
+	}
+	if len(pending) == 0 {
+		return 0, stacktrace.NewError(fmt.Sprintf("message with id [%s] is not pending in group [%s]", messageID, worker.config.Group))
+	}
+	return pending[0].RetryCount, nil
+}
+
+// deadLetter adds the message to the dead letter stream and acknowledges it in a single transaction
+func (worker *redisQueueWorker) deadLetter(ctx context.Context, message redis.XMessage, attempts int64, cause error) error {
+	values := map[string]any{
+		"message_id": message.ID,
+		"attempts":   attempts,
+		"error":      cause.Error(),
+	}
+	for key, value := range message.Values {
+		values[key] = value
+	}
+
+	_, err := worker.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAdd(ctx, &redis.XAddArgs{Stream: worker.config.DeadLetter, Values: values})
+		pipe.XAck(ctx, worker.stream, worker.config.Group, message.ID)
+		return nil
+	})
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot execute dead letter transaction for message with id [%s]", message.ID))
+	}
+	return nil
+}
+
+func (worker *redisQueueWorker) task(message redis.XMessage) *Task {
+	value := func(key string) string {
+		if v, ok := message.Values[key].(string); ok {
+			return v
+		}
+		return ""
+	}
+
+	return &Task{
+		Method: value(redisFieldMethod),
+		URL:    value(redisFieldURL),
+		Body:   []byte(value(redisFieldBody)),
+	}
+}
+
+func (worker *redisQueueWorker) sleep(ctx context.Context, duration time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(duration):
+	}
+}
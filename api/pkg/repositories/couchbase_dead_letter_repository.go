@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/couchbase/gocb/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// couchbaseDeadLetterRepository is responsible for persisting entities.DeadLetter
+type couchbaseDeadLetterRepository struct {
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	collection *gocb.Collection
+	cluster    *gocb.Cluster
+}
+
+// NewCouchbaseDeadLetterRepository creates the Couchbase version of the DeadLetterRepository
+func NewCouchbaseDeadLetterRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	collection *gocb.Collection,
+	cluster *gocb.Cluster,
+) DeadLetterRepository {
+	return &couchbaseDeadLetterRepository{
+		logger:     logger.WithCodeNamespace(fmt.Sprintf("%T", &couchbaseDeadLetterRepository{})),
+		tracer:     tracer,
+		collection: collection,
+		cluster:    cluster,
+	}
+}
+
+func (repository *couchbaseDeadLetterRepository) Store(ctx context.Context, deadLetter *entities.DeadLetter) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	_, err := repository.collection.Insert(deadLetter.ID.String(), deadLetter, &gocb.InsertOptions{Context: ctx})
+	if err != nil {
+		msg := fmt.Sprintf("cannot save dead letter with ID [%s]", deadLetter.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *couchbaseDeadLetterRepository) Update(ctx context.Context, deadLetter *entities.DeadLetter) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	_, err := repository.collection.Upsert(deadLetter.ID.String(), deadLetter, &gocb.UpsertOptions{Context: ctx})
+	if err != nil {
+		msg := fmt.Sprintf("cannot update dead letter with ID [%s]", deadLetter.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *couchbaseDeadLetterRepository) Load(ctx context.Context, deadLetterID uuid.UUID) (*entities.DeadLetter, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	result, err := repository.collection.Get(deadLetterID.String(), &gocb.GetOptions{Context: ctx})
+	if errors.Is(err, gocb.ErrDocumentNotFound) {
+		msg := fmt.Sprintf("dead letter with ID [%s] does not exist", deadLetterID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot load dead letter with ID [%s]", deadLetterID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	deadLetter := new(entities.DeadLetter)
+	if err = result.Content(deadLetter); err != nil {
+		msg := fmt.Sprintf("cannot decode dead letter with ID [%s]", deadLetterID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return deadLetter, nil
+}
+
+func (repository *couchbaseDeadLetterRepository) Index(ctx context.Context, status entities.DeadLetterStatus, limit uint, skip uint) ([]*entities.DeadLetter, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := fmt.Sprintf(
+		"SELECT d.* FROM `%s`.`%s`.`%s` d WHERE ($status = '' OR d.status = $status) ORDER BY d.created_at DESC LIMIT $limit OFFSET $skip",
+		repository.collection.Bucket().Name(),
+		repository.collection.ScopeName(),
+		repository.collection.Name(),
+	)
+
+	rows, err := repository.cluster.Query(query, &gocb.QueryOptions{
+		Context: ctx,
+		NamedParameters: map[string]interface{}{
+			"status": string(status),
+			"limit":  limit,
+			"skip":   skip,
+		},
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot load dead letters with status [%s]", status)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			repository.logger.Error(closeErr)
+		}
+	}()
+
+	deadLetters := make([]*entities.DeadLetter, 0)
+	for rows.Next() {
+		deadLetter := new(entities.DeadLetter)
+		if err = rows.Row(deadLetter); err != nil {
+			msg := fmt.Sprintf("cannot decode dead letter with status [%s]", status)
+			return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	return deadLetters, nil
+}
+
+func (repository *couchbaseDeadLetterRepository) DeleteByUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := fmt.Sprintf(
+		"DELETE FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID",
+		repository.collection.Bucket().Name(),
+		repository.collection.ScopeName(),
+		repository.collection.Name(),
+	)
+
+	rows, err := repository.cluster.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		NamedParameters: map[string]interface{}{"userID": string(userID)},
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete dead letters of user with ID [%s]", userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = rows.Close(); err != nil {
+		msg := fmt.Sprintf("cannot close rows after deleting dead letters of user with ID [%s]", userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/couchbase/gocb/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// couchbaseProjectEndpointCallbackAttemptRepository is responsible for persisting entities.ProjectEndpointCallbackAttempt
+type couchbaseProjectEndpointCallbackAttemptRepository struct {
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	collection *gocb.Collection
+	cluster    *gocb.Cluster
+}
+
+// NewCouchbaseProjectEndpointCallbackAttemptRepository creates the Couchbase version of the ProjectEndpointCallbackAttemptRepository
+func NewCouchbaseProjectEndpointCallbackAttemptRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	collection *gocb.Collection,
+	cluster *gocb.Cluster,
+) ProjectEndpointCallbackAttemptRepository {
+	return &couchbaseProjectEndpointCallbackAttemptRepository{
+		logger:     logger.WithCodeNamespace(fmt.Sprintf("%T", &couchbaseProjectEndpointCallbackAttemptRepository{})),
+		tracer:     tracer,
+		collection: collection,
+		cluster:    cluster,
+	}
+}
+
+func (repository *couchbaseProjectEndpointCallbackAttemptRepository) Store(ctx context.Context, attempt *entities.ProjectEndpointCallbackAttempt) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	_, err := repository.collection.Insert(attempt.ID.String(), attempt, &gocb.InsertOptions{Context: ctx})
+	if err != nil {
+		msg := fmt.Sprintf("cannot save callback attempt with ID [%s]", attempt.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *couchbaseProjectEndpointCallbackAttemptRepository) Index(ctx context.Context, userID entities.UserID, requestID string) ([]*entities.ProjectEndpointCallbackAttempt, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := fmt.Sprintf(
+		"SELECT d.* FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID AND d.project_endpoint_request_id = $requestID ORDER BY d.created_at ASC",
+		repository.collection.Bucket().Name(),
+		repository.collection.ScopeName(),
+		repository.collection.Name(),
+	)
+
+	rows, err := repository.cluster.Query(query, &gocb.QueryOptions{
+		Context: ctx,
+		NamedParameters: map[string]interface{}{
+			"userID":    string(userID),
+			"requestID": requestID,
+		},
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot load callback attempts for user with ID [%s] and request ID [%s]", userID, requestID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			repository.logger.Error(closeErr)
+		}
+	}()
+
+	attempts := make([]*entities.ProjectEndpointCallbackAttempt, 0)
+	for rows.Next() {
+		attempt := new(entities.ProjectEndpointCallbackAttempt)
+		if err = rows.Row(attempt); err != nil {
+			msg := fmt.Sprintf("cannot decode callback attempt for request ID [%s]", requestID)
+			return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, nil
+}
+
+func (repository *couchbaseProjectEndpointCallbackAttemptRepository) DeleteByRequest(ctx context.Context, userID entities.UserID, requestID string) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := fmt.Sprintf(
+		"DELETE FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID AND d.project_endpoint_request_id = $requestID",
+		repository.collection.Bucket().Name(),
+		repository.collection.ScopeName(),
+		repository.collection.Name(),
+	)
+
+	rows, err := repository.cluster.Query(query, &gocb.QueryOptions{
+		Context: ctx,
+		NamedParameters: map[string]interface{}{
+			"userID":    string(userID),
+			"requestID": requestID,
+		},
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete callback attempts for user with ID [%s] and request ID [%s]", userID, requestID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = rows.Close(); err != nil {
+		msg := fmt.Sprintf("cannot close rows after deleting callback attempts for request ID [%s]", requestID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *couchbaseProjectEndpointCallbackAttemptRepository) DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := fmt.Sprintf(
+		"DELETE FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID AND d.project_id = $projectID LIMIT $limit RETURNING meta(d).id",
+		repository.collection.Bucket().Name(),
+		repository.collection.ScopeName(),
+		repository.collection.Name(),
+	)
+
+	// the index must contain the documents stored before the query so that a short batch means that the project is cleaned up
+	rows, err := repository.cluster.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: map[string]interface{}{
+			"userID":    string(userID),
+			"projectID": projectID.String(),
+			"limit":     limit,
+		},
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete callback attempts for user with ID [%s] and project ID [%s]", userID, projectID)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	var count uint
+	for rows.Next() {
+		count++
+	}
+
+	if err = rows.Close(); err != nil {
+		msg := fmt.Sprintf("cannot close rows after deleting callback attempts for project ID [%s]", projectID)
+		return count, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return count, nil
+}
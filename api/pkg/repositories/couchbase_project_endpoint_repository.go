@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/matchers"
 	"github.com/NdoleStudio/httpmock/pkg/telemetry"
 	"github.com/couchbase/gocb/v2"
 	"github.com/google/uuid"
@@ -77,6 +78,27 @@ func (repository *couchbaseProjectEndpointRepository) IncreaseRequestCount(ctx c
 	return nil
 }
 
+func (repository *couchbaseProjectEndpointRepository) IncreaseResponseCounter(ctx context.Context, projectEndpointID uuid.UUID) (uint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	result, err := repository.collection.MutateIn(projectEndpointID.String(), []gocb.MutateInSpec{
+		gocb.IncrementSpec("response_counter", int64(1), &gocb.CounterSpecOptions{}),
+	}, &gocb.MutateInOptions{Context: ctx})
+	if err != nil {
+		msg := fmt.Sprintf("cannot increase response_counter [%T] with ID [%s]", &entities.ProjectEndpoint{}, projectEndpointID)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	var counter uint
+	if err = result.ContentAt(0, &counter); err != nil {
+		msg := fmt.Sprintf("cannot decode response_counter [%T] with ID [%s]", &entities.ProjectEndpoint{}, projectEndpointID)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return counter, nil
+}
+
 func (repository *couchbaseProjectEndpointRepository) DecreaseRequestCount(ctx context.Context, projectEndpointID uuid.UUID) error {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
@@ -200,84 +222,124 @@ func (repository *couchbaseProjectEndpointRepository) Delete(ctx context.Context
 	return nil
 }
 
-func (repository *couchbaseProjectEndpointRepository) LoadByRequest(ctx context.Context, subdomain, requestMethod, requestPath string) (*entities.ProjectEndpoint, error) {
+func (repository *couchbaseProjectEndpointRepository) DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error) {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
 	query := fmt.Sprintf(
-		"SELECT d.* FROM `%s`.`%s`.`%s` d WHERE d.project_subdomain = $subdomain AND d.request_path = $path AND (d.request_method = $method OR d.request_method = 'ANY')",
+		"DELETE FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID AND d.project_id = $projectID LIMIT $limit RETURNING meta(d).id",
 		repository.collection.Bucket().Name(),
 		repository.collection.ScopeName(),
 		repository.collection.Name(),
 	)
 
+	// the index must contain the documents stored before the query so that a short batch means that the project is cleaned up
 	rows, err := repository.cluster.Query(query, &gocb.QueryOptions{
-		Context: ctx,
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
 		NamedParameters: map[string]interface{}{
-			"subdomain": subdomain,
-			"path":      requestPath,
-			"method":    strings.ToUpper(requestMethod),
+			"userID":    string(userID),
+			"projectID": projectID.String(),
+			"limit":     limit,
 		},
 	})
 	if err != nil {
-		msg := fmt.Sprintf("endpoint not found with request method [%s] and request path [%s]", requestMethod, requestPath)
-		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		msg := fmt.Sprintf("cannot delete project endpoints for user with ID [%s] and project ID [%s]", userID, projectID)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
-	defer func() {
-		if closeErr := rows.Close(); closeErr != nil {
-			repository.logger.Error(closeErr)
-		}
-	}()
 
-	endpoint := new(entities.ProjectEndpoint)
-	if !rows.Next() {
-		msg := fmt.Sprintf("endpoint not found with request method [%s] and request path [%s]", requestMethod, requestPath)
-		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	var count uint
+	for rows.Next() {
+		count++
 	}
 
-	if err = rows.Row(endpoint); err != nil {
-		msg := fmt.Sprintf("cannot decode endpoint with request method [%s] and request path [%s]", requestMethod, requestPath)
+	if err = rows.Close(); err != nil {
+		msg := fmt.Sprintf("cannot close rows after deleting project endpoints for project ID [%s]", projectID)
+		return count, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return count, nil
+}
+
+func (repository *couchbaseProjectEndpointRepository) LoadByRequest(ctx context.Context, subdomain string, request *matchers.Request) (*entities.ProjectEndpoint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := fmt.Sprintf(
+		"SELECT d.* FROM `%s`.`%s`.`%s` d WHERE d.project_subdomain = $subdomain AND (d.request_method = $method OR d.request_method = 'ANY')",
+		repository.collection.Bucket().Name(),
+		repository.collection.ScopeName(),
+		repository.collection.Name(),
+	)
+
+	endpoints, err := repository.query(ctx, query, map[string]interface{}{
+		"subdomain": subdomain,
+		"method":    strings.ToUpper(request.Method),
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch endpoints with subdomain [%s] and request method [%s]", subdomain, request.Method)
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
 
-	return endpoint, nil
+	matches := matchers.MatchEndpoints(endpoints, request)
+	if len(matches) == 0 {
+		msg := fmt.Sprintf("endpoint not found with request method [%s] and request path [%s]", request.Method, request.Path)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	return matches[0].Endpoint, nil
 }
 
-func (repository *couchbaseProjectEndpointRepository) LoadByRequestForUser(ctx context.Context, userID entities.UserID, projectID uuid.UUID, requestMethod, requestPath string) (*entities.ProjectEndpoint, error) {
+func (repository *couchbaseProjectEndpointRepository) LoadConflicting(ctx context.Context, endpoint *entities.ProjectEndpoint) (*entities.ProjectEndpoint, error) {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
 	var query string
 	params := map[string]interface{}{
-		"userID":    string(userID),
-		"projectID": projectID.String(),
-		"path":      requestPath,
+		"userID":    string(endpoint.UserID),
+		"projectID": endpoint.ProjectID.String(),
 	}
 
-	if requestMethod == "ANY" {
+	if endpoint.RequestMethod == "ANY" {
 		query = fmt.Sprintf(
-			"SELECT d.* FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID AND d.project_id = $projectID AND d.request_path = $path",
+			"SELECT d.* FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID AND d.project_id = $projectID",
 			repository.collection.Bucket().Name(),
 			repository.collection.ScopeName(),
 			repository.collection.Name(),
 		)
 	} else {
 		query = fmt.Sprintf(
-			"SELECT d.* FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID AND d.project_id = $projectID AND d.request_path = $path AND (d.request_method = $method OR d.request_method = 'ANY')",
+			"SELECT d.* FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID AND d.project_id = $projectID AND (d.request_method = $method OR d.request_method = 'ANY')",
 			repository.collection.Bucket().Name(),
 			repository.collection.ScopeName(),
 			repository.collection.Name(),
 		)
-		params["method"] = requestMethod
+		params["method"] = endpoint.RequestMethod
+	}
+
+	endpoints, err := repository.query(ctx, query, params)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch endpoints with project ID [%s] and request method [%s]", endpoint.ProjectID, endpoint.RequestMethod)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	for _, existing := range endpoints {
+		if existing.ID != endpoint.ID && matchers.Conflicts(endpoint, existing) {
+			return existing, nil
+		}
 	}
 
+	msg := fmt.Sprintf("no conflicting endpoint with project ID [%s], request method [%s] and request path [%s]", endpoint.ProjectID, endpoint.RequestMethod, endpoint.RequestPath)
+	return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+}
+
+func (repository *couchbaseProjectEndpointRepository) query(ctx context.Context, query string, params map[string]interface{}) ([]*entities.ProjectEndpoint, error) {
 	rows, err := repository.cluster.Query(query, &gocb.QueryOptions{
 		Context:         ctx,
 		NamedParameters: params,
 	})
 	if err != nil {
-		msg := fmt.Sprintf("endpoint not found with project ID [%s], request method [%s] and request path [%s]", projectID, requestMethod, requestPath)
-		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot execute query [%s]", query))
 	}
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil {
@@ -285,16 +347,14 @@ func (repository *couchbaseProjectEndpointRepository) LoadByRequestForUser(ctx c
 		}
 	}()
 
-	endpoint := new(entities.ProjectEndpoint)
-	if !rows.Next() {
-		msg := fmt.Sprintf("endpoint not found with project ID [%s], request method [%s] and request path [%s]", projectID, requestMethod, requestPath)
-		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
-	}
-
-	if err = rows.Row(endpoint); err != nil {
-		msg := fmt.Sprintf("cannot decode endpoint with project ID [%s], request method [%s] and request path [%s]", projectID, requestMethod, requestPath)
-		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	endpoints := make([]*entities.ProjectEndpoint, 0)
+	for rows.Next() {
+		endpoint := new(entities.ProjectEndpoint)
+		if err = rows.Row(endpoint); err != nil {
+			return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot decode row into [%T] for query [%s]", endpoint, query))
+		}
+		endpoints = append(endpoints, endpoint)
 	}
 
-	return endpoint, nil
+	return endpoints, nil
 }
@@ -4,10 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"sort"
+	"strings"
 	"time"
 
 	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/matchers"
 	"github.com/NdoleStudio/httpmock/pkg/telemetry"
 	"github.com/couchbase/gocb/v2"
 	"github.com/google/uuid"
@@ -64,6 +65,87 @@ func (repository *couchbaseProjectEndpointRequestRepository) Delete(ctx context.
 	return nil
 }
 
+func (repository *couchbaseProjectEndpointRequestRepository) DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := fmt.Sprintf(
+		"DELETE FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID AND d.project_id = $projectID LIMIT $limit RETURNING meta(d).id",
+		repository.collection.Bucket().Name(),
+		repository.collection.ScopeName(),
+		repository.collection.Name(),
+	)
+
+	// the index must contain the documents stored before the query so that a short batch means that the project is cleaned up
+	rows, err := repository.cluster.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: map[string]interface{}{
+			"userID":    string(userID),
+			"projectID": projectID.String(),
+			"limit":     limit,
+		},
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete project endpoint requests for user with ID [%s] and project ID [%s]", userID, projectID)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	var count uint
+	for rows.Next() {
+		count++
+	}
+
+	if err = rows.Close(); err != nil {
+		msg := fmt.Sprintf("cannot close rows after deleting project endpoint requests for project ID [%s]", projectID)
+		return count, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return count, nil
+}
+
+func (repository *couchbaseProjectEndpointRequestRepository) DeleteBefore(ctx context.Context, userID entities.UserID, before time.Time, limit uint) ([]*entities.ProjectEndpointRequest, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := fmt.Sprintf(
+		"DELETE FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID AND d.id < $beforeID LIMIT $limit RETURNING d.*",
+		repository.collection.Bucket().Name(),
+		repository.collection.ScopeName(),
+		repository.collection.Name(),
+	)
+
+	rows, err := repository.cluster.Query(query, &gocb.QueryOptions{
+		Context: ctx,
+		NamedParameters: map[string]interface{}{
+			"userID":   string(userID),
+			"beforeID": requestIDBefore(before),
+			"limit":    limit,
+		},
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete requests created before [%s] for user with ID [%s]", before, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			repository.logger.Error(closeErr)
+		}
+	}()
+
+	requests := make([]*entities.ProjectEndpointRequest, 0)
+	for rows.Next() {
+		request := new(entities.ProjectEndpointRequest)
+		if err = rows.Row(request); err != nil {
+			msg := fmt.Sprintf("cannot decode deleted project endpoint request for user with ID [%s]", userID)
+			return requests, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
 func (repository *couchbaseProjectEndpointRequestRepository) Load(ctx context.Context, userID entities.UserID, requestID ulid.ULID) (*entities.ProjectEndpointRequest, error) {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
@@ -133,7 +215,7 @@ func (repository *couchbaseProjectEndpointRequestRepository) GetProjectTraffic(c
 		data = append(data, point)
 	}
 
-	return repository.normalizeTimeSeries(data), nil
+	return normalizeTimeSeries(data), nil
 }
 
 func (repository *couchbaseProjectEndpointRequestRepository) GetEndpointTraffic(ctx context.Context, userID entities.UserID, endpointID uuid.UUID) ([]*TimeSeriesData, error) {
@@ -177,7 +259,49 @@ func (repository *couchbaseProjectEndpointRequestRepository) GetEndpointTraffic(
 		data = append(data, point)
 	}
 
-	return repository.normalizeTimeSeries(data), nil
+	return normalizeTimeSeries(data), nil
+}
+
+func (repository *couchbaseProjectEndpointRequestRepository) FetchLatest(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) ([]*entities.ProjectEndpointRequest, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := fmt.Sprintf(
+		"SELECT d.* FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID AND d.project_id = $projectID ORDER BY META(d).id DESC LIMIT $limit",
+		repository.collection.Bucket().Name(),
+		repository.collection.ScopeName(),
+		repository.collection.Name(),
+	)
+
+	rows, err := repository.cluster.Query(query, &gocb.QueryOptions{
+		Context: ctx,
+		NamedParameters: map[string]interface{}{
+			"userID":    string(userID),
+			"projectID": projectID.String(),
+			"limit":     int(limit),
+		},
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot load the latest [%d] requests for user with ID [%s] and project ID [%s]", limit, userID, projectID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			repository.logger.Error(closeErr)
+		}
+	}()
+
+	requests := make([]*entities.ProjectEndpointRequest, 0)
+	for rows.Next() {
+		request := new(entities.ProjectEndpointRequest)
+		if err = rows.Row(request); err != nil {
+			msg := fmt.Sprintf("cannot decode project endpoint request for user with ID [%s] and project ID [%s]", userID, projectID)
+			return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, nil
 }
 
 func (repository *couchbaseProjectEndpointRequestRepository) Index(ctx context.Context, userID entities.UserID, endpointID uuid.UUID, limit uint, previousID *ulid.ULID, nextID *ulid.ULID) ([]*entities.ProjectEndpointRequest, error) {
@@ -235,35 +359,139 @@ func (repository *couchbaseProjectEndpointRequestRepository) Index(ctx context.C
 	return requests, nil
 }
 
-func (repository *couchbaseProjectEndpointRequestRepository) generateTimeSeries() map[string]*TimeSeriesData {
-	series := make(map[string]*TimeSeriesData)
-	for i := 0; i < 30; i++ {
-		date := time.Now().UTC().AddDate(0, 0, -i)
-		series[date.Format("2006-01-02")] = &TimeSeriesData{
-			Timestamp: time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -i),
-			Count:     0,
-		}
+func (repository *couchbaseProjectEndpointRequestRepository) Search(ctx context.Context, userID entities.UserID, params *ProjectEndpointRequestSearchParams) ([]*entities.ProjectEndpointRequest, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	queryParams := map[string]interface{}{
+		"userID":    string(userID),
+		"projectID": params.ProjectID.String(),
+		"limit":     int(params.Limit),
 	}
-	return series
-}
 
-func (repository *couchbaseProjectEndpointRequestRepository) normalizeTimeSeries(input []*TimeSeriesData) []*TimeSeriesData {
-	series := repository.generateTimeSeries()
-	for _, data := range input {
-		date := data.Timestamp.Format("2006-01-02")
-		if _, ok := series[date]; ok {
-			series[date].Count = data.Count
+	conditions := []string{"d.user_id = $userID", "d.project_id = $projectID"}
+	if params.From != nil {
+		conditions = append(conditions, "META(d).id >= $fromID")
+		queryParams["fromID"] = requestIDBefore(*params.From)
+	}
+	if params.To != nil {
+		conditions = append(conditions, "META(d).id < $toID")
+		queryParams["toID"] = requestIDBefore(*params.To)
+	}
+	if params.Unmatched != nil {
+		// the requests which were stored before unmatched requests were logged do not have the field
+		conditions = append(conditions, "IFMISSINGORNULL(d.unmatched, false) = $unmatched")
+		queryParams["unmatched"] = *params.Unmatched
+	}
+	if params.Method != "" {
+		conditions = append(conditions, "d.request_method = $method")
+		queryParams["method"] = params.Method
+	}
+	if params.ResponseCode != nil {
+		conditions = append(conditions, "d.response_code = $responseCode")
+		queryParams["responseCode"] = *params.ResponseCode
+	}
+	if params.IPAddress != "" {
+		conditions = append(conditions, "d.request_ip_address = $ipAddress")
+		queryParams["ipAddress"] = params.IPAddress
+	}
+	if params.PathPrefix != "" {
+		conditions = append(conditions, "REGEXP_CONTAINS(d.request_url, $pathPrefix)")
+		queryParams["pathPrefix"] = params.pathPrefixPattern()
+	}
+	if params.HeaderKey != "" {
+		condition := "LOWER(header.name) = $headerKey"
+		queryParams["headerKey"] = strings.ToLower(params.HeaderKey)
+		if params.HeaderValue != nil {
+			condition += " AND header.val = $headerValue"
+			queryParams["headerValue"] = *params.HeaderValue
 		}
+		conditions = append(conditions, "ANY headers IN DECODE_JSON(d.request_headers) SATISFIES (ANY header IN OBJECT_PAIRS(headers) SATISFIES "+condition+" END) END")
+	}
+	if params.Body != "" {
+		conditions = append(conditions, "CONTAINS(LOWER(d.request_body), $body)")
+		queryParams["body"] = strings.ToLower(params.Body)
+	}
+	if params.JSONPath != nil {
+		conditions = append(conditions, repository.jsonPathCondition("DECODE_JSON(d.request_body)", params.JSONPath.Selectors(), params.JSONValue, queryParams))
 	}
 
-	var result []*TimeSeriesData
-	for _, data := range series {
-		result = append(result, data)
+	// ULIDs are sorted by time so the ID is used as the pagination cursor
+	order := "DESC"
+	if params.PreviousID != nil {
+		conditions = append(conditions, "META(d).id < $cursorID")
+		queryParams["cursorID"] = params.PreviousID.String()
+	} else if params.NextID != nil {
+		conditions = append(conditions, "META(d).id > $cursorID")
+		queryParams["cursorID"] = params.NextID.String()
+		order = "ASC"
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].Timestamp.Before(result[j].Timestamp)
+	query := fmt.Sprintf(
+		"SELECT d.* FROM `%s`.`%s`.`%s` d WHERE %s ORDER BY META(d).id %s LIMIT $limit",
+		repository.collection.Bucket().Name(),
+		repository.collection.ScopeName(),
+		repository.collection.Name(),
+		strings.Join(conditions, " AND "),
+		order,
+	)
+
+	rows, err := repository.cluster.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		NamedParameters: queryParams,
 	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot search project endpoint requests for user with ID [%s] and project ID [%s]", userID, params.ProjectID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			repository.logger.Error(closeErr)
+		}
+	}()
+
+	requests := make([]*entities.ProjectEndpointRequest, 0)
+	for rows.Next() {
+		request := new(entities.ProjectEndpointRequest)
+		if err = rows.Row(request); err != nil {
+			msg := fmt.Sprintf("cannot decode project endpoint request for user with ID [%s] and project ID [%s]", userID, params.ProjectID)
+			return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+// jsonPathCondition returns a condition which is true when the selectors select a value in the JSON document which is equal to the value when it is set.
+// The keys and indexes are passed as named parameters so that they behave like matchers.JSONPath in the other repositories.
+func (repository *couchbaseProjectEndpointRequestRepository) jsonPathCondition(document string, selectors []matchers.JSONPathSelector, value *string, params map[string]interface{}) string {
+	for i, selector := range selectors {
+		if selector.Wildcard {
+			child := fmt.Sprintf("child%d", len(selectors)-i)
+			return fmt.Sprintf(
+				"(ANY %[2]s IN (CASE WHEN IS_ARRAY(%[1]s) THEN %[1]s WHEN IS_OBJECT(%[1]s) THEN OBJECT_VALUES(%[1]s) ELSE [] END) SATISFIES %[3]s END)",
+				document,
+				child,
+				repository.jsonPathCondition(child, selectors[i+1:], value, params),
+			)
+		}
+
+		// a selector which does not match the type of the value returns MISSING so that a JSON null can still be selected
+		name := fmt.Sprintf("jsonPath%d", len(selectors)-i)
+		if selector.IsIndex {
+			params[name] = selector.Index
+			document = fmt.Sprintf("(CASE WHEN IS_ARRAY(%[1]s) THEN %[1]s[$%[2]s] ELSE MISSING END)", document, name)
+		} else {
+			params[name] = selector.Key
+			document = fmt.Sprintf("(CASE WHEN IS_OBJECT(%[1]s) THEN %[1]s.[$%[2]s] ELSE MISSING END)", document, name)
+		}
+	}
+
+	if value == nil {
+		return document + " IS NOT MISSING"
+	}
 
-	return result
+	params["jsonValue"] = *value
+	return fmt.Sprintf("(CASE WHEN IS_STRING(%[1]s) THEN %[1]s ELSE ENCODE_JSON(%[1]s) END) = $jsonValue", document)
 }
@@ -146,6 +146,53 @@ func (repository *couchbaseProjectRepository) Delete(ctx context.Context, userID
 	return nil
 }
 
+func (repository *couchbaseProjectRepository) UpdateScenarioState(ctx context.Context, projectID uuid.UUID, scenario string, state string) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := fmt.Sprintf(
+		"UPDATE `%s`.`%s`.`%s` d USE KEYS $id SET d.scenario_states = OBJECT_PUT(IFMISSINGORNULL(d.scenario_states, {}), $scenario, $state)",
+		repository.collection.Bucket().Name(),
+		repository.collection.ScopeName(),
+		repository.collection.Name(),
+	)
+
+	rows, err := repository.cluster.Query(query, &gocb.QueryOptions{
+		Context: ctx,
+		NamedParameters: map[string]interface{}{
+			"id":       projectID.String(),
+			"scenario": scenario,
+			"state":    state,
+		},
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot update scenario [%s] to state [%s] for project with ID [%s]", scenario, state, projectID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = rows.Close(); err != nil {
+		msg := fmt.Sprintf("cannot close rows after updating scenario [%s] for project with ID [%s]", scenario, projectID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *couchbaseProjectRepository) ResetScenarioStates(ctx context.Context, projectID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	_, err := repository.collection.MutateIn(projectID.String(), []gocb.MutateInSpec{
+		gocb.UpsertSpec("scenario_states", map[string]string{}, &gocb.UpsertSpecOptions{}),
+	}, &gocb.MutateInOptions{Context: ctx})
+	if err != nil {
+		msg := fmt.Sprintf("cannot reset scenario states for project with ID [%s]", projectID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
 func (repository *couchbaseProjectRepository) LoadWithSubdomain(ctx context.Context, subdomain string) (*entities.Project, error) {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
@@ -0,0 +1,184 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/couchbase/gocb/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// couchbaseProjectWebhookDeliveryRepository is responsible for persisting entities.ProjectWebhookDelivery
+type couchbaseProjectWebhookDeliveryRepository struct {
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	collection *gocb.Collection
+	cluster    *gocb.Cluster
+}
+
+// NewCouchbaseProjectWebhookDeliveryRepository creates the Couchbase version of the ProjectWebhookDeliveryRepository
+func NewCouchbaseProjectWebhookDeliveryRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	collection *gocb.Collection,
+	cluster *gocb.Cluster,
+) ProjectWebhookDeliveryRepository {
+	return &couchbaseProjectWebhookDeliveryRepository{
+		logger:     logger.WithCodeNamespace(fmt.Sprintf("%T", &couchbaseProjectWebhookDeliveryRepository{})),
+		tracer:     tracer,
+		collection: collection,
+		cluster:    cluster,
+	}
+}
+
+func (repository *couchbaseProjectWebhookDeliveryRepository) Store(ctx context.Context, delivery *entities.ProjectWebhookDelivery) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	_, err := repository.collection.Insert(delivery.ID.String(), delivery, &gocb.InsertOptions{Context: ctx})
+	if err != nil {
+		msg := fmt.Sprintf("cannot save webhook delivery with ID [%s]", delivery.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *couchbaseProjectWebhookDeliveryRepository) Index(ctx context.Context, userID entities.UserID, webhookID uuid.UUID, limit uint) ([]*entities.ProjectWebhookDelivery, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := fmt.Sprintf(
+		"SELECT d.* FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID AND d.project_webhook_id = $webhookID ORDER BY d.created_at DESC LIMIT $limit",
+		repository.collection.Bucket().Name(),
+		repository.collection.ScopeName(),
+		repository.collection.Name(),
+	)
+
+	rows, err := repository.cluster.Query(query, &gocb.QueryOptions{
+		Context: ctx,
+		NamedParameters: map[string]interface{}{
+			"userID":    string(userID),
+			"webhookID": webhookID.String(),
+			"limit":     limit,
+		},
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot load deliveries for user with ID [%s] and webhook ID [%s]", userID, webhookID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			repository.logger.Error(closeErr)
+		}
+	}()
+
+	deliveries := make([]*entities.ProjectWebhookDelivery, 0)
+	for rows.Next() {
+		delivery := new(entities.ProjectWebhookDelivery)
+		if err = rows.Row(delivery); err != nil {
+			msg := fmt.Sprintf("cannot decode delivery for webhook ID [%s]", webhookID)
+			return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (repository *couchbaseProjectWebhookDeliveryRepository) DeleteByWebhook(ctx context.Context, userID entities.UserID, webhookID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := fmt.Sprintf(
+		"DELETE FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID AND d.project_webhook_id = $webhookID",
+		repository.collection.Bucket().Name(),
+		repository.collection.ScopeName(),
+		repository.collection.Name(),
+	)
+
+	rows, err := repository.cluster.Query(query, &gocb.QueryOptions{
+		Context: ctx,
+		NamedParameters: map[string]interface{}{
+			"userID":    string(userID),
+			"webhookID": webhookID.String(),
+		},
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete deliveries for user with ID [%s] and webhook ID [%s]", userID, webhookID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = rows.Close(); err != nil {
+		msg := fmt.Sprintf("cannot close rows after deleting deliveries for webhook ID [%s]", webhookID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *couchbaseProjectWebhookDeliveryRepository) DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := fmt.Sprintf(
+		"DELETE FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID AND d.project_id = $projectID LIMIT $limit RETURNING meta(d).id",
+		repository.collection.Bucket().Name(),
+		repository.collection.ScopeName(),
+		repository.collection.Name(),
+	)
+
+	// the index must contain the documents stored before the query so that a short batch means that the project is cleaned up
+	return repository.count(ctx, query, gocb.QueryScanConsistencyRequestPlus, map[string]interface{}{
+		"userID":    string(userID),
+		"projectID": projectID.String(),
+		"limit":     limit,
+	})
+}
+
+func (repository *couchbaseProjectWebhookDeliveryRepository) DeleteBefore(ctx context.Context, userID entities.UserID, before time.Time, limit uint) (uint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := fmt.Sprintf(
+		"DELETE FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID AND STR_TO_MILLIS(d.created_at) < $before LIMIT $limit RETURNING meta(d).id",
+		repository.collection.Bucket().Name(),
+		repository.collection.ScopeName(),
+		repository.collection.Name(),
+	)
+
+	count, err := repository.count(ctx, query, gocb.QueryScanConsistencyNotBounded, map[string]interface{}{
+		"userID": string(userID),
+		"before": before.UnixMilli(),
+		"limit":  limit,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete deliveries created before [%s] for user with ID [%s]", before, userID)
+		return count, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return count, nil
+}
+
+// count executes a DELETE query and returns the number of deleted documents
+func (repository *couchbaseProjectWebhookDeliveryRepository) count(ctx context.Context, query string, consistency gocb.QueryScanConsistency, params map[string]interface{}) (uint, error) {
+	rows, err := repository.cluster.Query(query, &gocb.QueryOptions{Context: ctx, ScanConsistency: consistency, NamedParameters: params})
+	if err != nil {
+		return 0, stacktrace.Propagate(err, fmt.Sprintf("cannot execute query [%s]", query))
+	}
+
+	var count uint
+	for rows.Next() {
+		count++
+	}
+
+	if err = rows.Close(); err != nil {
+		return count, stacktrace.Propagate(err, fmt.Sprintf("cannot close rows of query [%s]", query))
+	}
+
+	return count, nil
+}
@@ -0,0 +1,188 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/couchbase/gocb/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// couchbaseProjectWebhookRepository is responsible for persisting entities.ProjectWebhook
+type couchbaseProjectWebhookRepository struct {
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	collection *gocb.Collection
+	cluster    *gocb.Cluster
+}
+
+// NewCouchbaseProjectWebhookRepository creates the Couchbase version of the ProjectWebhookRepository
+func NewCouchbaseProjectWebhookRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	collection *gocb.Collection,
+	cluster *gocb.Cluster,
+) ProjectWebhookRepository {
+	return &couchbaseProjectWebhookRepository{
+		logger:     logger.WithCodeNamespace(fmt.Sprintf("%T", &couchbaseProjectWebhookRepository{})),
+		tracer:     tracer,
+		collection: collection,
+		cluster:    cluster,
+	}
+}
+
+func (repository *couchbaseProjectWebhookRepository) Store(ctx context.Context, webhook *entities.ProjectWebhook) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	_, err := repository.collection.Insert(webhook.ID.String(), webhook, &gocb.InsertOptions{Context: ctx})
+	if err != nil {
+		msg := fmt.Sprintf("cannot save webhook with ID [%s]", webhook.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *couchbaseProjectWebhookRepository) Update(ctx context.Context, webhook *entities.ProjectWebhook) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	_, err := repository.collection.Upsert(webhook.ID.String(), webhook, &gocb.UpsertOptions{Context: ctx})
+	if err != nil {
+		msg := fmt.Sprintf("cannot update webhook with ID [%s]", webhook.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *couchbaseProjectWebhookRepository) Index(ctx context.Context, userID entities.UserID, projectID uuid.UUID) ([]*entities.ProjectWebhook, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := fmt.Sprintf(
+		"SELECT d.* FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID AND d.project_id = $projectID ORDER BY d.created_at ASC",
+		repository.collection.Bucket().Name(),
+		repository.collection.ScopeName(),
+		repository.collection.Name(),
+	)
+
+	rows, err := repository.cluster.Query(query, &gocb.QueryOptions{
+		Context: ctx,
+		NamedParameters: map[string]interface{}{
+			"userID":    string(userID),
+			"projectID": projectID.String(),
+		},
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot load webhooks for user with ID [%s] and project ID [%s]", userID, projectID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			repository.logger.Error(closeErr)
+		}
+	}()
+
+	webhooks := make([]*entities.ProjectWebhook, 0)
+	for rows.Next() {
+		webhook := new(entities.ProjectWebhook)
+		if err = rows.Row(webhook); err != nil {
+			msg := fmt.Sprintf("cannot decode webhook for project ID [%s]", projectID)
+			return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, nil
+}
+
+func (repository *couchbaseProjectWebhookRepository) Load(ctx context.Context, userID entities.UserID, projectID uuid.UUID, webhookID uuid.UUID) (*entities.ProjectWebhook, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	result, err := repository.collection.Get(webhookID.String(), &gocb.GetOptions{Context: ctx})
+	if errors.Is(err, gocb.ErrDocumentNotFound) {
+		msg := fmt.Sprintf("webhook with ID [%s] does not exist", webhookID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot load webhook with ID [%s]", webhookID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	webhook := new(entities.ProjectWebhook)
+	if err = result.Content(webhook); err != nil {
+		msg := fmt.Sprintf("cannot decode webhook with ID [%s]", webhookID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if webhook.UserID != userID || webhook.ProjectID != projectID {
+		msg := fmt.Sprintf("webhook with ID [%s] does not exist", webhookID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	return webhook, nil
+}
+
+func (repository *couchbaseProjectWebhookRepository) Delete(ctx context.Context, userID entities.UserID, projectID uuid.UUID, webhookID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	// Verify ownership before deleting
+	if _, err := repository.Load(ctx, userID, projectID, webhookID); err != nil {
+		return err
+	}
+
+	_, err := repository.collection.Remove(webhookID.String(), &gocb.RemoveOptions{Context: ctx})
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete webhook with ID [%s] for user [%s]", webhookID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *couchbaseProjectWebhookRepository) DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := fmt.Sprintf(
+		"DELETE FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID AND d.project_id = $projectID LIMIT $limit RETURNING meta(d).id",
+		repository.collection.Bucket().Name(),
+		repository.collection.ScopeName(),
+		repository.collection.Name(),
+	)
+
+	// the index must contain the documents stored before the query so that a short batch means that the project is cleaned up
+	rows, err := repository.cluster.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+		NamedParameters: map[string]interface{}{
+			"userID":    string(userID),
+			"projectID": projectID.String(),
+			"limit":     limit,
+		},
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete webhooks for user with ID [%s] and project ID [%s]", userID, projectID)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	var count uint
+	for rows.Next() {
+		count++
+	}
+
+	if err = rows.Close(); err != nil {
+		msg := fmt.Sprintf("cannot close rows after deleting webhooks for project ID [%s]", projectID)
+		return count, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return count, nil
+}
@@ -156,3 +156,20 @@ func (repository *couchbaseUserRepository) LoadOrStore(ctx context.Context, auth
 
 	return existingUser, false, nil
 }
+
+func (repository *couchbaseUserRepository) Delete(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	_, err := repository.collection.Remove(string(userID), &gocb.RemoveOptions{Context: ctx})
+	if errors.Is(err, gocb.ErrDocumentNotFound) {
+		msg := fmt.Sprintf("user with ID [%s] does not exist", userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete user with ID [%s]", userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
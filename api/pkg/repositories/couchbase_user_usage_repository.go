@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/couchbase/gocb/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// couchbaseUserUsageRepository is responsible for persisting entities.UserUsage
+type couchbaseUserUsageRepository struct {
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	collection *gocb.Collection
+	cluster    *gocb.Cluster
+}
+
+// NewCouchbaseUserUsageRepository creates the Couchbase version of the UserUsageRepository
+func NewCouchbaseUserUsageRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	collection *gocb.Collection,
+	cluster *gocb.Cluster,
+) UserUsageRepository {
+	return &couchbaseUserUsageRepository{
+		logger:     logger.WithCodeNamespace(fmt.Sprintf("%T", &couchbaseUserUsageRepository{})),
+		tracer:     tracer,
+		collection: collection,
+		cluster:    cluster,
+	}
+}
+
+func (repository *couchbaseUserUsageRepository) IncrementRequestCount(ctx context.Context, userID entities.UserID, periodStart time.Time) (uint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	// the sub-document increment is atomic and creates the document when it does not exist
+	result, err := repository.collection.MutateIn(
+		entities.UserUsageID(userID, periodStart),
+		[]gocb.MutateInSpec{
+			gocb.IncrementSpec("request_count", 1, &gocb.CounterSpecOptions{CreatePath: true}),
+			gocb.UpsertSpec("user_id", userID, nil),
+			gocb.UpsertSpec("period_start", periodStart.UTC(), nil),
+			gocb.UpsertSpec("updated_at", time.Now().UTC(), nil),
+		},
+		&gocb.MutateInOptions{Context: ctx, StoreSemantic: gocb.StoreSemanticsUpsert},
+	)
+	if err != nil {
+		msg := fmt.Sprintf("cannot increment request count for user with ID [%s] and period [%s]", userID, periodStart)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	var count uint
+	if err = result.ContentAt(0, &count); err != nil {
+		msg := fmt.Sprintf("cannot decode request count for user with ID [%s] and period [%s]", userID, periodStart)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return count, nil
+}
+
+func (repository *couchbaseUserUsageRepository) Load(ctx context.Context, userID entities.UserID, periodStart time.Time) (*entities.UserUsage, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	result, err := repository.collection.Get(entities.UserUsageID(userID, periodStart), &gocb.GetOptions{Context: ctx})
+	if errors.Is(err, gocb.ErrDocumentNotFound) {
+		msg := fmt.Sprintf("usage of user with ID [%s] does not exist for period [%s]", userID, periodStart)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot load usage of user with ID [%s] for period [%s]", userID, periodStart)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	usage := new(entities.UserUsage)
+	if err = result.Content(usage); err != nil {
+		msg := fmt.Sprintf("cannot decode usage of user with ID [%s] for period [%s]", userID, periodStart)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return usage, nil
+}
+
+func (repository *couchbaseUserUsageRepository) DeleteByUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := fmt.Sprintf(
+		"DELETE FROM `%s`.`%s`.`%s` d WHERE d.user_id = $userID",
+		repository.collection.Bucket().Name(),
+		repository.collection.ScopeName(),
+		repository.collection.Name(),
+	)
+
+	rows, err := repository.cluster.Query(query, &gocb.QueryOptions{
+		Context:         ctx,
+		NamedParameters: map[string]interface{}{"userID": string(userID)},
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete usages of user with ID [%s]", userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = rows.Close(); err != nil {
+		msg := fmt.Sprintf("cannot close rows after deleting usages of user with ID [%s]", userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+)
+
+// DeadLetterRepository loads and persists an entities.DeadLetter
+type DeadLetterRepository interface {
+	// Store a new entities.DeadLetter
+	Store(ctx context.Context, deadLetter *entities.DeadLetter) error
+
+	// Update an entities.DeadLetter
+	Update(ctx context.Context, deadLetter *entities.DeadLetter) error
+
+	// Load an entities.DeadLetter by its ID
+	Load(ctx context.Context, deadLetterID uuid.UUID) (*entities.DeadLetter, error)
+
+	// Index fetches the entities.DeadLetter with a status ordered from the newest, all the statuses are returned when the status is empty
+	Index(ctx context.Context, status entities.DeadLetterStatus, limit uint, skip uint) ([]*entities.DeadLetter, error)
+
+	// DeleteByUser deletes all the entities.DeadLetter of the events of a user
+	DeleteByUser(ctx context.Context, userID entities.UserID) error
+}
@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// memoryDeadLetterRepository is responsible for persisting entities.DeadLetter in memory
+type memoryDeadLetterRepository struct {
+	logger      telemetry.Logger
+	tracer      telemetry.Tracer
+	lock        sync.RWMutex
+	deadLetters map[uuid.UUID]*entities.DeadLetter
+}
+
+// NewMemoryDeadLetterRepository creates the in-memory version of the DeadLetterRepository
+func NewMemoryDeadLetterRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) DeadLetterRepository {
+	return &memoryDeadLetterRepository{
+		logger:      logger.WithCodeNamespace(fmt.Sprintf("%T", &memoryDeadLetterRepository{})),
+		tracer:      tracer,
+		deadLetters: make(map[uuid.UUID]*entities.DeadLetter),
+	}
+}
+
+func (repository *memoryDeadLetterRepository) Store(ctx context.Context, deadLetter *entities.DeadLetter) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.deadLetters[deadLetter.ID]; ok {
+		msg := fmt.Sprintf("dead letter with ID [%s] already exists", deadLetter.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+	}
+
+	if err := repository.save(deadLetter); err != nil {
+		msg := fmt.Sprintf("cannot save dead letter with ID [%s]", deadLetter.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *memoryDeadLetterRepository) Update(ctx context.Context, deadLetter *entities.DeadLetter) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if err := repository.save(deadLetter); err != nil {
+		msg := fmt.Sprintf("cannot update dead letter with ID [%s]", deadLetter.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *memoryDeadLetterRepository) Load(ctx context.Context, deadLetterID uuid.UUID) (*entities.DeadLetter, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.RLock()
+	defer repository.lock.RUnlock()
+
+	deadLetter, ok := repository.deadLetters[deadLetterID]
+	if !ok {
+		msg := fmt.Sprintf("dead letter with ID [%s] does not exist", deadLetterID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	result, err := memoryCopy(deadLetter)
+	if err != nil {
+		msg := fmt.Sprintf("cannot copy dead letter with ID [%s]", deadLetterID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return result, nil
+}
+
+func (repository *memoryDeadLetterRepository) Index(ctx context.Context, status entities.DeadLetterStatus, limit uint, skip uint) ([]*entities.DeadLetter, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.RLock()
+	defer repository.lock.RUnlock()
+
+	deadLetters := make([]*entities.DeadLetter, 0, len(repository.deadLetters))
+	for _, deadLetter := range repository.deadLetters {
+		if status == "" || deadLetter.Status == status {
+			deadLetters = append(deadLetters, deadLetter)
+		}
+	}
+
+	sort.Slice(deadLetters, func(i, j int) bool {
+		return deadLetters[i].CreatedAt.After(deadLetters[j].CreatedAt)
+	})
+
+	start := min(int(skip), len(deadLetters))
+	end := min(start+int(limit), len(deadLetters))
+
+	result, err := memoryCopies(deadLetters[start:end])
+	if err != nil {
+		msg := fmt.Sprintf("cannot copy dead letters with status [%s]", status)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return result, nil
+}
+
+func (repository *memoryDeadLetterRepository) DeleteByUser(ctx context.Context, userID entities.UserID) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	for id, deadLetter := range repository.deadLetters {
+		if deadLetter.UserID == userID {
+			delete(repository.deadLetters, id)
+		}
+	}
+
+	return nil
+}
+
+// save stores a copy of the entities.DeadLetter. The caller must hold the write lock.
+func (repository *memoryDeadLetterRepository) save(deadLetter *entities.DeadLetter) error {
+	value, err := memoryCopy(deadLetter)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot copy dead letter with ID [%s]", deadLetter.ID))
+	}
+
+	repository.deadLetters[deadLetter.ID] = value
+	return nil
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// memoryProjectEndpointCallbackAttemptRepository is responsible for persisting entities.ProjectEndpointCallbackAttempt in memory
+type memoryProjectEndpointCallbackAttemptRepository struct {
+	logger   telemetry.Logger
+	tracer   telemetry.Tracer
+	lock     sync.RWMutex
+	attempts map[uuid.UUID]*entities.ProjectEndpointCallbackAttempt
+}
+
+// NewMemoryProjectEndpointCallbackAttemptRepository creates the in-memory version of the ProjectEndpointCallbackAttemptRepository
+func NewMemoryProjectEndpointCallbackAttemptRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) ProjectEndpointCallbackAttemptRepository {
+	return &memoryProjectEndpointCallbackAttemptRepository{
+		logger:   logger.WithCodeNamespace(fmt.Sprintf("%T", &memoryProjectEndpointCallbackAttemptRepository{})),
+		tracer:   tracer,
+		attempts: make(map[uuid.UUID]*entities.ProjectEndpointCallbackAttempt),
+	}
+}
+
+func (repository *memoryProjectEndpointCallbackAttemptRepository) Store(ctx context.Context, attempt *entities.ProjectEndpointCallbackAttempt) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.attempts[attempt.ID]; ok {
+		msg := fmt.Sprintf("callback attempt with ID [%s] already exists", attempt.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+	}
+
+	value, err := memoryCopy(attempt)
+	if err != nil {
+		msg := fmt.Sprintf("cannot save callback attempt with ID [%s]", attempt.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	repository.attempts[attempt.ID] = value
+	return nil
+}
+
+func (repository *memoryProjectEndpointCallbackAttemptRepository) Index(ctx context.Context, userID entities.UserID, requestID string) ([]*entities.ProjectEndpointCallbackAttempt, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.RLock()
+	defer repository.lock.RUnlock()
+
+	attempts := make([]*entities.ProjectEndpointCallbackAttempt, 0)
+	for _, attempt := range repository.attempts {
+		if attempt.UserID == userID && attempt.ProjectEndpointRequestID == requestID {
+			attempts = append(attempts, attempt)
+		}
+	}
+
+	sort.Slice(attempts, func(i, j int) bool {
+		return attempts[i].CreatedAt.Before(attempts[j].CreatedAt)
+	})
+
+	result, err := memoryCopies(attempts)
+	if err != nil {
+		msg := fmt.Sprintf("cannot copy callback attempts for request with ID [%s]", requestID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return result, nil
+}
+
+func (repository *memoryProjectEndpointCallbackAttemptRepository) DeleteByRequest(ctx context.Context, userID entities.UserID, requestID string) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	for id, attempt := range repository.attempts {
+		if attempt.UserID == userID && attempt.ProjectEndpointRequestID == requestID {
+			delete(repository.attempts, id)
+		}
+	}
+
+	return nil
+}
+
+func (repository *memoryProjectEndpointCallbackAttemptRepository) DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	var count uint
+	for id, attempt := range repository.attempts {
+		if count == limit {
+			break
+		}
+		if attempt.UserID == userID && attempt.ProjectID == projectID {
+			delete(repository.attempts, id)
+			count++
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,287 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/matchers"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// memoryProjectEndpointRepository is responsible for persisting entities.ProjectEndpoint in memory
+type memoryProjectEndpointRepository struct {
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	lock      sync.RWMutex
+	endpoints map[uuid.UUID]*entities.ProjectEndpoint
+}
+
+// NewMemoryProjectEndpointRepository creates the in-memory version of the ProjectEndpointRepository
+func NewMemoryProjectEndpointRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) ProjectEndpointRepository {
+	return &memoryProjectEndpointRepository{
+		logger:    logger.WithCodeNamespace(fmt.Sprintf("%T", &memoryProjectEndpointRepository{})),
+		tracer:    tracer,
+		endpoints: make(map[uuid.UUID]*entities.ProjectEndpoint),
+	}
+}
+
+func (repository *memoryProjectEndpointRepository) Store(ctx context.Context, endpoint *entities.ProjectEndpoint) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.endpoints[endpoint.ID]; ok {
+		msg := fmt.Sprintf("project endpoint with ID [%s] already exists", endpoint.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+	}
+
+	if err := repository.save(endpoint); err != nil {
+		msg := fmt.Sprintf("cannot save project endpoint with ID [%s]", endpoint.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *memoryProjectEndpointRepository) Update(ctx context.Context, endpoint *entities.ProjectEndpoint) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if err := repository.save(endpoint); err != nil {
+		msg := fmt.Sprintf("cannot update project endpoint with ID [%s]", endpoint.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *memoryProjectEndpointRepository) IncreaseRequestCount(ctx context.Context, projectEndpointID uuid.UUID) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	endpoint, ok := repository.endpoints[projectEndpointID]
+	if !ok {
+		msg := fmt.Sprintf("cannot increase request_count [%T] with ID [%s] which does not exist", endpoint, projectEndpointID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	endpoint.RequestCount++
+	return nil
+}
+
+func (repository *memoryProjectEndpointRepository) IncreaseResponseCounter(ctx context.Context, projectEndpointID uuid.UUID) (uint, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	endpoint, ok := repository.endpoints[projectEndpointID]
+	if !ok {
+		msg := fmt.Sprintf("cannot increase response_counter [%T] with ID [%s] which does not exist", endpoint, projectEndpointID)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	endpoint.ResponseCounter++
+	return endpoint.ResponseCounter, nil
+}
+
+func (repository *memoryProjectEndpointRepository) DecreaseRequestCount(ctx context.Context, projectEndpointID uuid.UUID) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	endpoint, ok := repository.endpoints[projectEndpointID]
+	if !ok {
+		msg := fmt.Sprintf("cannot decrease request_count [%T] with ID [%s] which does not exist", endpoint, projectEndpointID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	if endpoint.RequestCount > 0 {
+		endpoint.RequestCount--
+	}
+	return nil
+}
+
+func (repository *memoryProjectEndpointRepository) UpdateSubdomain(ctx context.Context, subdomain string, projectID uuid.UUID) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	for _, endpoint := range repository.endpoints {
+		if endpoint.ProjectID == projectID {
+			endpoint.ProjectSubdomain = subdomain
+		}
+	}
+
+	return nil
+}
+
+func (repository *memoryProjectEndpointRepository) Fetch(ctx context.Context, userID entities.UserID, projectID uuid.UUID) ([]*entities.ProjectEndpoint, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	endpoints, err := repository.filter(func(endpoint *entities.ProjectEndpoint) bool {
+		return endpoint.UserID == userID && endpoint.ProjectID == projectID
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot load project endpoint for user with ID [%s] and project ID [%s]", userID, projectID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return endpoints, nil
+}
+
+func (repository *memoryProjectEndpointRepository) Load(ctx context.Context, userID entities.UserID, projectID uuid.UUID, projectEndpointID uuid.UUID) (*entities.ProjectEndpoint, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.RLock()
+	defer repository.lock.RUnlock()
+
+	endpoint, ok := repository.endpoints[projectEndpointID]
+	if !ok || endpoint.UserID != userID || endpoint.ProjectID != projectID {
+		msg := fmt.Sprintf("project endpoint with ID [%s] does not exist", projectEndpointID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	result, err := memoryCopy(endpoint)
+	if err != nil {
+		msg := fmt.Sprintf("cannot copy project endpoint with ID [%s]", projectEndpointID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return result, nil
+}
+
+func (repository *memoryProjectEndpointRepository) Delete(ctx context.Context, endpoint *entities.ProjectEndpoint) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.endpoints[endpoint.ID]; !ok {
+		msg := fmt.Sprintf("cannot delete [%T] with ID [%s] for user [%s] which does not exist", endpoint, endpoint.ID, endpoint.UserID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	delete(repository.endpoints, endpoint.ID)
+	return nil
+}
+
+func (repository *memoryProjectEndpointRepository) DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	var count uint
+	for id, endpoint := range repository.endpoints {
+		if count == limit {
+			break
+		}
+		if endpoint.UserID == userID && endpoint.ProjectID == projectID {
+			delete(repository.endpoints, id)
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (repository *memoryProjectEndpointRepository) LoadByRequest(ctx context.Context, subdomain string, request *matchers.Request) (*entities.ProjectEndpoint, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	method := strings.ToUpper(request.Method)
+	endpoints, err := repository.filter(func(endpoint *entities.ProjectEndpoint) bool {
+		return endpoint.ProjectSubdomain == subdomain && (endpoint.RequestMethod == method || endpoint.RequestMethod == "ANY")
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch endpoints with subdomain [%s] and request method [%s]", subdomain, request.Method)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	matches := matchers.MatchEndpoints(endpoints, request)
+	if len(matches) == 0 {
+		msg := fmt.Sprintf("endpoint not found with request method [%s] and request path [%s]", request.Method, request.Path)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	return matches[0].Endpoint, nil
+}
+
+func (repository *memoryProjectEndpointRepository) LoadConflicting(ctx context.Context, endpoint *entities.ProjectEndpoint) (*entities.ProjectEndpoint, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	endpoints, err := repository.filter(func(existing *entities.ProjectEndpoint) bool {
+		return existing.UserID == endpoint.UserID && existing.ProjectID == endpoint.ProjectID && existing.ID != endpoint.ID
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch endpoints with project ID [%s] and request method [%s]", endpoint.ProjectID, endpoint.RequestMethod)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	for _, existing := range endpoints {
+		if matchers.Conflicts(endpoint, existing) {
+			return existing, nil
+		}
+	}
+
+	msg := fmt.Sprintf("no conflicting endpoint with project ID [%s], request method [%s] and request path [%s]", endpoint.ProjectID, endpoint.RequestMethod, endpoint.RequestPath)
+	return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+}
+
+// filter returns copies of the entities.ProjectEndpoint which match the predicate ordered from the newest endpoint
+func (repository *memoryProjectEndpointRepository) filter(predicate func(endpoint *entities.ProjectEndpoint) bool) ([]*entities.ProjectEndpoint, error) {
+	repository.lock.RLock()
+	defer repository.lock.RUnlock()
+
+	endpoints := make([]*entities.ProjectEndpoint, 0)
+	for _, endpoint := range repository.endpoints {
+		if predicate(endpoint) {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		return endpoints[i].CreatedAt.After(endpoints[j].CreatedAt)
+	})
+
+	return memoryCopies(endpoints)
+}
+
+// save stores a copy of the entities.ProjectEndpoint. The caller must hold the write lock.
+func (repository *memoryProjectEndpointRepository) save(endpoint *entities.ProjectEndpoint) error {
+	value, err := memoryCopy(endpoint)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot copy project endpoint with ID [%s]", endpoint.ID))
+	}
+
+	repository.endpoints[endpoint.ID] = value
+	return nil
+}
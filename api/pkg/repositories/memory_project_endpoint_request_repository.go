@@ -0,0 +1,359 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/oklog/ulid/v2"
+	"github.com/palantir/stacktrace"
+)
+
+// memoryProjectEndpointRequestRepository is responsible for persisting entities.ProjectEndpointRequest in memory
+type memoryProjectEndpointRequestRepository struct {
+	logger   telemetry.Logger
+	tracer   telemetry.Tracer
+	lock     sync.RWMutex
+	requests map[string]*entities.ProjectEndpointRequest
+}
+
+// NewMemoryProjectEndpointRequestRepository creates the in-memory version of the ProjectEndpointRequestRepository
+func NewMemoryProjectEndpointRequestRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) ProjectEndpointRequestRepository {
+	return &memoryProjectEndpointRequestRepository{
+		logger:   logger.WithCodeNamespace(fmt.Sprintf("%T", &memoryProjectEndpointRequestRepository{})),
+		tracer:   tracer,
+		requests: make(map[string]*entities.ProjectEndpointRequest),
+	}
+}
+
+func (repository *memoryProjectEndpointRequestRepository) Store(ctx context.Context, request *entities.ProjectEndpointRequest) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.requests[request.ID]; ok {
+		msg := fmt.Sprintf("project endpoint request with ID [%s] already exists", request.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+	}
+
+	value, err := memoryCopy(request)
+	if err != nil {
+		msg := fmt.Sprintf("cannot save project endpoint request with ID [%s]", request.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	repository.requests[request.ID] = value
+	return nil
+}
+
+func (repository *memoryProjectEndpointRequestRepository) Delete(ctx context.Context, request *entities.ProjectEndpointRequest) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.requests[request.ID]; !ok {
+		msg := fmt.Sprintf("cannot delete [%T] with ID [%s] for user [%s] which does not exist", request, request.ID, request.UserID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	delete(repository.requests, request.ID)
+	return nil
+}
+
+func (repository *memoryProjectEndpointRequestRepository) DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	var count uint
+	for id, request := range repository.requests {
+		if count == limit {
+			break
+		}
+		if request.UserID == userID && request.ProjectID == projectID {
+			delete(repository.requests, id)
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (repository *memoryProjectEndpointRequestRepository) DeleteBefore(ctx context.Context, userID entities.UserID, before time.Time, limit uint) ([]*entities.ProjectEndpointRequest, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	beforeID := requestIDBefore(before)
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	requests := make([]*entities.ProjectEndpointRequest, 0)
+	for id, request := range repository.requests {
+		if uint(len(requests)) == limit {
+			break
+		}
+		if request.UserID == userID && request.ID < beforeID {
+			delete(repository.requests, id)
+			requests = append(requests, request)
+		}
+	}
+
+	return requests, nil
+}
+
+func (repository *memoryProjectEndpointRequestRepository) Load(ctx context.Context, userID entities.UserID, requestID ulid.ULID) (*entities.ProjectEndpointRequest, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.RLock()
+	defer repository.lock.RUnlock()
+
+	request, ok := repository.requests[requestID.String()]
+	if !ok || request.UserID != userID {
+		msg := fmt.Sprintf("request with ID [%s] for userID [%s] does not exist", requestID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	result, err := memoryCopy(request)
+	if err != nil {
+		msg := fmt.Sprintf("cannot copy project endpoint request with ID [%s]", requestID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return result, nil
+}
+
+func (repository *memoryProjectEndpointRequestRepository) GetProjectTraffic(ctx context.Context, userID entities.UserID, projectID uuid.UUID) ([]*TimeSeriesData, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	return repository.traffic(func(request *entities.ProjectEndpointRequest) bool {
+		return request.UserID == userID && request.ProjectID == projectID
+	}), nil
+}
+
+func (repository *memoryProjectEndpointRequestRepository) GetEndpointTraffic(ctx context.Context, userID entities.UserID, endpointID uuid.UUID) ([]*TimeSeriesData, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	return repository.traffic(func(request *entities.ProjectEndpointRequest) bool {
+		return request.UserID == userID && request.ProjectEndpointID == endpointID
+	}), nil
+}
+
+func (repository *memoryProjectEndpointRequestRepository) FetchLatest(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) ([]*entities.ProjectEndpointRequest, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	requests, err := repository.filter(limit, true, func(request *entities.ProjectEndpointRequest) bool {
+		return request.UserID == userID && request.ProjectID == projectID
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot load the latest [%d] requests for user with ID [%s] and project ID [%s]", limit, userID, projectID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return requests, nil
+}
+
+func (repository *memoryProjectEndpointRequestRepository) Index(ctx context.Context, userID entities.UserID, endpointID uuid.UUID, limit uint, previousID *ulid.ULID, nextID *ulid.ULID) ([]*entities.ProjectEndpointRequest, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	descending := nextID == nil || previousID != nil
+	requests, err := repository.filter(limit, descending, func(request *entities.ProjectEndpointRequest) bool {
+		if request.UserID != userID || request.ProjectEndpointID != endpointID {
+			return false
+		}
+		if previousID != nil {
+			return request.ID < previousID.String()
+		}
+		if nextID != nil {
+			return request.ID > nextID.String()
+		}
+		return true
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot load project endpoint requests for user with ID [%s] and endpoint ID [%s]", userID, endpointID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return requests, nil
+}
+
+func (repository *memoryProjectEndpointRequestRepository) Search(ctx context.Context, userID entities.UserID, params *ProjectEndpointRequestSearchParams) ([]*entities.ProjectEndpointRequest, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	var pathPrefix *regexp.Regexp
+	if params.PathPrefix != "" {
+		pathPrefix = regexp.MustCompile(params.pathPrefixPattern())
+	}
+
+	descending := params.NextID == nil || params.PreviousID != nil
+	requests, err := repository.filter(params.Limit, descending, func(request *entities.ProjectEndpointRequest) bool {
+		if request.UserID != userID || request.ProjectID != params.ProjectID {
+			return false
+		}
+		if params.PreviousID != nil && request.ID >= params.PreviousID.String() {
+			return false
+		}
+		if params.PreviousID == nil && params.NextID != nil && request.ID <= params.NextID.String() {
+			return false
+		}
+		if params.From != nil && request.ID < requestIDBefore(*params.From) {
+			return false
+		}
+		if params.To != nil && request.ID >= requestIDBefore(*params.To) {
+			return false
+		}
+		if params.Unmatched != nil && request.Unmatched != *params.Unmatched {
+			return false
+		}
+		if params.Method != "" && request.RequestMethod != params.Method {
+			return false
+		}
+		if params.ResponseCode != nil && request.ResponseCode != *params.ResponseCode {
+			return false
+		}
+		if params.IPAddress != "" && request.RequestIPAddress != params.IPAddress {
+			return false
+		}
+		if pathPrefix != nil && !pathPrefix.MatchString(request.RequestURL) {
+			return false
+		}
+		if params.HeaderKey != "" && !repository.hasHeader(request, params.HeaderKey, params.HeaderValue) {
+			return false
+		}
+		if params.Body != "" && (request.RequestBody == nil || !strings.Contains(strings.ToLower(*request.RequestBody), strings.ToLower(params.Body))) {
+			return false
+		}
+		if params.JSONPath != nil && !repository.hasJSONPath(request, params) {
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot search project endpoint requests for user with ID [%s] and project ID [%s]", userID, params.ProjectID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return requests, nil
+}
+
+// hasHeader checks if an entities.ProjectEndpointRequest has a header with the key and optionally the value
+func (repository *memoryProjectEndpointRequestRepository) hasHeader(request *entities.ProjectEndpointRequest, key string, value *string) bool {
+	if request.RequestHeaders == nil {
+		return false
+	}
+
+	var headers []map[string]string
+	if err := json.Unmarshal([]byte(*request.RequestHeaders), &headers); err != nil {
+		return false
+	}
+
+	for _, header := range headers {
+		for headerKey, headerValue := range header {
+			if strings.EqualFold(headerKey, key) && (value == nil || headerValue == *value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// hasJSONPath checks if the JSONPath of the ProjectEndpointRequestSearchParams selects a value in the body of an entities.ProjectEndpointRequest
+func (repository *memoryProjectEndpointRequestRepository) hasJSONPath(request *entities.ProjectEndpointRequest, params *ProjectEndpointRequestSearchParams) bool {
+	if request.RequestBody == nil {
+		return false
+	}
+
+	var document interface{}
+	if err := json.Unmarshal([]byte(*request.RequestBody), &document); err != nil {
+		return false
+	}
+
+	for _, value := range params.JSONPath.FindStrings(document) {
+		if params.JSONValue == nil || value == *params.JSONValue {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filter returns copies of at most limit entities.ProjectEndpointRequest which match the predicate ordered by ID
+func (repository *memoryProjectEndpointRequestRepository) filter(limit uint, descending bool, predicate func(request *entities.ProjectEndpointRequest) bool) ([]*entities.ProjectEndpointRequest, error) {
+	repository.lock.RLock()
+	defer repository.lock.RUnlock()
+
+	requests := make([]*entities.ProjectEndpointRequest, 0)
+	for _, request := range repository.requests {
+		if predicate(request) {
+			requests = append(requests, request)
+		}
+	}
+
+	sort.Slice(requests, func(i, j int) bool {
+		if descending {
+			return requests[i].ID > requests[j].ID
+		}
+		return requests[i].ID < requests[j].ID
+	})
+
+	if uint(len(requests)) > limit {
+		requests = requests[:limit]
+	}
+
+	return memoryCopies(requests)
+}
+
+// traffic counts the entities.ProjectEndpointRequest which match the predicate per day in the last 30 days
+func (repository *memoryProjectEndpointRequestRepository) traffic(predicate func(request *entities.ProjectEndpointRequest) bool) []*TimeSeriesData {
+	repository.lock.RLock()
+	defer repository.lock.RUnlock()
+
+	thirtyDaysAgo := time.Now().UTC().AddDate(0, 0, -30)
+
+	counts := make(map[string]*TimeSeriesData)
+	for _, request := range repository.requests {
+		if !predicate(request) || request.CreatedAt.Before(thirtyDaysAgo) {
+			continue
+		}
+
+		createdAt := request.CreatedAt.UTC()
+		day := createdAt.Format("2006-01-02")
+		if _, ok := counts[day]; !ok {
+			counts[day] = &TimeSeriesData{
+				Timestamp: time.Date(createdAt.Year(), createdAt.Month(), createdAt.Day(), 0, 0, 0, 0, time.UTC),
+			}
+		}
+		counts[day].Count++
+	}
+
+	data := make([]*TimeSeriesData, 0, len(counts))
+	for _, point := range counts {
+		data = append(data, point)
+	}
+
+	return normalizeTimeSeries(data)
+}
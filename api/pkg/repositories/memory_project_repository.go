@@ -0,0 +1,207 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// memoryProjectRepository is responsible for persisting entities.Project in memory
+type memoryProjectRepository struct {
+	logger   telemetry.Logger
+	tracer   telemetry.Tracer
+	lock     sync.RWMutex
+	projects map[uuid.UUID]*entities.Project
+}
+
+// NewMemoryProjectRepository creates the in-memory version of the ProjectRepository
+func NewMemoryProjectRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) ProjectRepository {
+	return &memoryProjectRepository{
+		logger:   logger.WithCodeNamespace(fmt.Sprintf("%T", &memoryProjectRepository{})),
+		tracer:   tracer,
+		projects: make(map[uuid.UUID]*entities.Project),
+	}
+}
+
+func (repository *memoryProjectRepository) Store(ctx context.Context, project *entities.Project) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.projects[project.ID]; ok {
+		msg := fmt.Sprintf("project with ID [%s] already exists", project.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+	}
+
+	if err := repository.save(project); err != nil {
+		msg := fmt.Sprintf("cannot save project with ID [%s]", project.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *memoryProjectRepository) Update(ctx context.Context, project *entities.Project) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if err := repository.save(project); err != nil {
+		msg := fmt.Sprintf("cannot update project with ID [%s]", project.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *memoryProjectRepository) Fetch(ctx context.Context, userID entities.UserID) ([]*entities.Project, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.RLock()
+	defer repository.lock.RUnlock()
+
+	projects := make([]*entities.Project, 0)
+	for _, project := range repository.projects {
+		if project.UserID == userID {
+			projects = append(projects, project)
+		}
+	}
+
+	sort.SliceStable(projects, func(i, j int) bool {
+		return projects[i].CreatedAt.After(projects[j].CreatedAt)
+	})
+
+	result, err := memoryCopies(projects)
+	if err != nil {
+		msg := fmt.Sprintf("cannot copy projects for user with ID [%s]", userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return result, nil
+}
+
+func (repository *memoryProjectRepository) Load(ctx context.Context, userID entities.UserID, projectID uuid.UUID) (*entities.Project, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.RLock()
+	defer repository.lock.RUnlock()
+
+	project, ok := repository.projects[projectID]
+	if !ok || project.UserID != userID {
+		msg := fmt.Sprintf("project with ID [%s] does not exist", projectID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	result, err := memoryCopy(project)
+	if err != nil {
+		msg := fmt.Sprintf("cannot copy project with ID [%s]", projectID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return result, nil
+}
+
+func (repository *memoryProjectRepository) Delete(ctx context.Context, userID entities.UserID, projectID uuid.UUID) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	project, ok := repository.projects[projectID]
+	if !ok || project.UserID != userID {
+		msg := fmt.Sprintf("project with ID [%s] does not exist", projectID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	delete(repository.projects, projectID)
+	return nil
+}
+
+func (repository *memoryProjectRepository) UpdateScenarioState(ctx context.Context, projectID uuid.UUID, scenario string, state string) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	project, ok := repository.projects[projectID]
+	if !ok {
+		msg := fmt.Sprintf("cannot update scenario [%s] to state [%s] for project with ID [%s] which does not exist", scenario, state, projectID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	if project.ScenarioStates == nil {
+		project.ScenarioStates = make(map[string]string)
+	}
+	project.ScenarioStates[scenario] = state
+
+	return nil
+}
+
+func (repository *memoryProjectRepository) ResetScenarioStates(ctx context.Context, projectID uuid.UUID) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	project, ok := repository.projects[projectID]
+	if !ok {
+		msg := fmt.Sprintf("cannot reset scenario states for project with ID [%s] which does not exist", projectID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	project.ScenarioStates = map[string]string{}
+	return nil
+}
+
+func (repository *memoryProjectRepository) LoadWithSubdomain(ctx context.Context, subdomain string) (*entities.Project, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.RLock()
+	defer repository.lock.RUnlock()
+
+	for _, project := range repository.projects {
+		if project.Subdomain != subdomain {
+			continue
+		}
+
+		result, err := memoryCopy(project)
+		if err != nil {
+			msg := fmt.Sprintf("cannot copy project with subdomain [%s]", subdomain)
+			return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		return result, nil
+	}
+
+	msg := fmt.Sprintf("project not found with subdomain [%s]", subdomain)
+	return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+}
+
+// save stores a copy of the entities.Project. The caller must hold the write lock.
+func (repository *memoryProjectRepository) save(project *entities.Project) error {
+	value, err := memoryCopy(project)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot copy project with ID [%s]", project.ID))
+	}
+
+	repository.projects[project.ID] = value
+	return nil
+}
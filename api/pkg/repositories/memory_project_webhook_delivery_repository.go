@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// memoryProjectWebhookDeliveryRepository is responsible for persisting entities.ProjectWebhookDelivery in memory
+type memoryProjectWebhookDeliveryRepository struct {
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	lock       sync.RWMutex
+	deliveries map[uuid.UUID]*entities.ProjectWebhookDelivery
+}
+
+// NewMemoryProjectWebhookDeliveryRepository creates the in-memory version of the ProjectWebhookDeliveryRepository
+func NewMemoryProjectWebhookDeliveryRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) ProjectWebhookDeliveryRepository {
+	return &memoryProjectWebhookDeliveryRepository{
+		logger:     logger.WithCodeNamespace(fmt.Sprintf("%T", &memoryProjectWebhookDeliveryRepository{})),
+		tracer:     tracer,
+		deliveries: make(map[uuid.UUID]*entities.ProjectWebhookDelivery),
+	}
+}
+
+func (repository *memoryProjectWebhookDeliveryRepository) Store(ctx context.Context, delivery *entities.ProjectWebhookDelivery) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.deliveries[delivery.ID]; ok {
+		msg := fmt.Sprintf("webhook delivery with ID [%s] already exists", delivery.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+	}
+
+	value, err := memoryCopy(delivery)
+	if err != nil {
+		msg := fmt.Sprintf("cannot save webhook delivery with ID [%s]", delivery.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	repository.deliveries[delivery.ID] = value
+	return nil
+}
+
+func (repository *memoryProjectWebhookDeliveryRepository) Index(ctx context.Context, userID entities.UserID, webhookID uuid.UUID, limit uint) ([]*entities.ProjectWebhookDelivery, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.RLock()
+	defer repository.lock.RUnlock()
+
+	deliveries := make([]*entities.ProjectWebhookDelivery, 0)
+	for _, delivery := range repository.deliveries {
+		if delivery.UserID == userID && delivery.ProjectWebhookID == webhookID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+
+	if uint(len(deliveries)) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	result, err := memoryCopies(deliveries)
+	if err != nil {
+		msg := fmt.Sprintf("cannot copy deliveries for webhook with ID [%s]", webhookID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return result, nil
+}
+
+func (repository *memoryProjectWebhookDeliveryRepository) DeleteByWebhook(ctx context.Context, userID entities.UserID, webhookID uuid.UUID) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	for id, delivery := range repository.deliveries {
+		if delivery.UserID == userID && delivery.ProjectWebhookID == webhookID {
+			delete(repository.deliveries, id)
+		}
+	}
+
+	return nil
+}
+
+func (repository *memoryProjectWebhookDeliveryRepository) DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	var count uint
+	for id, delivery := range repository.deliveries {
+		if count == limit {
+			break
+		}
+		if delivery.UserID == userID && delivery.ProjectID == projectID {
+			delete(repository.deliveries, id)
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (repository *memoryProjectWebhookDeliveryRepository) DeleteBefore(ctx context.Context, userID entities.UserID, before time.Time, limit uint) (uint, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	var count uint
+	for id, delivery := range repository.deliveries {
+		if count == limit {
+			break
+		}
+		if delivery.UserID == userID && delivery.CreatedAt.Before(before) {
+			delete(repository.deliveries, id)
+			count++
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// memoryProjectWebhookRepository is responsible for persisting entities.ProjectWebhook in memory
+type memoryProjectWebhookRepository struct {
+	logger   telemetry.Logger
+	tracer   telemetry.Tracer
+	lock     sync.RWMutex
+	webhooks map[uuid.UUID]*entities.ProjectWebhook
+}
+
+// NewMemoryProjectWebhookRepository creates the in-memory version of the ProjectWebhookRepository
+func NewMemoryProjectWebhookRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) ProjectWebhookRepository {
+	return &memoryProjectWebhookRepository{
+		logger:   logger.WithCodeNamespace(fmt.Sprintf("%T", &memoryProjectWebhookRepository{})),
+		tracer:   tracer,
+		webhooks: make(map[uuid.UUID]*entities.ProjectWebhook),
+	}
+}
+
+func (repository *memoryProjectWebhookRepository) Store(ctx context.Context, webhook *entities.ProjectWebhook) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.webhooks[webhook.ID]; ok {
+		msg := fmt.Sprintf("webhook with ID [%s] already exists", webhook.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+	}
+
+	if err := repository.save(webhook); err != nil {
+		msg := fmt.Sprintf("cannot save webhook with ID [%s]", webhook.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *memoryProjectWebhookRepository) Update(ctx context.Context, webhook *entities.ProjectWebhook) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if err := repository.save(webhook); err != nil {
+		msg := fmt.Sprintf("cannot update webhook with ID [%s]", webhook.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *memoryProjectWebhookRepository) Index(ctx context.Context, userID entities.UserID, projectID uuid.UUID) ([]*entities.ProjectWebhook, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.RLock()
+	defer repository.lock.RUnlock()
+
+	webhooks := make([]*entities.ProjectWebhook, 0)
+	for _, webhook := range repository.webhooks {
+		if webhook.UserID == userID && webhook.ProjectID == projectID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+
+	sort.SliceStable(webhooks, func(i, j int) bool {
+		return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt)
+	})
+
+	result, err := memoryCopies(webhooks)
+	if err != nil {
+		msg := fmt.Sprintf("cannot copy webhooks for project with ID [%s]", projectID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return result, nil
+}
+
+func (repository *memoryProjectWebhookRepository) Load(ctx context.Context, userID entities.UserID, projectID uuid.UUID, webhookID uuid.UUID) (*entities.ProjectWebhook, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.RLock()
+	defer repository.lock.RUnlock()
+
+	webhook, ok := repository.webhooks[webhookID]
+	if !ok || webhook.UserID != userID || webhook.ProjectID != projectID {
+		msg := fmt.Sprintf("webhook with ID [%s] does not exist", webhookID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	result, err := memoryCopy(webhook)
+	if err != nil {
+		msg := fmt.Sprintf("cannot copy webhook with ID [%s]", webhookID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return result, nil
+}
+
+func (repository *memoryProjectWebhookRepository) Delete(ctx context.Context, userID entities.UserID, projectID uuid.UUID, webhookID uuid.UUID) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	webhook, ok := repository.webhooks[webhookID]
+	if !ok || webhook.UserID != userID || webhook.ProjectID != projectID {
+		msg := fmt.Sprintf("webhook with ID [%s] does not exist", webhookID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	delete(repository.webhooks, webhookID)
+	return nil
+}
+
+func (repository *memoryProjectWebhookRepository) DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	var count uint
+	for id, webhook := range repository.webhooks {
+		if count == limit {
+			break
+		}
+		if webhook.UserID == userID && webhook.ProjectID == projectID {
+			delete(repository.webhooks, id)
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// save stores a copy of the entities.ProjectWebhook. The caller must hold the write lock.
+func (repository *memoryProjectWebhookRepository) save(webhook *entities.ProjectWebhook) error {
+	value, err := memoryCopy(webhook)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot copy webhook with ID [%s]", webhook.ID))
+	}
+
+	repository.webhooks[webhook.ID] = value
+	return nil
+}
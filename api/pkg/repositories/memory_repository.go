@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/palantir/stacktrace"
+)
+
+// memoryCopy copies an entity through JSON just like a database would so that callers cannot mutate the stored value
+func memoryCopy[T any](value *T) (*T, error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot encode [%T] as JSON", value))
+	}
+
+	result := new(T)
+	if err = json.Unmarshal(content, result); err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot decode JSON into [%T]", result))
+	}
+
+	return result, nil
+}
+
+// memoryCopies copies a list of entities with memoryCopy
+func memoryCopies[T any](values []*T) ([]*T, error) {
+	result := make([]*T, 0, len(values))
+	for _, value := range values {
+		item, err := memoryCopy(value)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot copy [%T]", value))
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+// memoryUserRepository is responsible for persisting entities.User in memory
+type memoryUserRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	lock   sync.RWMutex
+	users  map[entities.UserID]*entities.User
+}
+
+// NewMemoryUserRepository creates the in-memory version of the UserRepository
+func NewMemoryUserRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) UserRepository {
+	return &memoryUserRepository{
+		logger: logger.WithCodeNamespace(fmt.Sprintf("%T", &memoryUserRepository{})),
+		tracer: tracer,
+		users:  make(map[entities.UserID]*entities.User),
+	}
+}
+
+func (repository *memoryUserRepository) Store(ctx context.Context, user *entities.User) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.users[user.ID]; ok {
+		msg := fmt.Sprintf("user with ID [%s] already exists", user.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+	}
+
+	if err := repository.save(user); err != nil {
+		msg := fmt.Sprintf("cannot save user with ID [%s]", user.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *memoryUserRepository) Update(ctx context.Context, user *entities.User) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if err := repository.save(user); err != nil {
+		msg := fmt.Sprintf("cannot update user with ID [%s]", user.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *memoryUserRepository) Load(ctx context.Context, userID entities.UserID) (*entities.User, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.RLock()
+	defer repository.lock.RUnlock()
+
+	user, ok := repository.users[userID]
+	if !ok {
+		msg := fmt.Sprintf("user with ID [%s] does not exist", userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	result, err := memoryCopy(user)
+	if err != nil {
+		msg := fmt.Sprintf("cannot copy user with ID [%s]", userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return result, nil
+}
+
+func (repository *memoryUserRepository) LoadBySubscriptionID(ctx context.Context, subscriptionID string) (*entities.User, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.RLock()
+	defer repository.lock.RUnlock()
+
+	for _, user := range repository.users {
+		if user.SubscriptionID != subscriptionID {
+			continue
+		}
+
+		result, err := memoryCopy(user)
+		if err != nil {
+			msg := fmt.Sprintf("cannot copy user with subscriptionID [%s]", subscriptionID)
+			return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		return result, nil
+	}
+
+	msg := fmt.Sprintf("user with subscriptionID [%s] does not exist", subscriptionID)
+	return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+}
+
+func (repository *memoryUserRepository) LoadOrStore(ctx context.Context, authUser entities.AuthUser) (*entities.User, bool, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	user, ok := repository.users[authUser.ID]
+	isNew := !ok
+	if isNew {
+		user = &entities.User{
+			ID:               authUser.ID,
+			Email:            authUser.Email,
+			SubscriptionName: entities.SubscriptionNameFree,
+			FirstName:        authUser.FirstName,
+			LastName:         authUser.LastName,
+			CreatedAt:        time.Now().UTC(),
+			UpdatedAt:        time.Now().UTC(),
+		}
+		if err := repository.save(user); err != nil {
+			msg := fmt.Sprintf("cannot load or create user from auth user [%+#v]", authUser)
+			return nil, false, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+	}
+
+	result, err := memoryCopy(user)
+	if err != nil {
+		msg := fmt.Sprintf("cannot copy user with ID [%s]", authUser.ID)
+		return nil, false, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return result, isNew, nil
+}
+
+// save stores a copy of the entities.User. The caller must hold the write lock.
+func (repository *memoryUserRepository) save(user *entities.User) error {
+	value, err := memoryCopy(user)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot copy user with ID [%s]", user.ID))
+	}
+
+	repository.users[user.ID] = value
+	return nil
+}
+
+func (repository *memoryUserRepository) Delete(ctx context.Context, userID entities.UserID) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	if _, ok := repository.users[userID]; !ok {
+		msg := fmt.Sprintf("user with ID [%s] does not exist", userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	delete(repository.users, userID)
+	return nil
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+// memoryUserUsageRepository is responsible for persisting entities.UserUsage in memory
+type memoryUserUsageRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	lock   sync.Mutex
+	usages map[string]*entities.UserUsage
+}
+
+// NewMemoryUserUsageRepository creates the in-memory version of the UserUsageRepository
+func NewMemoryUserUsageRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) UserUsageRepository {
+	return &memoryUserUsageRepository{
+		logger: logger.WithCodeNamespace(fmt.Sprintf("%T", &memoryUserUsageRepository{})),
+		tracer: tracer,
+		usages: make(map[string]*entities.UserUsage),
+	}
+}
+
+func (repository *memoryUserUsageRepository) IncrementRequestCount(ctx context.Context, userID entities.UserID, periodStart time.Time) (uint, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	id := entities.UserUsageID(userID, periodStart)
+	usage, ok := repository.usages[id]
+	if !ok {
+		usage = &entities.UserUsage{UserID: userID, PeriodStart: periodStart.UTC()}
+		repository.usages[id] = usage
+	}
+
+	usage.RequestCount++
+	usage.UpdatedAt = time.Now().UTC()
+
+	return usage.RequestCount, nil
+}
+
+func (repository *memoryUserUsageRepository) Load(ctx context.Context, userID entities.UserID, periodStart time.Time) (*entities.UserUsage, error) {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	usage, ok := repository.usages[entities.UserUsageID(userID, periodStart)]
+	if !ok {
+		msg := fmt.Sprintf("usage of user with ID [%s] does not exist for period [%s]", userID, periodStart)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	result, err := memoryCopy(usage)
+	if err != nil {
+		msg := fmt.Sprintf("cannot copy usage of user with ID [%s] for period [%s]", userID, periodStart)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return result, nil
+}
+
+func (repository *memoryUserUsageRepository) DeleteByUser(ctx context.Context, userID entities.UserID) error {
+	_, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+
+	for id, usage := range repository.usages {
+		if usage.UserID == userID {
+			delete(repository.usages, id)
+		}
+	}
+
+	return nil
+}
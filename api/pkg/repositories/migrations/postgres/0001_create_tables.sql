@@ -0,0 +1,85 @@
+CREATE TABLE IF NOT EXISTS users (
+    id                     TEXT PRIMARY KEY,
+    email                  TEXT        NOT NULL,
+    first_name             TEXT,
+    last_name              TEXT,
+    subscription_name      TEXT        NOT NULL,
+    subscription_id        TEXT        NOT NULL DEFAULT '',
+    subscription_status    TEXT        NOT NULL DEFAULT '',
+    subscription_renews_at TIMESTAMPTZ,
+    subscription_ends_at   TIMESTAMPTZ,
+    created_at             TIMESTAMPTZ NOT NULL,
+    updated_at             TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_subscription_id ON users (subscription_id);
+
+CREATE TABLE IF NOT EXISTS projects (
+    id                UUID PRIMARY KEY,
+    user_id           TEXT        NOT NULL,
+    subdomain         TEXT        NOT NULL UNIQUE,
+    name              TEXT        NOT NULL,
+    description       TEXT        NOT NULL DEFAULT '',
+    upstream_url      TEXT        NOT NULL DEFAULT '',
+    recording_enabled BOOLEAN     NOT NULL DEFAULT FALSE,
+    scenario_states   JSONB,
+    created_at        TIMESTAMPTZ NOT NULL,
+    updated_at        TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_projects_user_id ON projects (user_id);
+
+CREATE TABLE IF NOT EXISTS project_endpoints (
+    id                             UUID PRIMARY KEY,
+    project_id                     UUID        NOT NULL,
+    project_subdomain              TEXT        NOT NULL,
+    user_id                        TEXT        NOT NULL,
+    request_method                 TEXT        NOT NULL,
+    request_path                   TEXT        NOT NULL,
+    request_conditions             JSONB,
+    scenario_name                  TEXT        NOT NULL DEFAULT '',
+    scenario_required_state        TEXT        NOT NULL DEFAULT '',
+    scenario_new_state             TEXT        NOT NULL DEFAULT '',
+    priority                       BIGINT      NOT NULL DEFAULT 0,
+    response_code                  BIGINT      NOT NULL,
+    response_body                  TEXT,
+    response_headers               TEXT,
+    responses                      JSONB,
+    response_mode                  TEXT        NOT NULL DEFAULT '',
+    response_counter               BIGINT      NOT NULL DEFAULT 0,
+    response_template_enabled      BOOLEAN     NOT NULL DEFAULT FALSE,
+    response_delay_in_milliseconds BIGINT      NOT NULL DEFAULT 0,
+    faults                         JSONB,
+    description                    TEXT,
+    request_count                  BIGINT      NOT NULL DEFAULT 0,
+    created_at                     TIMESTAMPTZ NOT NULL,
+    updated_at                     TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_endpoints_user_project ON project_endpoints (user_id, project_id);
+CREATE INDEX IF NOT EXISTS idx_endpoints_subdomain_request ON project_endpoints (project_subdomain, request_method, request_path);
+CREATE INDEX IF NOT EXISTS idx_endpoints_project_id ON project_endpoints (project_id);
+
+CREATE TABLE IF NOT EXISTS project_endpoint_requests (
+    id                             TEXT PRIMARY KEY,
+    project_id                     UUID        NOT NULL,
+    project_endpoint_id            UUID        NOT NULL,
+    user_id                        TEXT        NOT NULL,
+    request_method                 TEXT        NOT NULL,
+    request_url                    TEXT        NOT NULL,
+    request_headers                TEXT,
+    request_body                   TEXT,
+    request_ip_address             TEXT        NOT NULL DEFAULT '',
+    response_code                  BIGINT      NOT NULL,
+    response_body                  TEXT,
+    response_headers               TEXT,
+    response_delay_in_milliseconds BIGINT      NOT NULL DEFAULT 0,
+    passthrough                    BOOLEAN     NOT NULL DEFAULT FALSE,
+    response_fault                 TEXT,
+    response_index                 BIGINT,
+    created_at                     TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_requests_user_endpoint ON project_endpoint_requests (user_id, project_endpoint_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_requests_user_project_created ON project_endpoint_requests (user_id, project_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_requests_user_endpoint_created ON project_endpoint_requests (user_id, project_endpoint_id, created_at);
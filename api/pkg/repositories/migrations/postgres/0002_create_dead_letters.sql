@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS dead_letters (
+    id          UUID PRIMARY KEY,
+    event_id    TEXT        NOT NULL,
+    event_type  TEXT        NOT NULL,
+    event       JSONB       NOT NULL,
+    listener    TEXT        NOT NULL,
+    status      TEXT        NOT NULL,
+    error       TEXT        NOT NULL DEFAULT '',
+    attempts    BIGINT      NOT NULL DEFAULT 0,
+    replayed_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_dead_letters_status_created ON dead_letters (status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_dead_letters_created ON dead_letters (created_at DESC);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS user_usages (
+    user_id       TEXT        NOT NULL,
+    period_start  TIMESTAMPTZ NOT NULL,
+    request_count BIGINT      NOT NULL DEFAULT 0,
+    updated_at    TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, period_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_requests_user_id ON project_endpoint_requests (user_id, id);
@@ -0,0 +1,24 @@
+ALTER TABLE project_endpoints ADD COLUMN IF NOT EXISTS callbacks JSONB;
+
+CREATE TABLE IF NOT EXISTS project_endpoint_callback_attempts (
+    id                          UUID PRIMARY KEY,
+    project_id                  UUID        NOT NULL,
+    project_endpoint_id         UUID        NOT NULL,
+    project_endpoint_request_id TEXT        NOT NULL,
+    user_id                     TEXT        NOT NULL,
+    callback_index              BIGINT      NOT NULL DEFAULT 0,
+    attempt                     BIGINT      NOT NULL DEFAULT 1,
+    request_url                 TEXT        NOT NULL,
+    request_method              TEXT        NOT NULL,
+    request_headers             TEXT,
+    request_body                TEXT,
+    response_code               BIGINT,
+    response_body               TEXT,
+    error                       TEXT,
+    succeeded                   BOOLEAN     NOT NULL DEFAULT FALSE,
+    duration_in_milliseconds    BIGINT      NOT NULL DEFAULT 0,
+    created_at                  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_callback_attempts_user_request ON project_endpoint_callback_attempts (user_id, project_endpoint_request_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_callback_attempts_user_project ON project_endpoint_callback_attempts (user_id, project_id);
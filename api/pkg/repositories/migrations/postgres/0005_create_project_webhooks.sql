@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS project_webhooks (
+    id             UUID PRIMARY KEY,
+    project_id     UUID        NOT NULL,
+    user_id        TEXT        NOT NULL,
+    url            TEXT        NOT NULL,
+    mode           TEXT        NOT NULL,
+    signing_secret TEXT        NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL,
+    updated_at     TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_user_project ON project_webhooks (user_id, project_id, created_at);
+
+CREATE TABLE IF NOT EXISTS project_webhook_deliveries (
+    id                       UUID PRIMARY KEY,
+    project_webhook_id       UUID        NOT NULL,
+    project_id               UUID        NOT NULL,
+    user_id                  TEXT        NOT NULL,
+    event_id                 TEXT        NOT NULL,
+    event_type               TEXT        NOT NULL,
+    attempt                  BIGINT      NOT NULL DEFAULT 1,
+    request_url              TEXT        NOT NULL,
+    response_code            BIGINT,
+    response_body            TEXT,
+    error                    TEXT,
+    succeeded                BOOLEAN     NOT NULL DEFAULT FALSE,
+    duration_in_milliseconds BIGINT      NOT NULL DEFAULT 0,
+    created_at               TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_user_webhook ON project_webhook_deliveries (user_id, project_webhook_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_user_project ON project_webhook_deliveries (user_id, project_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_user_created ON project_webhook_deliveries (user_id, created_at);
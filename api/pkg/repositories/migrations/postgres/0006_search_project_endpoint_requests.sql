@@ -0,0 +1,11 @@
+-- httpmock_try_jsonb parses a request body or headers as JSONB and returns NULL when the text is not valid JSON
+CREATE OR REPLACE FUNCTION httpmock_try_jsonb(value TEXT) RETURNS JSONB AS $$
+BEGIN
+    RETURN value::jsonb;
+EXCEPTION
+    WHEN others THEN
+        RETURN NULL;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+CREATE INDEX IF NOT EXISTS idx_requests_user_project_id ON project_endpoint_requests (user_id, project_id, id DESC);
@@ -0,0 +1 @@
+ALTER TABLE project_endpoint_requests ADD COLUMN IF NOT EXISTS unmatched BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+ALTER TABLE dead_letters ADD COLUMN IF NOT EXISTS user_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_dead_letters_user ON dead_letters (user_id);
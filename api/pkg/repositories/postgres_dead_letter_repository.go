@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/palantir/stacktrace"
+)
+
+const postgresDeadLetterColumns = "id, event_id, event_type, user_id, event, listener, status, error, attempts, replayed_at, created_at, updated_at"
+
+// postgresDeadLetterRepository is responsible for persisting entities.DeadLetter
+type postgresDeadLetterRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *pgxpool.Pool
+}
+
+// NewPostgresDeadLetterRepository creates the PostgreSQL version of the DeadLetterRepository
+func NewPostgresDeadLetterRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *pgxpool.Pool,
+) DeadLetterRepository {
+	return &postgresDeadLetterRepository{
+		logger: logger.WithCodeNamespace(fmt.Sprintf("%T", &postgresDeadLetterRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *postgresDeadLetterRepository) Store(ctx context.Context, deadLetter *entities.DeadLetter) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "INSERT INTO dead_letters (" + postgresDeadLetterColumns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)"
+	if _, err := repository.db.Exec(ctx, query, repository.values(deadLetter)...); err != nil {
+		msg := fmt.Sprintf("cannot save dead letter with ID [%s]", deadLetter.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresDeadLetterRepository) Update(ctx context.Context, deadLetter *entities.DeadLetter) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "INSERT INTO dead_letters (" + postgresDeadLetterColumns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) " +
+		"ON CONFLICT (id) DO UPDATE SET event_id = EXCLUDED.event_id, event_type = EXCLUDED.event_type, user_id = EXCLUDED.user_id, event = EXCLUDED.event, " +
+		"listener = EXCLUDED.listener, status = EXCLUDED.status, error = EXCLUDED.error, attempts = EXCLUDED.attempts, " +
+		"replayed_at = EXCLUDED.replayed_at, created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at"
+	if _, err := repository.db.Exec(ctx, query, repository.values(deadLetter)...); err != nil {
+		msg := fmt.Sprintf("cannot update dead letter with ID [%s]", deadLetter.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresDeadLetterRepository) Load(ctx context.Context, deadLetterID uuid.UUID) (*entities.DeadLetter, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	rows, err := repository.db.Query(ctx, "SELECT "+postgresDeadLetterColumns+" FROM dead_letters WHERE id = $1", deadLetterID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load dead letter with ID [%s]", deadLetterID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	deadLetter, err := pgx.CollectExactlyOneRow(rows, repository.scan)
+	if errors.Is(err, pgx.ErrNoRows) {
+		msg := fmt.Sprintf("dead letter with ID [%s] does not exist", deadLetterID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot decode dead letter with ID [%s]", deadLetterID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return deadLetter, nil
+}
+
+func (repository *postgresDeadLetterRepository) Index(ctx context.Context, status entities.DeadLetterStatus, limit uint, skip uint) ([]*entities.DeadLetter, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "SELECT " + postgresDeadLetterColumns + " FROM dead_letters WHERE ($1 = '' OR status = $1) ORDER BY created_at DESC LIMIT $2 OFFSET $3"
+	rows, err := repository.db.Query(ctx, query, string(status), int64(limit), int64(skip))
+	if err != nil {
+		msg := fmt.Sprintf("cannot load dead letters with status [%s]", status)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	deadLetters, err := pgx.CollectRows(rows, repository.scan)
+	if err != nil {
+		msg := fmt.Sprintf("cannot decode dead letters with status [%s]", status)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return deadLetters, nil
+}
+
+func (repository *postgresDeadLetterRepository) DeleteByUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if _, err := repository.db.Exec(ctx, "DELETE FROM dead_letters WHERE user_id = $1", string(userID)); err != nil {
+		msg := fmt.Sprintf("cannot delete dead letters of user with ID [%s]", userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresDeadLetterRepository) values(deadLetter *entities.DeadLetter) []any {
+	return []any{
+		deadLetter.ID,
+		deadLetter.EventID,
+		deadLetter.EventType,
+		string(deadLetter.UserID),
+		deadLetter.Event,
+		deadLetter.Listener,
+		string(deadLetter.Status),
+		deadLetter.Error,
+		deadLetter.Attempts,
+		deadLetter.ReplayedAt,
+		deadLetter.CreatedAt,
+		deadLetter.UpdatedAt,
+	}
+}
+
+func (repository *postgresDeadLetterRepository) scan(row pgx.CollectableRow) (*entities.DeadLetter, error) {
+	deadLetter := new(entities.DeadLetter)
+	err := row.Scan(
+		&deadLetter.ID,
+		&deadLetter.EventID,
+		&deadLetter.EventType,
+		&deadLetter.UserID,
+		&deadLetter.Event,
+		&deadLetter.Listener,
+		&deadLetter.Status,
+		&deadLetter.Error,
+		&deadLetter.Attempts,
+		&deadLetter.ReplayedAt,
+		&deadLetter.CreatedAt,
+		&deadLetter.UpdatedAt,
+	)
+	return deadLetter, err
+}
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/palantir/stacktrace"
+)
+
+const postgresProjectEndpointCallbackAttemptColumns = "id, project_id, project_endpoint_id, project_endpoint_request_id, user_id, callback_index, " +
+	"attempt, request_url, request_method, request_headers, request_body, response_code, response_body, error, succeeded, " +
+	"duration_in_milliseconds, created_at"
+
+// postgresProjectEndpointCallbackAttemptRepository is responsible for persisting entities.ProjectEndpointCallbackAttempt
+type postgresProjectEndpointCallbackAttemptRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *pgxpool.Pool
+}
+
+// NewPostgresProjectEndpointCallbackAttemptRepository creates the PostgreSQL version of the ProjectEndpointCallbackAttemptRepository
+func NewPostgresProjectEndpointCallbackAttemptRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *pgxpool.Pool,
+) ProjectEndpointCallbackAttemptRepository {
+	return &postgresProjectEndpointCallbackAttemptRepository{
+		logger: logger.WithCodeNamespace(fmt.Sprintf("%T", &postgresProjectEndpointCallbackAttemptRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *postgresProjectEndpointCallbackAttemptRepository) Store(ctx context.Context, attempt *entities.ProjectEndpointCallbackAttempt) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "INSERT INTO project_endpoint_callback_attempts (" + postgresProjectEndpointCallbackAttemptColumns + ") " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)"
+	if _, err := repository.db.Exec(ctx, query, repository.values(attempt)...); err != nil {
+		msg := fmt.Sprintf("cannot save callback attempt with ID [%s]", attempt.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectEndpointCallbackAttemptRepository) Index(ctx context.Context, userID entities.UserID, requestID string) ([]*entities.ProjectEndpointCallbackAttempt, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "SELECT " + postgresProjectEndpointCallbackAttemptColumns + " FROM project_endpoint_callback_attempts " +
+		"WHERE user_id = $1 AND project_endpoint_request_id = $2 ORDER BY created_at ASC"
+	rows, err := repository.db.Query(ctx, query, string(userID), requestID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load callback attempts for user with ID [%s] and request ID [%s]", userID, requestID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	attempts, err := pgx.CollectRows(rows, repository.scan)
+	if err != nil {
+		msg := fmt.Sprintf("cannot decode callback attempts for user with ID [%s] and request ID [%s]", userID, requestID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return attempts, nil
+}
+
+func (repository *postgresProjectEndpointCallbackAttemptRepository) DeleteByRequest(ctx context.Context, userID entities.UserID, requestID string) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "DELETE FROM project_endpoint_callback_attempts WHERE user_id = $1 AND project_endpoint_request_id = $2"
+	if _, err := repository.db.Exec(ctx, query, string(userID), requestID); err != nil {
+		msg := fmt.Sprintf("cannot delete callback attempts for user with ID [%s] and request ID [%s]", userID, requestID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectEndpointCallbackAttemptRepository) DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "DELETE FROM project_endpoint_callback_attempts WHERE id IN " +
+		"(SELECT id FROM project_endpoint_callback_attempts WHERE user_id = $1 AND project_id = $2 LIMIT $3)"
+	result, err := repository.db.Exec(ctx, query, string(userID), projectID, int64(limit))
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete callback attempts for user with ID [%s] and project ID [%s]", userID, projectID)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return uint(result.RowsAffected()), nil
+}
+
+func (repository *postgresProjectEndpointCallbackAttemptRepository) values(attempt *entities.ProjectEndpointCallbackAttempt) []any {
+	return []any{
+		attempt.ID,
+		attempt.ProjectID,
+		attempt.ProjectEndpointID,
+		attempt.ProjectEndpointRequestID,
+		string(attempt.UserID),
+		attempt.CallbackIndex,
+		attempt.Attempt,
+		attempt.RequestURL,
+		attempt.RequestMethod,
+		attempt.RequestHeaders,
+		attempt.RequestBody,
+		attempt.ResponseCode,
+		attempt.ResponseBody,
+		attempt.Error,
+		attempt.Succeeded,
+		attempt.DurationInMilliseconds,
+		attempt.CreatedAt,
+	}
+}
+
+func (repository *postgresProjectEndpointCallbackAttemptRepository) scan(row pgx.CollectableRow) (*entities.ProjectEndpointCallbackAttempt, error) {
+	attempt := new(entities.ProjectEndpointCallbackAttempt)
+	err := row.Scan(
+		&attempt.ID,
+		&attempt.ProjectID,
+		&attempt.ProjectEndpointID,
+		&attempt.ProjectEndpointRequestID,
+		&attempt.UserID,
+		&attempt.CallbackIndex,
+		&attempt.Attempt,
+		&attempt.RequestURL,
+		&attempt.RequestMethod,
+		&attempt.RequestHeaders,
+		&attempt.RequestBody,
+		&attempt.ResponseCode,
+		&attempt.ResponseBody,
+		&attempt.Error,
+		&attempt.Succeeded,
+		&attempt.DurationInMilliseconds,
+		&attempt.CreatedAt,
+	)
+	return attempt, err
+}
@@ -0,0 +1,314 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/matchers"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/palantir/stacktrace"
+)
+
+const postgresProjectEndpointColumns = "id, project_id, project_subdomain, user_id, request_method, request_path, request_conditions, " +
+	"scenario_name, scenario_required_state, scenario_new_state, priority, response_code, response_body, response_headers, responses, " +
+	"response_mode, response_counter, response_template_enabled, response_delay_in_milliseconds, faults, callbacks, description, " +
+	"request_count, created_at, updated_at"
+
+const postgresProjectEndpointPlaceholders = "$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25"
+
+// postgresProjectEndpointRepository is responsible for persisting entities.ProjectEndpoint
+type postgresProjectEndpointRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *pgxpool.Pool
+}
+
+// NewPostgresProjectEndpointRepository creates the PostgreSQL version of the ProjectEndpointRepository
+func NewPostgresProjectEndpointRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *pgxpool.Pool,
+) ProjectEndpointRepository {
+	return &postgresProjectEndpointRepository{
+		logger: logger.WithCodeNamespace(fmt.Sprintf("%T", &postgresProjectEndpointRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *postgresProjectEndpointRepository) Store(ctx context.Context, endpoint *entities.ProjectEndpoint) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "INSERT INTO project_endpoints (" + postgresProjectEndpointColumns + ") VALUES (" + postgresProjectEndpointPlaceholders + ")"
+	if _, err := repository.db.Exec(ctx, query, repository.values(endpoint)...); err != nil {
+		msg := fmt.Sprintf("cannot save project endpoint with ID [%s]", endpoint.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectEndpointRepository) Update(ctx context.Context, endpoint *entities.ProjectEndpoint) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	assignments := make([]string, 0)
+	for _, column := range strings.Split(postgresProjectEndpointColumns, ", ") {
+		if column != "id" {
+			assignments = append(assignments, fmt.Sprintf("%s = EXCLUDED.%s", column, column))
+		}
+	}
+
+	query := "INSERT INTO project_endpoints (" + postgresProjectEndpointColumns + ") VALUES (" + postgresProjectEndpointPlaceholders + ") " +
+		"ON CONFLICT (id) DO UPDATE SET " + strings.Join(assignments, ", ")
+	if _, err := repository.db.Exec(ctx, query, repository.values(endpoint)...); err != nil {
+		msg := fmt.Sprintf("cannot update project endpoint with ID [%s]", endpoint.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectEndpointRepository) IncreaseRequestCount(ctx context.Context, projectEndpointID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if _, err := repository.db.Exec(ctx, "UPDATE project_endpoints SET request_count = request_count + 1 WHERE id = $1", projectEndpointID); err != nil {
+		msg := fmt.Sprintf("cannot increase request_count [%T] with ID [%s]", &entities.ProjectEndpoint{}, projectEndpointID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectEndpointRepository) IncreaseResponseCounter(ctx context.Context, projectEndpointID uuid.UUID) (uint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	var counter uint
+	query := "UPDATE project_endpoints SET response_counter = response_counter + 1 WHERE id = $1 RETURNING response_counter"
+	if err := repository.db.QueryRow(ctx, query, projectEndpointID).Scan(&counter); err != nil {
+		msg := fmt.Sprintf("cannot increase response_counter [%T] with ID [%s]", &entities.ProjectEndpoint{}, projectEndpointID)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return counter, nil
+}
+
+func (repository *postgresProjectEndpointRepository) DecreaseRequestCount(ctx context.Context, projectEndpointID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if _, err := repository.db.Exec(ctx, "UPDATE project_endpoints SET request_count = GREATEST(request_count - 1, 0) WHERE id = $1", projectEndpointID); err != nil {
+		msg := fmt.Sprintf("cannot decrease request_count [%T] with ID [%s]", &entities.ProjectEndpoint{}, projectEndpointID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectEndpointRepository) UpdateSubdomain(ctx context.Context, subdomain string, projectID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if _, err := repository.db.Exec(ctx, "UPDATE project_endpoints SET project_subdomain = $1 WHERE project_id = $2", subdomain, projectID); err != nil {
+		msg := fmt.Sprintf("cannot update [project_subdomain] for [%T] with project ID [%s]", &entities.ProjectEndpoint{}, projectID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectEndpointRepository) Fetch(ctx context.Context, userID entities.UserID, projectID uuid.UUID) ([]*entities.ProjectEndpoint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "SELECT " + postgresProjectEndpointColumns + " FROM project_endpoints WHERE user_id = $1 AND project_id = $2 ORDER BY created_at DESC"
+	endpoints, err := repository.query(ctx, query, string(userID), projectID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load project endpoint for user with ID [%s] and project ID [%s]", userID, projectID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return endpoints, nil
+}
+
+func (repository *postgresProjectEndpointRepository) Load(ctx context.Context, userID entities.UserID, projectID uuid.UUID, projectEndpointID uuid.UUID) (*entities.ProjectEndpoint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "SELECT " + postgresProjectEndpointColumns + " FROM project_endpoints WHERE id = $1 AND user_id = $2 AND project_id = $3"
+	rows, err := repository.db.Query(ctx, query, projectEndpointID, string(userID), projectID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load project endpoint with ID [%s]", projectEndpointID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	endpoint, err := pgx.CollectExactlyOneRow(rows, repository.scan)
+	if errors.Is(err, pgx.ErrNoRows) {
+		msg := fmt.Sprintf("project endpoint with ID [%s] does not exist", projectEndpointID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot decode project endpoint with ID [%s]", projectEndpointID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return endpoint, nil
+}
+
+func (repository *postgresProjectEndpointRepository) Delete(ctx context.Context, endpoint *entities.ProjectEndpoint) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if _, err := repository.db.Exec(ctx, "DELETE FROM project_endpoints WHERE id = $1", endpoint.ID); err != nil {
+		msg := fmt.Sprintf("cannot delete [%T] with ID [%s] for user [%s]", endpoint, endpoint.ID, endpoint.UserID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectEndpointRepository) DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "DELETE FROM project_endpoints WHERE id IN (SELECT id FROM project_endpoints WHERE user_id = $1 AND project_id = $2 LIMIT $3)"
+	result, err := repository.db.Exec(ctx, query, string(userID), projectID, int64(limit))
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete project endpoints for user with ID [%s] and project ID [%s]", userID, projectID)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return uint(result.RowsAffected()), nil
+}
+
+func (repository *postgresProjectEndpointRepository) LoadByRequest(ctx context.Context, subdomain string, request *matchers.Request) (*entities.ProjectEndpoint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "SELECT " + postgresProjectEndpointColumns + " FROM project_endpoints WHERE project_subdomain = $1 AND (request_method = $2 OR request_method = 'ANY')"
+	endpoints, err := repository.query(ctx, query, subdomain, strings.ToUpper(request.Method))
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch endpoints with subdomain [%s] and request method [%s]", subdomain, request.Method)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	matches := matchers.MatchEndpoints(endpoints, request)
+	if len(matches) == 0 {
+		msg := fmt.Sprintf("endpoint not found with request method [%s] and request path [%s]", request.Method, request.Path)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	return matches[0].Endpoint, nil
+}
+
+func (repository *postgresProjectEndpointRepository) LoadConflicting(ctx context.Context, endpoint *entities.ProjectEndpoint) (*entities.ProjectEndpoint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "SELECT " + postgresProjectEndpointColumns + " FROM project_endpoints WHERE user_id = $1 AND project_id = $2"
+	args := []any{string(endpoint.UserID), endpoint.ProjectID}
+	if endpoint.RequestMethod != "ANY" {
+		query += " AND (request_method = $3 OR request_method = 'ANY')"
+		args = append(args, endpoint.RequestMethod)
+	}
+
+	endpoints, err := repository.query(ctx, query, args...)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch endpoints with project ID [%s] and request method [%s]", endpoint.ProjectID, endpoint.RequestMethod)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	for _, existing := range endpoints {
+		if existing.ID != endpoint.ID && matchers.Conflicts(endpoint, existing) {
+			return existing, nil
+		}
+	}
+
+	msg := fmt.Sprintf("no conflicting endpoint with project ID [%s], request method [%s] and request path [%s]", endpoint.ProjectID, endpoint.RequestMethod, endpoint.RequestPath)
+	return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+}
+
+func (repository *postgresProjectEndpointRepository) query(ctx context.Context, query string, args ...any) ([]*entities.ProjectEndpoint, error) {
+	rows, err := repository.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot execute query [%s]", query))
+	}
+
+	endpoints, err := pgx.CollectRows(rows, repository.scan)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot decode row into [%T] for query [%s]", &entities.ProjectEndpoint{}, query))
+	}
+
+	return endpoints, nil
+}
+
+func (repository *postgresProjectEndpointRepository) values(endpoint *entities.ProjectEndpoint) []any {
+	return []any{
+		endpoint.ID,
+		endpoint.ProjectID,
+		endpoint.ProjectSubdomain,
+		string(endpoint.UserID),
+		endpoint.RequestMethod,
+		endpoint.RequestPath,
+		endpoint.RequestConditions,
+		endpoint.ScenarioName,
+		endpoint.ScenarioRequiredState,
+		endpoint.ScenarioNewState,
+		endpoint.Priority,
+		endpoint.ResponseCode,
+		endpoint.ResponseBody,
+		endpoint.ResponseHeaders,
+		endpoint.Responses,
+		string(endpoint.ResponseMode),
+		endpoint.ResponseCounter,
+		endpoint.ResponseTemplateEnabled,
+		endpoint.ResponseDelayInMilliseconds,
+		endpoint.Faults,
+		endpoint.Callbacks,
+		endpoint.Description,
+		endpoint.RequestCount,
+		endpoint.CreatedAt,
+		endpoint.UpdatedAt,
+	}
+}
+
+func (repository *postgresProjectEndpointRepository) scan(row pgx.CollectableRow) (*entities.ProjectEndpoint, error) {
+	endpoint := new(entities.ProjectEndpoint)
+	err := row.Scan(
+		&endpoint.ID,
+		&endpoint.ProjectID,
+		&endpoint.ProjectSubdomain,
+		&endpoint.UserID,
+		&endpoint.RequestMethod,
+		&endpoint.RequestPath,
+		&endpoint.RequestConditions,
+		&endpoint.ScenarioName,
+		&endpoint.ScenarioRequiredState,
+		&endpoint.ScenarioNewState,
+		&endpoint.Priority,
+		&endpoint.ResponseCode,
+		&endpoint.ResponseBody,
+		&endpoint.ResponseHeaders,
+		&endpoint.Responses,
+		&endpoint.ResponseMode,
+		&endpoint.ResponseCounter,
+		&endpoint.ResponseTemplateEnabled,
+		&endpoint.ResponseDelayInMilliseconds,
+		&endpoint.Faults,
+		&endpoint.Callbacks,
+		&endpoint.Description,
+		&endpoint.RequestCount,
+		&endpoint.CreatedAt,
+		&endpoint.UpdatedAt,
+	)
+	return endpoint, err
+}
@@ -0,0 +1,370 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/matchers"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	"github.com/palantir/stacktrace"
+)
+
+const postgresProjectEndpointRequestColumns = "id, project_id, project_endpoint_id, user_id, request_method, request_url, request_headers, " +
+	"request_body, request_ip_address, response_code, response_body, response_headers, response_delay_in_milliseconds, passthrough, " +
+	"response_fault, response_index, unmatched, created_at"
+
+// postgresProjectEndpointRequestRepository is responsible for persisting entities.ProjectEndpointRequest
+type postgresProjectEndpointRequestRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *pgxpool.Pool
+}
+
+// NewPostgresProjectEndpointRequestRepository creates the PostgreSQL version of the ProjectEndpointRequestRepository
+func NewPostgresProjectEndpointRequestRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *pgxpool.Pool,
+) ProjectEndpointRequestRepository {
+	return &postgresProjectEndpointRequestRepository{
+		logger: logger.WithCodeNamespace(fmt.Sprintf("%T", &postgresProjectEndpointRequestRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *postgresProjectEndpointRequestRepository) Store(ctx context.Context, request *entities.ProjectEndpointRequest) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "INSERT INTO project_endpoint_requests (" + postgresProjectEndpointRequestColumns + ") " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)"
+	_, err := repository.db.Exec(
+		ctx,
+		query,
+		request.ID,
+		request.ProjectID,
+		request.ProjectEndpointID,
+		string(request.UserID),
+		request.RequestMethod,
+		request.RequestURL,
+		request.RequestHeaders,
+		request.RequestBody,
+		request.RequestIPAddress,
+		request.ResponseCode,
+		request.ResponseBody,
+		request.ResponseHeaders,
+		request.ResponseDelayInMilliseconds,
+		request.Passthrough,
+		request.ResponseFault,
+		request.ResponseIndex,
+		request.Unmatched,
+		request.CreatedAt,
+	)
+	if err != nil {
+		msg := fmt.Sprintf("cannot save project endpoint request with ID [%s]", request.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectEndpointRequestRepository) Delete(ctx context.Context, request *entities.ProjectEndpointRequest) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if _, err := repository.db.Exec(ctx, "DELETE FROM project_endpoint_requests WHERE id = $1", request.ID); err != nil {
+		msg := fmt.Sprintf("cannot delete [%T] with ID [%s] for user [%s]", request, request.ID, request.UserID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectEndpointRequestRepository) DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "DELETE FROM project_endpoint_requests WHERE id IN (SELECT id FROM project_endpoint_requests WHERE user_id = $1 AND project_id = $2 LIMIT $3)"
+	result, err := repository.db.Exec(ctx, query, string(userID), projectID, int64(limit))
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete project endpoint requests for user with ID [%s] and project ID [%s]", userID, projectID)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return uint(result.RowsAffected()), nil
+}
+
+func (repository *postgresProjectEndpointRequestRepository) DeleteBefore(ctx context.Context, userID entities.UserID, before time.Time, limit uint) ([]*entities.ProjectEndpointRequest, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "DELETE FROM project_endpoint_requests WHERE id IN (SELECT id FROM project_endpoint_requests WHERE user_id = $1 AND id < $2 LIMIT $3) " +
+		"RETURNING " + postgresProjectEndpointRequestColumns
+	requests, err := repository.query(ctx, query, string(userID), requestIDBefore(before), int64(limit))
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete requests created before [%s] for user with ID [%s]", before, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return requests, nil
+}
+
+func (repository *postgresProjectEndpointRequestRepository) Load(ctx context.Context, userID entities.UserID, requestID ulid.ULID) (*entities.ProjectEndpointRequest, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "SELECT " + postgresProjectEndpointRequestColumns + " FROM project_endpoint_requests WHERE id = $1 AND user_id = $2"
+	rows, err := repository.db.Query(ctx, query, requestID.String(), string(userID))
+	if err != nil {
+		msg := fmt.Sprintf("cannot load project endpoint request for user with ID [%s] and request ID [%s]", userID, requestID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	request, err := pgx.CollectExactlyOneRow(rows, repository.scan)
+	if errors.Is(err, pgx.ErrNoRows) {
+		msg := fmt.Sprintf("request with ID [%s] for userID [%s] does not exist", requestID, userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot decode project endpoint request with ID [%s]", requestID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return request, nil
+}
+
+func (repository *postgresProjectEndpointRequestRepository) GetProjectTraffic(ctx context.Context, userID entities.UserID, projectID uuid.UUID) ([]*TimeSeriesData, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	data, err := repository.traffic(ctx, "project_id", string(userID), projectID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load project traffic for user with ID [%s] and project ID [%s]", userID, projectID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return normalizeTimeSeries(data), nil
+}
+
+func (repository *postgresProjectEndpointRequestRepository) GetEndpointTraffic(ctx context.Context, userID entities.UserID, endpointID uuid.UUID) ([]*TimeSeriesData, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	data, err := repository.traffic(ctx, "project_endpoint_id", string(userID), endpointID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load project endpoint traffic for user with ID [%s] and project endpoint ID [%s]", userID, endpointID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return normalizeTimeSeries(data), nil
+}
+
+func (repository *postgresProjectEndpointRequestRepository) FetchLatest(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) ([]*entities.ProjectEndpointRequest, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "SELECT " + postgresProjectEndpointRequestColumns + " FROM project_endpoint_requests WHERE user_id = $1 AND project_id = $2 ORDER BY id DESC LIMIT $3"
+	requests, err := repository.query(ctx, query, string(userID), projectID, int64(limit))
+	if err != nil {
+		msg := fmt.Sprintf("cannot load the latest [%d] requests for user with ID [%s] and project ID [%s]", limit, userID, projectID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return requests, nil
+}
+
+func (repository *postgresProjectEndpointRequestRepository) Index(ctx context.Context, userID entities.UserID, endpointID uuid.UUID, limit uint, previousID *ulid.ULID, nextID *ulid.ULID) ([]*entities.ProjectEndpointRequest, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "SELECT " + postgresProjectEndpointRequestColumns + " FROM project_endpoint_requests WHERE user_id = $1 AND project_endpoint_id = $2"
+	args := []any{string(userID), endpointID, int64(limit)}
+
+	// ULIDs are sorted by time so the ID is used as the pagination cursor
+	if previousID != nil {
+		query += " AND id < $4 ORDER BY id DESC LIMIT $3"
+		args = append(args, previousID.String())
+	} else if nextID != nil {
+		query += " AND id > $4 ORDER BY id ASC LIMIT $3"
+		args = append(args, nextID.String())
+	} else {
+		query += " ORDER BY id DESC LIMIT $3"
+	}
+
+	requests, err := repository.query(ctx, query, args...)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load project endpoint requests for user with ID [%s] and endpoint ID [%s]", userID, endpointID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return requests, nil
+}
+
+func (repository *postgresProjectEndpointRequestRepository) Search(ctx context.Context, userID entities.UserID, params *ProjectEndpointRequestSearchParams) ([]*entities.ProjectEndpointRequest, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	var args []any
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conditions := []string{"user_id = " + arg(string(userID)), "project_id = " + arg(params.ProjectID)}
+	if params.From != nil {
+		conditions = append(conditions, "id >= "+arg(requestIDBefore(*params.From)))
+	}
+	if params.To != nil {
+		conditions = append(conditions, "id < "+arg(requestIDBefore(*params.To)))
+	}
+	if params.Unmatched != nil {
+		conditions = append(conditions, "unmatched = "+arg(*params.Unmatched))
+	}
+	if params.Method != "" {
+		conditions = append(conditions, "request_method = "+arg(params.Method))
+	}
+	if params.ResponseCode != nil {
+		conditions = append(conditions, "response_code = "+arg(int64(*params.ResponseCode)))
+	}
+	if params.IPAddress != "" {
+		conditions = append(conditions, "request_ip_address = "+arg(params.IPAddress))
+	}
+	if params.PathPrefix != "" {
+		conditions = append(conditions, "request_url ~ "+arg(params.pathPrefixPattern()))
+	}
+	if params.HeaderKey != "" {
+		condition := "lower(header.key) = lower(" + arg(params.HeaderKey) + ")"
+		if params.HeaderValue != nil {
+			condition += " AND header.value = " + arg(*params.HeaderValue)
+		}
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM jsonb_array_elements(CASE WHEN jsonb_typeof(httpmock_try_jsonb(request_headers)) = 'array' THEN request_headers::jsonb ELSE '[]' END) AS headers, "+
+			"jsonb_each_text(CASE WHEN jsonb_typeof(headers.value) = 'object' THEN headers.value ELSE '{}' END) AS header WHERE "+condition+")")
+	}
+	if params.Body != "" {
+		conditions = append(conditions, "strpos(lower(request_body), lower("+arg(params.Body)+")) > 0")
+	}
+	if params.JSONPath != nil {
+		conditions = append(conditions, repository.jsonPathCondition("httpmock_try_jsonb(request_body)", params.JSONPath.Selectors(), params.JSONValue, arg))
+	}
+
+	// ULIDs are sorted by time so the ID is used as the pagination cursor
+	order := "DESC"
+	if params.PreviousID != nil {
+		conditions = append(conditions, "id < "+arg(params.PreviousID.String()))
+	} else if params.NextID != nil {
+		conditions = append(conditions, "id > "+arg(params.NextID.String()))
+		order = "ASC"
+	}
+
+	query := "SELECT " + postgresProjectEndpointRequestColumns + " FROM project_endpoint_requests WHERE " + strings.Join(conditions, " AND ") +
+		" ORDER BY id " + order + " LIMIT " + arg(int64(params.Limit))
+
+	requests, err := repository.query(ctx, query, args...)
+	if err != nil {
+		msg := fmt.Sprintf("cannot search project endpoint requests for user with ID [%s] and project ID [%s]", userID, params.ProjectID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return requests, nil
+}
+
+// jsonPathCondition returns a condition which is true when the selectors select a value in the JSONB document which is equal to the value when it is set.
+// The selectors are applied with the JSONB operators so that they behave like matchers.JSONPath in the other repositories.
+func (repository *postgresProjectEndpointRequestRepository) jsonPathCondition(document string, selectors []matchers.JSONPathSelector, value *string, arg func(value any) string) string {
+	for i, selector := range selectors {
+		if selector.Wildcard {
+			alias := fmt.Sprintf("children%d", len(selectors)-i)
+			return fmt.Sprintf(
+				"EXISTS (SELECT 1 FROM (SELECT value FROM jsonb_each(CASE WHEN jsonb_typeof(%[1]s) = 'object' THEN %[1]s ELSE '{}' END) "+
+					"UNION ALL SELECT value FROM jsonb_array_elements(CASE WHEN jsonb_typeof(%[1]s) = 'array' THEN %[1]s ELSE '[]' END)) AS %[2]s WHERE %[3]s)",
+				document,
+				alias,
+				repository.jsonPathCondition(alias+".value", selectors[i+1:], value, arg),
+			)
+		}
+
+		if selector.IsIndex {
+			document = fmt.Sprintf("(%s -> %s::int)", document, arg(selector.Index))
+		} else {
+			document = fmt.Sprintf("(%s -> %s::text)", document, arg(selector.Key))
+		}
+	}
+
+	if value == nil {
+		return document + " IS NOT NULL"
+	}
+
+	return fmt.Sprintf("(CASE WHEN jsonb_typeof(%[1]s) = 'string' THEN %[1]s #>> '{}' ELSE %[1]s::text END) = %[2]s", document, arg(*value))
+}
+
+// traffic counts the requests per day in the last 30 days where the column has the given ID
+func (repository *postgresProjectEndpointRequestRepository) traffic(ctx context.Context, column string, userID string, id uuid.UUID) ([]*TimeSeriesData, error) {
+	query := fmt.Sprintf(
+		"SELECT date_trunc('day', created_at AT TIME ZONE 'UTC') AS timestamp, COUNT(*) AS count FROM project_endpoint_requests "+
+			"WHERE user_id = $1 AND %s = $2 AND created_at >= $3 GROUP BY 1",
+		column,
+	)
+
+	rows, err := repository.db.Query(ctx, query, userID, id, time.Now().UTC().AddDate(0, 0, -30))
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot execute query [%s]", query))
+	}
+
+	data, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*TimeSeriesData, error) {
+		point := new(TimeSeriesData)
+		return point, row.Scan(&point.Timestamp, &point.Count)
+	})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot decode row into [%T] for query [%s]", &TimeSeriesData{}, query))
+	}
+
+	return data, nil
+}
+
+func (repository *postgresProjectEndpointRequestRepository) query(ctx context.Context, query string, args ...any) ([]*entities.ProjectEndpointRequest, error) {
+	rows, err := repository.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot execute query [%s]", query))
+	}
+
+	requests, err := pgx.CollectRows(rows, repository.scan)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot decode row into [%T] for query [%s]", &entities.ProjectEndpointRequest{}, query))
+	}
+
+	return requests, nil
+}
+
+func (repository *postgresProjectEndpointRequestRepository) scan(row pgx.CollectableRow) (*entities.ProjectEndpointRequest, error) {
+	request := new(entities.ProjectEndpointRequest)
+	err := row.Scan(
+		&request.ID,
+		&request.ProjectID,
+		&request.ProjectEndpointID,
+		&request.UserID,
+		&request.RequestMethod,
+		&request.RequestURL,
+		&request.RequestHeaders,
+		&request.RequestBody,
+		&request.RequestIPAddress,
+		&request.ResponseCode,
+		&request.ResponseBody,
+		&request.ResponseHeaders,
+		&request.ResponseDelayInMilliseconds,
+		&request.Passthrough,
+		&request.ResponseFault,
+		&request.ResponseIndex,
+		&request.Unmatched,
+		&request.CreatedAt,
+	)
+	return request, err
+}
@@ -0,0 +1,206 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/palantir/stacktrace"
+)
+
+const postgresProjectColumns = "id, user_id, subdomain, name, description, upstream_url, recording_enabled, scenario_states, created_at, updated_at"
+
+// postgresProjectRepository is responsible for persisting entities.Project
+type postgresProjectRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *pgxpool.Pool
+}
+
+// NewPostgresProjectRepository creates the PostgreSQL version of the ProjectRepository
+func NewPostgresProjectRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *pgxpool.Pool,
+) ProjectRepository {
+	return &postgresProjectRepository{
+		logger: logger.WithCodeNamespace(fmt.Sprintf("%T", &postgresProjectRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *postgresProjectRepository) Store(ctx context.Context, project *entities.Project) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "INSERT INTO projects (" + postgresProjectColumns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)"
+	if _, err := repository.db.Exec(ctx, query, repository.values(project)...); err != nil {
+		msg := fmt.Sprintf("cannot save project with ID [%s]", project.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectRepository) Update(ctx context.Context, project *entities.Project) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "INSERT INTO projects (" + postgresProjectColumns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) " +
+		"ON CONFLICT (id) DO UPDATE SET user_id = EXCLUDED.user_id, subdomain = EXCLUDED.subdomain, name = EXCLUDED.name, " +
+		"description = EXCLUDED.description, upstream_url = EXCLUDED.upstream_url, recording_enabled = EXCLUDED.recording_enabled, " +
+		"scenario_states = EXCLUDED.scenario_states, created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at"
+	if _, err := repository.db.Exec(ctx, query, repository.values(project)...); err != nil {
+		msg := fmt.Sprintf("cannot update project with ID [%s]", project.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectRepository) Fetch(ctx context.Context, userID entities.UserID) ([]*entities.Project, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "SELECT " + postgresProjectColumns + " FROM projects WHERE user_id = $1 ORDER BY created_at DESC"
+	rows, err := repository.db.Query(ctx, query, string(userID))
+	if err != nil {
+		msg := fmt.Sprintf("cannot load projects for user with ID [%s]", userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	projects, err := pgx.CollectRows(rows, repository.scan)
+	if err != nil {
+		msg := fmt.Sprintf("cannot decode project for user with ID [%s]", userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return projects, nil
+}
+
+func (repository *postgresProjectRepository) Load(ctx context.Context, userID entities.UserID, projectID uuid.UUID) (*entities.Project, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "SELECT " + postgresProjectColumns + " FROM projects WHERE id = $1 AND user_id = $2"
+	project, err := repository.one(ctx, query, projectID, string(userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		msg := fmt.Sprintf("project with ID [%s] does not exist", projectID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot load project with ID [%s]", projectID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return project, nil
+}
+
+func (repository *postgresProjectRepository) Delete(ctx context.Context, userID entities.UserID, projectID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	result, err := repository.db.Exec(ctx, "DELETE FROM projects WHERE id = $1 AND user_id = $2", projectID, string(userID))
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete project with ID [%s] for user [%s]", projectID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if result.RowsAffected() == 0 {
+		msg := fmt.Sprintf("project with ID [%s] does not exist", projectID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectRepository) UpdateScenarioState(ctx context.Context, projectID uuid.UUID, scenario string, state string) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "UPDATE projects SET scenario_states = " +
+		"(CASE WHEN jsonb_typeof(scenario_states) = 'object' THEN scenario_states ELSE '{}'::jsonb END) || jsonb_build_object($2::text, $3::text) " +
+		"WHERE id = $1"
+	if _, err := repository.db.Exec(ctx, query, projectID, scenario, state); err != nil {
+		msg := fmt.Sprintf("cannot update scenario [%s] to state [%s] for project with ID [%s]", scenario, state, projectID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectRepository) ResetScenarioStates(ctx context.Context, projectID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if _, err := repository.db.Exec(ctx, "UPDATE projects SET scenario_states = '{}'::jsonb WHERE id = $1", projectID); err != nil {
+		msg := fmt.Sprintf("cannot reset scenario states for project with ID [%s]", projectID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectRepository) LoadWithSubdomain(ctx context.Context, subdomain string) (*entities.Project, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "SELECT " + postgresProjectColumns + " FROM projects WHERE subdomain = $1"
+	project, err := repository.one(ctx, query, subdomain)
+	if errors.Is(err, pgx.ErrNoRows) {
+		msg := fmt.Sprintf("project not found with subdomain [%s]", subdomain)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot load a project with subdomain [%s]", subdomain)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return project, nil
+}
+
+func (repository *postgresProjectRepository) one(ctx context.Context, query string, args ...any) (*entities.Project, error) {
+	rows, err := repository.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot execute query [%s]", query))
+	}
+	return pgx.CollectExactlyOneRow(rows, repository.scan)
+}
+
+func (repository *postgresProjectRepository) values(project *entities.Project) []any {
+	return []any{
+		project.ID,
+		string(project.UserID),
+		project.Subdomain,
+		project.Name,
+		project.Description,
+		project.UpstreamURL,
+		project.RecordingEnabled,
+		project.ScenarioStates,
+		project.CreatedAt,
+		project.UpdatedAt,
+	}
+}
+
+func (repository *postgresProjectRepository) scan(row pgx.CollectableRow) (*entities.Project, error) {
+	project := new(entities.Project)
+	err := row.Scan(
+		&project.ID,
+		&project.UserID,
+		&project.Subdomain,
+		&project.Name,
+		&project.Description,
+		&project.UpstreamURL,
+		&project.RecordingEnabled,
+		&project.ScenarioStates,
+		&project.CreatedAt,
+		&project.UpdatedAt,
+	)
+	return project, err
+}
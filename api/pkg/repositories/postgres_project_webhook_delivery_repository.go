@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/palantir/stacktrace"
+)
+
+const postgresProjectWebhookDeliveryColumns = "id, project_webhook_id, project_id, user_id, event_id, event_type, attempt, request_url, " +
+	"response_code, response_body, error, succeeded, duration_in_milliseconds, created_at"
+
+// postgresProjectWebhookDeliveryRepository is responsible for persisting entities.ProjectWebhookDelivery
+type postgresProjectWebhookDeliveryRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *pgxpool.Pool
+}
+
+// NewPostgresProjectWebhookDeliveryRepository creates the PostgreSQL version of the ProjectWebhookDeliveryRepository
+func NewPostgresProjectWebhookDeliveryRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *pgxpool.Pool,
+) ProjectWebhookDeliveryRepository {
+	return &postgresProjectWebhookDeliveryRepository{
+		logger: logger.WithCodeNamespace(fmt.Sprintf("%T", &postgresProjectWebhookDeliveryRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *postgresProjectWebhookDeliveryRepository) Store(ctx context.Context, delivery *entities.ProjectWebhookDelivery) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "INSERT INTO project_webhook_deliveries (" + postgresProjectWebhookDeliveryColumns + ") " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)"
+	if _, err := repository.db.Exec(ctx, query, repository.values(delivery)...); err != nil {
+		msg := fmt.Sprintf("cannot save webhook delivery with ID [%s]", delivery.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectWebhookDeliveryRepository) Index(ctx context.Context, userID entities.UserID, webhookID uuid.UUID, limit uint) ([]*entities.ProjectWebhookDelivery, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "SELECT " + postgresProjectWebhookDeliveryColumns + " FROM project_webhook_deliveries " +
+		"WHERE user_id = $1 AND project_webhook_id = $2 ORDER BY created_at DESC LIMIT $3"
+	rows, err := repository.db.Query(ctx, query, string(userID), webhookID, int64(limit))
+	if err != nil {
+		msg := fmt.Sprintf("cannot load deliveries for user with ID [%s] and webhook ID [%s]", userID, webhookID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	deliveries, err := pgx.CollectRows(rows, repository.scan)
+	if err != nil {
+		msg := fmt.Sprintf("cannot decode deliveries for user with ID [%s] and webhook ID [%s]", userID, webhookID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return deliveries, nil
+}
+
+func (repository *postgresProjectWebhookDeliveryRepository) DeleteByWebhook(ctx context.Context, userID entities.UserID, webhookID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "DELETE FROM project_webhook_deliveries WHERE user_id = $1 AND project_webhook_id = $2"
+	if _, err := repository.db.Exec(ctx, query, string(userID), webhookID); err != nil {
+		msg := fmt.Sprintf("cannot delete deliveries for user with ID [%s] and webhook ID [%s]", userID, webhookID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectWebhookDeliveryRepository) DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "DELETE FROM project_webhook_deliveries WHERE id IN " +
+		"(SELECT id FROM project_webhook_deliveries WHERE user_id = $1 AND project_id = $2 LIMIT $3)"
+	result, err := repository.db.Exec(ctx, query, string(userID), projectID, int64(limit))
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete deliveries for user with ID [%s] and project ID [%s]", userID, projectID)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return uint(result.RowsAffected()), nil
+}
+
+func (repository *postgresProjectWebhookDeliveryRepository) DeleteBefore(ctx context.Context, userID entities.UserID, before time.Time, limit uint) (uint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "DELETE FROM project_webhook_deliveries WHERE id IN " +
+		"(SELECT id FROM project_webhook_deliveries WHERE user_id = $1 AND created_at < $2 LIMIT $3)"
+	result, err := repository.db.Exec(ctx, query, string(userID), before, int64(limit))
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete deliveries created before [%s] for user with ID [%s]", before, userID)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return uint(result.RowsAffected()), nil
+}
+
+func (repository *postgresProjectWebhookDeliveryRepository) values(delivery *entities.ProjectWebhookDelivery) []any {
+	return []any{
+		delivery.ID,
+		delivery.ProjectWebhookID,
+		delivery.ProjectID,
+		string(delivery.UserID),
+		delivery.EventID,
+		delivery.EventType,
+		delivery.Attempt,
+		delivery.RequestURL,
+		delivery.ResponseCode,
+		delivery.ResponseBody,
+		delivery.Error,
+		delivery.Succeeded,
+		delivery.DurationInMilliseconds,
+		delivery.CreatedAt,
+	}
+}
+
+func (repository *postgresProjectWebhookDeliveryRepository) scan(row pgx.CollectableRow) (*entities.ProjectWebhookDelivery, error) {
+	delivery := new(entities.ProjectWebhookDelivery)
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.ProjectWebhookID,
+		&delivery.ProjectID,
+		&delivery.UserID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&delivery.Attempt,
+		&delivery.RequestURL,
+		&delivery.ResponseCode,
+		&delivery.ResponseBody,
+		&delivery.Error,
+		&delivery.Succeeded,
+		&delivery.DurationInMilliseconds,
+		&delivery.CreatedAt,
+	)
+	return delivery, err
+}
@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/palantir/stacktrace"
+)
+
+const postgresProjectWebhookColumns = "id, project_id, user_id, url, mode, signing_secret, created_at, updated_at"
+
+// postgresProjectWebhookRepository is responsible for persisting entities.ProjectWebhook
+type postgresProjectWebhookRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *pgxpool.Pool
+}
+
+// NewPostgresProjectWebhookRepository creates the PostgreSQL version of the ProjectWebhookRepository
+func NewPostgresProjectWebhookRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *pgxpool.Pool,
+) ProjectWebhookRepository {
+	return &postgresProjectWebhookRepository{
+		logger: logger.WithCodeNamespace(fmt.Sprintf("%T", &postgresProjectWebhookRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *postgresProjectWebhookRepository) Store(ctx context.Context, webhook *entities.ProjectWebhook) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "INSERT INTO project_webhooks (" + postgresProjectWebhookColumns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)"
+	if _, err := repository.db.Exec(ctx, query, repository.values(webhook)...); err != nil {
+		msg := fmt.Sprintf("cannot save webhook with ID [%s]", webhook.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectWebhookRepository) Update(ctx context.Context, webhook *entities.ProjectWebhook) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "INSERT INTO project_webhooks (" + postgresProjectWebhookColumns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8) " +
+		"ON CONFLICT (id) DO UPDATE SET project_id = EXCLUDED.project_id, user_id = EXCLUDED.user_id, url = EXCLUDED.url, " +
+		"mode = EXCLUDED.mode, signing_secret = EXCLUDED.signing_secret, created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at"
+	if _, err := repository.db.Exec(ctx, query, repository.values(webhook)...); err != nil {
+		msg := fmt.Sprintf("cannot update webhook with ID [%s]", webhook.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectWebhookRepository) Index(ctx context.Context, userID entities.UserID, projectID uuid.UUID) ([]*entities.ProjectWebhook, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "SELECT " + postgresProjectWebhookColumns + " FROM project_webhooks WHERE user_id = $1 AND project_id = $2 ORDER BY created_at ASC"
+	rows, err := repository.db.Query(ctx, query, string(userID), projectID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load webhooks for user with ID [%s] and project ID [%s]", userID, projectID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	webhooks, err := pgx.CollectRows(rows, repository.scan)
+	if err != nil {
+		msg := fmt.Sprintf("cannot decode webhooks for user with ID [%s] and project ID [%s]", userID, projectID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return webhooks, nil
+}
+
+func (repository *postgresProjectWebhookRepository) Load(ctx context.Context, userID entities.UserID, projectID uuid.UUID, webhookID uuid.UUID) (*entities.ProjectWebhook, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "SELECT " + postgresProjectWebhookColumns + " FROM project_webhooks WHERE id = $1 AND user_id = $2 AND project_id = $3"
+	rows, err := repository.db.Query(ctx, query, webhookID, string(userID), projectID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load webhook with ID [%s]", webhookID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	webhook, err := pgx.CollectExactlyOneRow(rows, repository.scan)
+	if errors.Is(err, pgx.ErrNoRows) {
+		msg := fmt.Sprintf("webhook with ID [%s] does not exist", webhookID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot decode webhook with ID [%s]", webhookID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return webhook, nil
+}
+
+func (repository *postgresProjectWebhookRepository) Delete(ctx context.Context, userID entities.UserID, projectID uuid.UUID, webhookID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "DELETE FROM project_webhooks WHERE id = $1 AND user_id = $2 AND project_id = $3"
+	result, err := repository.db.Exec(ctx, query, webhookID, string(userID), projectID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete webhook with ID [%s] for user [%s]", webhookID, userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if result.RowsAffected() == 0 {
+		msg := fmt.Sprintf("webhook with ID [%s] does not exist", webhookID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresProjectWebhookRepository) DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "DELETE FROM project_webhooks WHERE id IN (SELECT id FROM project_webhooks WHERE user_id = $1 AND project_id = $2 LIMIT $3)"
+	result, err := repository.db.Exec(ctx, query, string(userID), projectID, int64(limit))
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete webhooks for user with ID [%s] and project ID [%s]", userID, projectID)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return uint(result.RowsAffected()), nil
+}
+
+func (repository *postgresProjectWebhookRepository) values(webhook *entities.ProjectWebhook) []any {
+	return []any{
+		webhook.ID,
+		webhook.ProjectID,
+		string(webhook.UserID),
+		webhook.URL,
+		string(webhook.Mode),
+		webhook.SigningSecret,
+		webhook.CreatedAt,
+		webhook.UpdatedAt,
+	}
+}
+
+func (repository *postgresProjectWebhookRepository) scan(row pgx.CollectableRow) (*entities.ProjectWebhook, error) {
+	webhook := new(entities.ProjectWebhook)
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.ProjectID,
+		&webhook.UserID,
+		&webhook.URL,
+		&webhook.Mode,
+		&webhook.SigningSecret,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	return webhook, err
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/palantir/stacktrace"
+)
+
+//go:embed migrations/postgres/*.sql
+var postgresMigrations embed.FS
+
+// postgresMigrationsLockID is the advisory lock which prevents multiple instances from running the migrations at the same time
+const postgresMigrationsLockID = int64(7_449_291_055)
+
+// MigratePostgres applies the SQL files in migrations/postgres which have not been applied yet in alphabetical order.
+// Applied migrations are recorded in the schema_migrations table so each file runs only once.
+func MigratePostgres(ctx context.Context, logger telemetry.Logger, db *pgxpool.Pool) error {
+	names, err := fs.Glob(postgresMigrations, "migrations/postgres/*.sql")
+	if err != nil {
+		return stacktrace.Propagate(err, "cannot list the postgres migrations")
+	}
+	sort.Strings(names)
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return stacktrace.Propagate(err, "cannot start the postgres migrations transaction")
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			logger.Error(stacktrace.Propagate(rollbackErr, "cannot rollback the postgres migrations transaction"))
+		}
+	}()
+
+	if _, err = tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", postgresMigrationsLockID); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot acquire the postgres advisory lock [%d]", postgresMigrationsLockID))
+	}
+
+	if _, err = tx.Exec(ctx, "CREATE TABLE IF NOT EXISTS schema_migrations (version TEXT PRIMARY KEY, applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW())"); err != nil {
+		return stacktrace.Propagate(err, "cannot create the [schema_migrations] table")
+	}
+
+	for _, name := range names {
+		var applied bool
+		if err = tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", name).Scan(&applied); err != nil {
+			return stacktrace.Propagate(err, fmt.Sprintf("cannot check if the migration [%s] was applied", name))
+		}
+		if applied {
+			continue
+		}
+
+		content, err := postgresMigrations.ReadFile(name)
+		if err != nil {
+			return stacktrace.Propagate(err, fmt.Sprintf("cannot read the migration [%s]", name))
+		}
+
+		if _, err = tx.Exec(ctx, string(content)); err != nil {
+			return stacktrace.Propagate(err, fmt.Sprintf("cannot apply the migration [%s]", name))
+		}
+
+		if _, err = tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", name); err != nil {
+			return stacktrace.Propagate(err, fmt.Sprintf("cannot record the migration [%s]", name))
+		}
+
+		logger.Info(fmt.Sprintf("applied the postgres migration [%s]", name))
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return stacktrace.Propagate(err, "cannot commit the postgres migrations transaction")
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/palantir/stacktrace"
+)
+
+const postgresUserColumns = "id, email, first_name, last_name, subscription_name, subscription_id, subscription_status, " +
+	"subscription_renews_at, subscription_ends_at, created_at, updated_at"
+
+// postgresUserRepository is responsible for persisting entities.User
+type postgresUserRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *pgxpool.Pool
+}
+
+// NewPostgresUserRepository creates the PostgreSQL version of the UserRepository
+func NewPostgresUserRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *pgxpool.Pool,
+) UserRepository {
+	return &postgresUserRepository{
+		logger: logger.WithCodeNamespace(fmt.Sprintf("%T", &postgresUserRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *postgresUserRepository) Store(ctx context.Context, user *entities.User) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "INSERT INTO users (" + postgresUserColumns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)"
+	if _, err := repository.db.Exec(ctx, query, repository.values(user)...); err != nil {
+		msg := fmt.Sprintf("cannot save user with ID [%s]", user.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresUserRepository) Update(ctx context.Context, user *entities.User) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "INSERT INTO users (" + postgresUserColumns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) " +
+		"ON CONFLICT (id) DO UPDATE SET email = EXCLUDED.email, first_name = EXCLUDED.first_name, last_name = EXCLUDED.last_name, " +
+		"subscription_name = EXCLUDED.subscription_name, subscription_id = EXCLUDED.subscription_id, " +
+		"subscription_status = EXCLUDED.subscription_status, subscription_renews_at = EXCLUDED.subscription_renews_at, " +
+		"subscription_ends_at = EXCLUDED.subscription_ends_at, created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at"
+	if _, err := repository.db.Exec(ctx, query, repository.values(user)...); err != nil {
+		msg := fmt.Sprintf("cannot update user with ID [%s]", user.ID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (repository *postgresUserRepository) Load(ctx context.Context, userID entities.UserID) (*entities.User, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	user, err := repository.one(ctx, "SELECT "+postgresUserColumns+" FROM users WHERE id = $1", string(userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		msg := fmt.Sprintf("user with ID [%s] does not exist", userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot load user with ID [%s]", userID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return user, nil
+}
+
+func (repository *postgresUserRepository) LoadBySubscriptionID(ctx context.Context, subscriptionID string) (*entities.User, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	user, err := repository.one(ctx, "SELECT "+postgresUserColumns+" FROM users WHERE subscription_id = $1 LIMIT 1", subscriptionID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		msg := fmt.Sprintf("user with subscriptionID [%s] does not exist", subscriptionID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot query user with subscriptionID [%s]", subscriptionID)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return user, nil
+}
+
+func (repository *postgresUserRepository) LoadOrStore(ctx context.Context, authUser entities.AuthUser) (*entities.User, bool, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	user := &entities.User{
+		ID:               authUser.ID,
+		Email:            authUser.Email,
+		SubscriptionName: entities.SubscriptionNameFree,
+		FirstName:        authUser.FirstName,
+		LastName:         authUser.LastName,
+		CreatedAt:        time.Now().UTC(),
+		UpdatedAt:        time.Now().UTC(),
+	}
+
+	query := "INSERT INTO users (" + postgresUserColumns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) ON CONFLICT (id) DO NOTHING"
+	result, err := repository.db.Exec(ctx, query, repository.values(user)...)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load or create user from auth user [%+#v]", authUser)
+		return nil, false, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if result.RowsAffected() == 1 {
+		return user, true, nil
+	}
+
+	existingUser, err := repository.Load(ctx, authUser.ID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load or create user from auth user [%+#v]", authUser)
+		return nil, false, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return existingUser, false, nil
+}
+
+func (repository *postgresUserRepository) one(ctx context.Context, query string, args ...any) (*entities.User, error) {
+	rows, err := repository.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot execute query [%s]", query))
+	}
+	return pgx.CollectExactlyOneRow(rows, repository.scan)
+}
+
+func (repository *postgresUserRepository) values(user *entities.User) []any {
+	return []any{
+		string(user.ID),
+		user.Email,
+		user.FirstName,
+		user.LastName,
+		string(user.SubscriptionName),
+		user.SubscriptionID,
+		user.SubscriptionStatus,
+		user.SubscriptionRenewsAt,
+		user.SubscriptionEndsAt,
+		user.CreatedAt,
+		user.UpdatedAt,
+	}
+}
+
+func (repository *postgresUserRepository) scan(row pgx.CollectableRow) (*entities.User, error) {
+	user := new(entities.User)
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.FirstName,
+		&user.LastName,
+		&user.SubscriptionName,
+		&user.SubscriptionID,
+		&user.SubscriptionStatus,
+		&user.SubscriptionRenewsAt,
+		&user.SubscriptionEndsAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	return user, err
+}
+
+func (repository *postgresUserRepository) Delete(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	result, err := repository.db.Exec(ctx, "DELETE FROM users WHERE id = $1", string(userID))
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete user with ID [%s]", userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if result.RowsAffected() == 0 {
+		msg := fmt.Sprintf("user with ID [%s] does not exist", userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCode(ErrCodeNotFound, msg))
+	}
+
+	return nil
+}
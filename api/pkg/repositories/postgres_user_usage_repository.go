@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/palantir/stacktrace"
+)
+
+// postgresUserUsageRepository is responsible for persisting entities.UserUsage
+type postgresUserUsageRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *pgxpool.Pool
+}
+
+// NewPostgresUserUsageRepository creates the PostgreSQL version of the UserUsageRepository
+func NewPostgresUserUsageRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *pgxpool.Pool,
+) UserUsageRepository {
+	return &postgresUserUsageRepository{
+		logger: logger.WithCodeNamespace(fmt.Sprintf("%T", &postgresUserUsageRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+func (repository *postgresUserUsageRepository) IncrementRequestCount(ctx context.Context, userID entities.UserID, periodStart time.Time) (uint, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := "INSERT INTO user_usages (user_id, period_start, request_count, updated_at) VALUES ($1, $2, 1, $3) " +
+		"ON CONFLICT (user_id, period_start) DO UPDATE SET request_count = user_usages.request_count + 1, updated_at = EXCLUDED.updated_at " +
+		"RETURNING request_count"
+
+	var count uint
+	if err := repository.db.QueryRow(ctx, query, string(userID), periodStart.UTC(), time.Now().UTC()).Scan(&count); err != nil {
+		msg := fmt.Sprintf("cannot increment request count for user with ID [%s] and period [%s]", userID, periodStart)
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return count, nil
+}
+
+func (repository *postgresUserUsageRepository) Load(ctx context.Context, userID entities.UserID, periodStart time.Time) (*entities.UserUsage, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	usage := new(entities.UserUsage)
+	err := repository.db.QueryRow(
+		ctx,
+		"SELECT user_id, period_start, request_count, updated_at FROM user_usages WHERE user_id = $1 AND period_start = $2",
+		string(userID),
+		periodStart.UTC(),
+	).Scan(&usage.UserID, &usage.PeriodStart, &usage.RequestCount, &usage.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		msg := fmt.Sprintf("usage of user with ID [%s] does not exist for period [%s]", userID, periodStart)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, ErrCodeNotFound, msg))
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot load usage of user with ID [%s] for period [%s]", userID, periodStart)
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return usage, nil
+}
+
+func (repository *postgresUserUsageRepository) DeleteByUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if _, err := repository.db.Exec(ctx, "DELETE FROM user_usages WHERE user_id = $1", string(userID)); err != nil {
+		msg := fmt.Sprintf("cannot delete usages of user with ID [%s]", userID)
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
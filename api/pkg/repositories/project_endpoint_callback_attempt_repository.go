@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+)
+
+// ProjectEndpointCallbackAttemptRepository loads and persists an entities.ProjectEndpointCallbackAttempt
+type ProjectEndpointCallbackAttemptRepository interface {
+	// Store a new entities.ProjectEndpointCallbackAttempt
+	Store(ctx context.Context, attempt *entities.ProjectEndpointCallbackAttempt) error
+
+	// Index fetches the entities.ProjectEndpointCallbackAttempt of an entities.ProjectEndpointRequest ordered from the oldest
+	Index(ctx context.Context, userID entities.UserID, requestID string) ([]*entities.ProjectEndpointCallbackAttempt, error)
+
+	// DeleteByRequest deletes all the entities.ProjectEndpointCallbackAttempt of an entities.ProjectEndpointRequest
+	DeleteByRequest(ctx context.Context, userID entities.UserID, requestID string) error
+
+	// DeleteByProject deletes at most limit entities.ProjectEndpointCallbackAttempt of a project and returns the number of deleted attempts
+	DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/matchers"
 )
 
 // ProjectEndpointRepository loads and persists an entities.ProjectEndpoint
@@ -19,6 +20,9 @@ type ProjectEndpointRepository interface {
 	// IncreaseRequestCount increases the request count for an entities.ProjectEndpoint
 	IncreaseRequestCount(ctx context.Context, projectEndpointID uuid.UUID) error
 
+	// IncreaseResponseCounter increases the response counter of an entities.ProjectEndpoint and returns the new value
+	IncreaseResponseCounter(ctx context.Context, projectEndpointID uuid.UUID) (uint, error)
+
 	// DecreaseRequestCount reduces a request count for an entities.ProjectEndpoint
 	DecreaseRequestCount(ctx context.Context, projectEndpointID uuid.UUID) error
 
@@ -34,9 +38,12 @@ type ProjectEndpointRepository interface {
 	// Delete an entities.ProjectEndpoint
 	Delete(ctx context.Context, endpoint *entities.ProjectEndpoint) error
 
-	// LoadByRequestForUser load an entities.ProjectEndpoint by a request path and method.
-	LoadByRequestForUser(ctx context.Context, userID entities.UserID, projectID uuid.UUID, requestMethod, requestPath string) (*entities.ProjectEndpoint, error)
+	// DeleteByProject deletes up to limit entities.ProjectEndpoint of a project and returns the number of deleted endpoints
+	DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error)
+
+	// LoadConflicting load another entities.ProjectEndpoint in the same project which cannot be distinguished from the endpoint
+	LoadConflicting(ctx context.Context, endpoint *entities.ProjectEndpoint) (*entities.ProjectEndpoint, error)
 
-	// LoadByRequest load an entities.ProjectEndpoint by a http path and method.
-	LoadByRequest(ctx context.Context, subdomain, requestMethod, requestPath string) (*entities.ProjectEndpoint, error)
+	// LoadByRequest load the best entities.ProjectEndpoint which matches the http method, path and request conditions.
+	LoadByRequest(ctx context.Context, subdomain string, request *matchers.Request) (*entities.ProjectEndpoint, error)
 }
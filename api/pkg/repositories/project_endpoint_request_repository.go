@@ -2,13 +2,63 @@ package repositories
 
 import (
 	"context"
+	"regexp"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/oklog/ulid/v2"
 
 	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/matchers"
 )
 
+// ProjectEndpointRequestSearchParams are the filters used to search the entities.ProjectEndpointRequest of a project.
+// Empty filters are ignored and the requests are paginated with the ULID of the previous or next request like in Index.
+type ProjectEndpointRequestSearchParams struct {
+	ProjectID uuid.UUID
+
+	// Method is the HTTP method of the request e.g. POST
+	Method string
+
+	// ResponseCode is the status code of the response e.g. 404
+	ResponseCode *uint
+
+	// PathPrefix matches the requests whose URL path starts with the prefix e.g. /v1/products
+	PathPrefix string
+
+	// IPAddress is the IP address of the client
+	IPAddress string
+
+	// From and To select the requests created at or after From and before To
+	From *time.Time
+	To   *time.Time
+
+	// HeaderKey matches the requests with a header whose name is equal to the key ignoring the case.
+	// When HeaderValue is set, the value of the header must also be equal to it.
+	HeaderKey   string
+	HeaderValue *string
+
+	// Body matches the requests whose body contains the text ignoring the case
+	Body string
+
+	// JSONPath matches the requests with a JSON body in which the expression selects a value.
+	// When JSONValue is set, one of the selected values formatted as a string must also be equal to it.
+	JSONPath  *matchers.JSONPath
+	JSONValue *string
+
+	// Unmatched selects the requests which did not match any entities.ProjectEndpoint when it is true or the requests which matched one when it is false
+	Unmatched *bool
+
+	Limit      uint
+	PreviousID *ulid.ULID
+	NextID     *ulid.ULID
+}
+
+// pathPrefixPattern returns the regular expression which matches the request URLs with the PathPrefix
+func (params *ProjectEndpointRequestSearchParams) pathPrefixPattern() string {
+	return "^[a-zA-Z][a-zA-Z0-9+.-]*://[^/?#]*" + regexp.QuoteMeta(params.PathPrefix)
+}
+
 // ProjectEndpointRequestRepository loads and persists an entities.ProjectEndpointRequests
 type ProjectEndpointRequestRepository interface {
 	// Store a new entities.ProjectEndpointRequest
@@ -17,6 +67,12 @@ type ProjectEndpointRequestRepository interface {
 	// Delete an entities.ProjectEndpointRequest
 	Delete(ctx context.Context, request *entities.ProjectEndpointRequest) error
 
+	// DeleteByProject deletes up to limit entities.ProjectEndpointRequest of a project and returns the number of deleted requests
+	DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error)
+
+	// DeleteBefore deletes up to limit entities.ProjectEndpointRequest of a user which were created before a time and returns the deleted requests
+	DeleteBefore(ctx context.Context, userID entities.UserID, before time.Time, limit uint) ([]*entities.ProjectEndpointRequest, error)
+
 	// Load an entities.ProjectEndpointRequest by its ID
 	Load(ctx context.Context, userID entities.UserID, requestID ulid.ULID) (*entities.ProjectEndpointRequest, error)
 
@@ -26,6 +82,12 @@ type ProjectEndpointRequestRepository interface {
 	// GetEndpointTraffic Traffic fetches the traffic data for a project endpoint
 	GetEndpointTraffic(ctx context.Context, userID entities.UserID, endpointID uuid.UUID) ([]*TimeSeriesData, error)
 
+	// FetchLatest fetches the latest entities.ProjectEndpointRequest for a project ordered from the newest request
+	FetchLatest(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) ([]*entities.ProjectEndpointRequest, error)
+
 	// Index fetches the list of all project endpoint requests available to the currently authenticated user
 	Index(ctx context.Context, userID entities.UserID, endpointID uuid.UUID, limit uint, previousID *ulid.ULID, nextID *ulid.ULID) ([]*entities.ProjectEndpointRequest, error)
+
+	// Search fetches the entities.ProjectEndpointRequest of all the endpoints of a project which match the ProjectEndpointRequestSearchParams
+	Search(ctx context.Context, userID entities.UserID, params *ProjectEndpointRequestSearchParams) ([]*entities.ProjectEndpointRequest, error)
 }
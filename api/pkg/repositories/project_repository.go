@@ -25,6 +25,12 @@ type ProjectRepository interface {
 	// Delete an entities.Project by entities.UserID and projectID
 	Delete(ctx context.Context, userID entities.UserID, projectID uuid.UUID) error
 
+	// UpdateScenarioState sets the current state of a scenario in an entities.Project
+	UpdateScenarioState(ctx context.Context, projectID uuid.UUID, scenario string, state string) error
+
+	// ResetScenarioStates resets all the scenarios in an entities.Project to entities.ProjectScenarioStateStarted
+	ResetScenarioStates(ctx context.Context, projectID uuid.UUID) error
+
 	// LoadWithSubdomain load an entities.Project by a subdomain
 	LoadWithSubdomain(ctx context.Context, subdomain string) (*entities.Project, error)
 }
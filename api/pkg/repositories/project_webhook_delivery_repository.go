@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+)
+
+// ProjectWebhookDeliveryRepository loads and persists an entities.ProjectWebhookDelivery
+type ProjectWebhookDeliveryRepository interface {
+	// Store a new entities.ProjectWebhookDelivery
+	Store(ctx context.Context, delivery *entities.ProjectWebhookDelivery) error
+
+	// Index fetches at most limit entities.ProjectWebhookDelivery of an entities.ProjectWebhook ordered from the newest
+	Index(ctx context.Context, userID entities.UserID, webhookID uuid.UUID, limit uint) ([]*entities.ProjectWebhookDelivery, error)
+
+	// DeleteByWebhook deletes all the entities.ProjectWebhookDelivery of an entities.ProjectWebhook
+	DeleteByWebhook(ctx context.Context, userID entities.UserID, webhookID uuid.UUID) error
+
+	// DeleteByProject deletes at most limit entities.ProjectWebhookDelivery of a project and returns the number of deleted deliveries
+	DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error)
+
+	// DeleteBefore deletes at most limit entities.ProjectWebhookDelivery of a user which were created before a time
+	DeleteBefore(ctx context.Context, userID entities.UserID, before time.Time, limit uint) (uint, error)
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+)
+
+// ProjectWebhookRepository loads and persists an entities.ProjectWebhook
+type ProjectWebhookRepository interface {
+	// Store a new entities.ProjectWebhook
+	Store(ctx context.Context, webhook *entities.ProjectWebhook) error
+
+	// Update an entities.ProjectWebhook
+	Update(ctx context.Context, webhook *entities.ProjectWebhook) error
+
+	// Index fetches the entities.ProjectWebhook of a project ordered from the oldest
+	Index(ctx context.Context, userID entities.UserID, projectID uuid.UUID) ([]*entities.ProjectWebhook, error)
+
+	// Load an entities.ProjectWebhook of a project
+	Load(ctx context.Context, userID entities.UserID, projectID uuid.UUID, webhookID uuid.UUID) (*entities.ProjectWebhook, error)
+
+	// Delete an entities.ProjectWebhook of a project
+	Delete(ctx context.Context, userID entities.UserID, projectID uuid.UUID, webhookID uuid.UUID) error
+
+	// DeleteByProject deletes at most limit entities.ProjectWebhook of a project and returns the number of deleted webhooks
+	DeleteByProject(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error)
+}
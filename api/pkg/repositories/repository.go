@@ -1,8 +1,10 @@
 package repositories
 
 import (
+	"sort"
 	"time"
 
+	"github.com/oklog/ulid/v2"
 	"github.com/palantir/stacktrace"
 )
 
@@ -25,3 +27,44 @@ const (
 	// ErrCodeNotFound is thrown when an entity does not exist in storage
 	ErrCodeNotFound = stacktrace.ErrorCode(1000)
 )
+
+// requestIDBefore returns the smallest ULID of an entities.ProjectEndpointRequest created at a time.
+// ULIDs are sorted by time so every request created before that time has a smaller ID.
+func requestIDBefore(before time.Time) string {
+	var id ulid.ULID
+	_ = id.SetTime(ulid.Timestamp(before))
+	return id.String()
+}
+
+func generateTimeSeries() map[string]*TimeSeriesData {
+	series := make(map[string]*TimeSeriesData)
+	for i := 0; i < 30; i++ {
+		date := time.Now().UTC().AddDate(0, 0, -i)
+		series[date.Format("2006-01-02")] = &TimeSeriesData{
+			Timestamp: time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -i),
+			Count:     0,
+		}
+	}
+	return series
+}
+
+func normalizeTimeSeries(input []*TimeSeriesData) []*TimeSeriesData {
+	series := generateTimeSeries()
+	for _, data := range input {
+		date := data.Timestamp.Format("2006-01-02")
+		if _, ok := series[date]; ok {
+			series[date].Count = data.Count
+		}
+	}
+
+	var result []*TimeSeriesData
+	for _, data := range series {
+		result = append(result, data)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+
+	return result
+}
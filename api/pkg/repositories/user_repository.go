@@ -22,4 +22,7 @@ type UserRepository interface {
 
 	// LoadOrStore an entities.User by entities.AuthUser
 	LoadOrStore(ctx context.Context, user entities.AuthUser) (*entities.User, bool, error)
+
+	// Delete an entities.User by entities.UserID
+	Delete(ctx context.Context, userID entities.UserID) error
 }
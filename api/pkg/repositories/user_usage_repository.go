@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+)
+
+// UserUsageRepository loads and persists an entities.UserUsage
+type UserUsageRepository interface {
+	// IncrementRequestCount atomically increases the request count of a user in a period and returns the new count
+	IncrementRequestCount(ctx context.Context, userID entities.UserID, periodStart time.Time) (uint, error)
+
+	// Load the entities.UserUsage of a user in a period
+	Load(ctx context.Context, userID entities.UserID, periodStart time.Time) (*entities.UserUsage, error)
+
+	// DeleteByUser deletes all the entities.UserUsage of a user
+	DeleteByUser(ctx context.Context, userID entities.UserID) error
+}
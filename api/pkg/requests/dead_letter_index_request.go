@@ -0,0 +1,26 @@
+package requests
+
+import "github.com/NdoleStudio/httpmock/pkg/entities"
+
+// DeadLetterIndexRequest is the payload fetching entities.DeadLetter
+type DeadLetterIndexRequest struct {
+	request
+
+	Status string `json:"status" query:"status"`
+	Limit  uint   `json:"limit" query:"limit"`
+	Skip   uint   `json:"skip" query:"skip"`
+}
+
+// Sanitize the request by stripping whitespaces
+func (input *DeadLetterIndexRequest) Sanitize() *DeadLetterIndexRequest {
+	if input.Limit == 0 {
+		input.Limit = 100
+	}
+	input.Status = input.sanitizeString(input.Status)
+	return input
+}
+
+// DeadLetterStatus returns the status as entities.DeadLetterStatus
+func (input *DeadLetterIndexRequest) DeadLetterStatus() entities.DeadLetterStatus {
+	return entities.DeadLetterStatus(input.Status)
+}
@@ -0,0 +1,77 @@
+package requests
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/exporters"
+	"github.com/palantir/stacktrace"
+)
+
+// ProjectBundleApplyRequest is the payload to apply a project bundle to a project
+type ProjectBundleApplyRequest struct {
+	request
+	ProjectID string `json:"projectId" swaggerignore:"true"`
+	DryRun    bool   `json:"dry_run" query:"dry_run"`
+	Document  string `json:"document"`
+}
+
+// Sanitize the request by stripping whitespaces
+func (input *ProjectBundleApplyRequest) Sanitize() *ProjectBundleApplyRequest {
+	input.Document = input.sanitizeString(input.Document)
+	return input
+}
+
+// ToProjectEndpointStoreRequests creates a ProjectEndpointStoreRequest for every endpoint in the bundle
+func (input *ProjectBundleApplyRequest) ToProjectEndpointStoreRequests() ([]*ProjectEndpointStoreRequest, error) {
+	bundle, err := exporters.ParseProjectBundle([]byte(input.Document))
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot parse the bundle for project [%s]", input.ProjectID))
+	}
+
+	result := make([]*ProjectEndpointStoreRequest, 0, len(bundle.Endpoints))
+	for _, endpoint := range bundle.Endpoints {
+		if endpoint == nil {
+			continue
+		}
+
+		request := &ProjectEndpointStoreRequest{
+			ProjectID:                   input.ProjectID,
+			RequestMethod:               endpoint.RequestMethod,
+			RequestPath:                 endpoint.RequestPath,
+			RequestConditions:           endpoint.RequestConditions,
+			ScenarioName:                endpoint.ScenarioName,
+			ScenarioRequiredState:       endpoint.ScenarioRequiredState,
+			ScenarioNewState:            endpoint.ScenarioNewState,
+			Priority:                    endpoint.Priority,
+			ResponseCode:                endpoint.ResponseCode,
+			ResponseBody:                endpoint.ResponseBody,
+			ResponseHeaders:             endpoint.ResponseHeaders,
+			Responses:                   endpoint.Responses,
+			ResponseMode:                endpoint.ResponseMode,
+			ResponseTemplateEnabled:     endpoint.ResponseTemplateEnabled,
+			ResponseDelayInMilliseconds: endpoint.ResponseDelayInMilliseconds,
+			Faults:                      endpoint.Faults,
+			Callbacks:                   endpoint.Callbacks,
+			Description:                 endpoint.Description,
+		}
+		result = append(result, request.Sanitize())
+	}
+
+	return result, nil
+}
+
+// ProjectBundleExportRequest is the payload to export a project bundle
+type ProjectBundleExportRequest struct {
+	request
+	ProjectID string `json:"projectId" swaggerignore:"true"`
+	Format    string `json:"format" query:"format"`
+}
+
+// Sanitize the request by stripping whitespaces
+func (input *ProjectBundleExportRequest) Sanitize() *ProjectBundleExportRequest {
+	input.Format = input.sanitizeString(input.Format)
+	if input.Format == "" {
+		input.Format = "json"
+	}
+	return input
+}
@@ -13,6 +13,7 @@ type ProjectCreateRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Subdomain   string `json:"subdomain"`
+	UpstreamURL string `json:"upstream_url"`
 }
 
 // Sanitize the request by stripping whitespaces
@@ -20,6 +21,7 @@ func (request *ProjectCreateRequest) Sanitize() *ProjectCreateRequest {
 	request.Name = request.sanitizeString(request.Name)
 	request.Description = request.sanitizeString(request.Description)
 	request.Subdomain = strings.TrimSuffix(request.sanitizeString(request.Subdomain), ".httpmock.dev")
+	request.UpstreamURL = strings.TrimRight(request.sanitizeString(request.UpstreamURL), "/")
 	return request
 }
 
@@ -29,6 +31,7 @@ func (request *ProjectCreateRequest) ToProjectCreateParams(source string, userID
 		Name:        request.Name,
 		Description: request.Description,
 		Subdomain:   request.Subdomain,
+		UpstreamURL: request.UpstreamURL,
 		UserID:      userID,
 		Source:      source,
 	}
@@ -1,6 +1,7 @@
 package requests
 
 import (
+	"net/http"
 	"strings"
 
 	"github.com/NdoleStudio/httpmock/pkg/entities"
@@ -13,13 +14,23 @@ type ProjectEndpointStoreRequest struct {
 	request
 	ProjectID string `json:"projectId" swaggerignore:"true"`
 
-	RequestMethod               string `json:"request_method"`
-	RequestPath                 string `json:"request_path"`
-	ResponseCode                uint   `json:"response_code"`
-	ResponseBody                string `json:"response_body"`
-	ResponseHeaders             string `json:"response_headers"`
-	ResponseDelayInMilliseconds uint   `json:"response_delay_in_milliseconds"`
-	Description                 string `json:"description"`
+	RequestMethod               string                               `json:"request_method"`
+	RequestPath                 string                               `json:"request_path"`
+	RequestConditions           []*entities.ProjectEndpointCondition `json:"request_conditions"`
+	ScenarioName                string                               `json:"scenario_name"`
+	ScenarioRequiredState       string                               `json:"scenario_required_state"`
+	ScenarioNewState            string                               `json:"scenario_new_state"`
+	Priority                    uint                                 `json:"priority"`
+	ResponseCode                uint                                 `json:"response_code"`
+	ResponseBody                string                               `json:"response_body"`
+	ResponseHeaders             string                               `json:"response_headers"`
+	Responses                   []*entities.ProjectEndpointResponse  `json:"responses"`
+	ResponseMode                string                               `json:"response_mode"`
+	ResponseTemplateEnabled     bool                                 `json:"response_template_enabled"`
+	ResponseDelayInMilliseconds uint                                 `json:"response_delay_in_milliseconds"`
+	Faults                      []*entities.ProjectEndpointFault     `json:"faults"`
+	Callbacks                   []*entities.ProjectEndpointCallback  `json:"callbacks"`
+	Description                 string                               `json:"description"`
 }
 
 // Sanitize the request by stripping whitespaces
@@ -29,6 +40,53 @@ func (request *ProjectEndpointStoreRequest) Sanitize() *ProjectEndpointStoreRequ
 	request.ResponseBody = request.sanitizeString(request.ResponseBody)
 	request.ResponseHeaders = request.sanitizeString(request.ResponseHeaders)
 	request.Description = request.sanitizeString(request.Description)
+	request.ResponseMode = strings.ToLower(request.sanitizeString(request.ResponseMode))
+	if len(request.Responses) > 0 && request.ResponseMode == "" {
+		request.ResponseMode = string(entities.ProjectEndpointResponseModeRoundRobin)
+	}
+
+	for _, fault := range request.Faults {
+		if fault != nil {
+			fault.Type = entities.ProjectEndpointFaultType(strings.ToLower(request.sanitizeString(string(fault.Type))))
+			fault.LatencyDistribution = entities.ProjectEndpointLatencyDistribution(strings.ToLower(request.sanitizeString(string(fault.LatencyDistribution))))
+			if fault.Type == entities.ProjectEndpointFaultTypeLatency && fault.LatencyDistribution == "" {
+				fault.LatencyDistribution = entities.ProjectEndpointLatencyDistributionUniform
+			}
+		}
+	}
+
+	for _, callback := range request.Callbacks {
+		if callback != nil {
+			callback.URL = request.sanitizeString(callback.URL)
+			callback.Method = strings.ToUpper(request.sanitizeString(callback.Method))
+			if callback.Method == "" {
+				callback.Method = http.MethodPost
+			}
+			if callback.Headers != nil {
+				headers := request.sanitizeString(*callback.Headers)
+				callback.Headers = &headers
+			}
+		}
+	}
+
+	for _, response := range request.Responses {
+		if response != nil && response.ResponseHeaders != nil {
+			headers := request.sanitizeString(*response.ResponseHeaders)
+			response.ResponseHeaders = &headers
+		}
+	}
+	request.ScenarioName = request.sanitizeString(request.ScenarioName)
+	request.ScenarioRequiredState = request.sanitizeString(request.ScenarioRequiredState)
+	request.ScenarioNewState = request.sanitizeString(request.ScenarioNewState)
+
+	for _, condition := range request.RequestConditions {
+		if condition == nil {
+			continue
+		}
+		condition.Source = entities.ProjectEndpointConditionSource(strings.ToLower(request.sanitizeString(string(condition.Source))))
+		condition.Operator = entities.ProjectEndpointConditionOperator(strings.ToLower(request.sanitizeString(string(condition.Operator))))
+		condition.Key = request.sanitizeString(condition.Key)
+	}
 
 	return request
 }
@@ -38,10 +96,20 @@ func (request *ProjectEndpointStoreRequest) ToProjectEndpointStorePrams(userID e
 	return &services.ProjectEndpointStoreParams{
 		RequestMethod:               request.RequestMethod,
 		RequestPath:                 request.RequestPath,
+		RequestConditions:           request.RequestConditions,
+		ScenarioName:                request.ScenarioName,
+		ScenarioRequiredState:       request.ScenarioRequiredState,
+		ScenarioNewState:            request.ScenarioNewState,
+		Priority:                    request.Priority,
 		ResponseCode:                request.ResponseCode,
 		ResponseBody:                &request.ResponseBody,
 		ResponseHeaders:             &request.ResponseHeaders,
+		Responses:                   request.Responses,
+		ResponseMode:                entities.ProjectEndpointResponseMode(request.ResponseMode),
+		ResponseTemplateEnabled:     request.ResponseTemplateEnabled,
 		ResponseDelayInMilliseconds: request.ResponseDelayInMilliseconds,
+		Faults:                      request.Faults,
+		Callbacks:                   request.Callbacks,
 		Description:                 &request.Description,
 		ProjectID:                   uuid.MustParse(request.ProjectID),
 		UserID:                      userID,
@@ -0,0 +1,71 @@
+package requests
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/openapi"
+	"github.com/palantir/stacktrace"
+)
+
+// maxImportDescriptionLength is the maximum length of the description of an imported endpoint
+const maxImportDescriptionLength = 500
+
+// ProjectEndpointImportRequest is the payload to import the endpoints of an OpenAPI 3.x or Swagger 2.0 document
+type ProjectEndpointImportRequest struct {
+	request
+	ProjectID string `json:"projectId" swaggerignore:"true"`
+	Document  string `json:"document"`
+
+	// endpoints are the requests created from the Document, it is parsed once by the validator and by the handler
+	endpoints []*ProjectEndpointStoreRequest
+}
+
+// Sanitize the request by stripping whitespaces
+func (request *ProjectEndpointImportRequest) Sanitize() *ProjectEndpointImportRequest {
+	request.Document = request.sanitizeString(request.Document)
+	return request
+}
+
+// ToProjectEndpointStoreRequests creates a ProjectEndpointStoreRequest for every operation in the document.
+// The document is parsed only once and the same requests are returned when this method is called again.
+func (request *ProjectEndpointImportRequest) ToProjectEndpointStoreRequests() ([]*ProjectEndpointStoreRequest, error) {
+	if request.endpoints != nil {
+		return request.endpoints, nil
+	}
+
+	document, err := openapi.Parse([]byte(request.Document))
+	if err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot parse the OpenAPI document for project [%s]", request.ProjectID))
+	}
+
+	result := []*ProjectEndpointStoreRequest{}
+	for _, endpoint := range document.Endpoints() {
+		storeRequest := &ProjectEndpointStoreRequest{
+			ProjectID:     request.ProjectID,
+			RequestMethod: endpoint.RequestMethod,
+			RequestPath:   endpoint.RequestPath,
+			ResponseCode:  endpoint.ResponseCode,
+			ResponseBody:  endpoint.ResponseBody,
+			Description:   request.truncate(endpoint.Description, maxImportDescriptionLength),
+		}
+
+		if endpoint.ContentType != "" {
+			headers, _ := json.Marshal([]map[string]string{{"Content-Type": endpoint.ContentType}})
+			storeRequest.ResponseHeaders = string(headers)
+		}
+
+		result = append(result, storeRequest.Sanitize())
+	}
+
+	request.endpoints = result
+	return result, nil
+}
+
+func (request *ProjectEndpointImportRequest) truncate(value string, length int) string {
+	runes := []rune(request.sanitizeString(value))
+	if len(runes) <= length {
+		return string(runes)
+	}
+	return string(runes[:length])
+}
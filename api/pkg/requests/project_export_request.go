@@ -0,0 +1,36 @@
+package requests
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NdoleStudio/httpmock/pkg/services"
+)
+
+// ProjectExportRequest is the payload for exporting a project
+type ProjectExportRequest struct {
+	request
+	ProjectID string `json:"projectId" swaggerignore:"true"`
+	Format    string `json:"format" query:"format"`
+}
+
+// Sanitize the request by stripping whitespaces
+func (input *ProjectExportRequest) Sanitize() *ProjectExportRequest {
+	input.Format = strings.ToLower(input.sanitizeString(input.Format))
+	if input.Format == "" {
+		input.Format = string(services.ProjectExportFormatOpenAPI)
+	}
+	return input
+}
+
+// Filename is the name of the exported file
+func (input *ProjectExportRequest) Filename() string {
+	switch services.ProjectExportFormat(input.Format) {
+	case services.ProjectExportFormatPostman:
+		return fmt.Sprintf("%s.postman_collection.json", input.ProjectID)
+	case services.ProjectExportFormatHAR:
+		return fmt.Sprintf("%s.har", input.ProjectID)
+	default:
+		return fmt.Sprintf("%s.openapi.json", input.ProjectID)
+	}
+}
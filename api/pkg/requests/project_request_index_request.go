@@ -0,0 +1,41 @@
+package requests
+
+import (
+	"github.com/NdoleStudio/httpmock/pkg/repositories"
+	"github.com/google/uuid"
+)
+
+// ProjectRequestIndexRequest is the payload fetching the entities.ProjectEndpointRequest of all the endpoints of a project
+type ProjectRequestIndexRequest struct {
+	request
+
+	Unmatched string `json:"unmatched" query:"unmatched"`
+
+	Prev  string `json:"prev" query:"prev"`
+	Next  string `json:"next" query:"next"`
+	Limit uint   `json:"limit" query:"limit"`
+
+	ProjectID string `json:"projectId" swaggerignore:"true"`
+}
+
+// Sanitize the request by stripping whitespaces
+func (input *ProjectRequestIndexRequest) Sanitize() *ProjectRequestIndexRequest {
+	if input.Limit == 0 {
+		input.Limit = 100
+	}
+	input.Unmatched = input.sanitizeString(input.Unmatched)
+	input.Prev = input.sanitizeString(input.Prev)
+	input.Next = input.sanitizeString(input.Next)
+	return input
+}
+
+// ToSearchParams converts ProjectRequestIndexRequest to repositories.ProjectEndpointRequestSearchParams
+func (input *ProjectRequestIndexRequest) ToSearchParams() *repositories.ProjectEndpointRequestSearchParams {
+	return &repositories.ProjectEndpointRequestSearchParams{
+		ProjectID:  uuid.MustParse(input.ProjectID),
+		Unmatched:  parseBool(input.Unmatched),
+		Limit:      input.Limit,
+		PreviousID: parseULID(input.Prev),
+		NextID:     parseULID(input.Next),
+	}
+}
@@ -0,0 +1,100 @@
+package requests
+
+import (
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/matchers"
+	"github.com/NdoleStudio/httpmock/pkg/repositories"
+	"github.com/google/uuid"
+)
+
+// ProjectRequestSearchRequest is the payload for searching the entities.ProjectEndpointRequest of a project
+type ProjectRequestSearchRequest struct {
+	request
+
+	Method       string `json:"method" query:"method"`
+	ResponseCode uint   `json:"response_code" query:"response_code"`
+	PathPrefix   string `json:"path_prefix" query:"path_prefix"`
+	IPAddress    string `json:"ip_address" query:"ip_address"`
+	From         string `json:"from" query:"from"`
+	To           string `json:"to" query:"to"`
+	HeaderKey    string `json:"header_key" query:"header_key"`
+	HeaderValue  string `json:"header_value" query:"header_value"`
+	Query        string `json:"query" query:"query"`
+	JSONPath     string `json:"json_path" query:"json_path"`
+	JSONValue    string `json:"json_value" query:"json_value"`
+	Unmatched    string `json:"unmatched" query:"unmatched"`
+
+	Prev  string `json:"prev" query:"prev"`
+	Next  string `json:"next" query:"next"`
+	Limit uint   `json:"limit" query:"limit"`
+
+	ProjectID string `json:"projectId" swaggerignore:"true"`
+}
+
+// Sanitize the request by stripping whitespaces
+func (input *ProjectRequestSearchRequest) Sanitize() *ProjectRequestSearchRequest {
+	if input.Limit == 0 {
+		input.Limit = 100
+	}
+	input.Method = strings.ToUpper(input.sanitizeString(input.Method))
+	input.PathPrefix = input.sanitizeString(input.PathPrefix)
+	input.IPAddress = input.sanitizeString(input.IPAddress)
+	input.From = input.sanitizeString(input.From)
+	input.To = input.sanitizeString(input.To)
+	input.HeaderKey = input.sanitizeString(input.HeaderKey)
+	input.JSONPath = input.sanitizeString(input.JSONPath)
+	input.Unmatched = input.sanitizeString(input.Unmatched)
+	input.Prev = input.sanitizeString(input.Prev)
+	input.Next = input.sanitizeString(input.Next)
+	return input
+}
+
+// ToSearchParams converts ProjectRequestSearchRequest to repositories.ProjectEndpointRequestSearchParams
+func (input *ProjectRequestSearchRequest) ToSearchParams() *repositories.ProjectEndpointRequestSearchParams {
+	params := &repositories.ProjectEndpointRequestSearchParams{
+		ProjectID:  uuid.MustParse(input.ProjectID),
+		Method:     input.Method,
+		PathPrefix: input.PathPrefix,
+		IPAddress:  input.IPAddress,
+		From:       input.parseTime(input.From),
+		To:         input.parseTime(input.To),
+		HeaderKey:  input.HeaderKey,
+		Body:       input.Query,
+		Unmatched:  parseBool(input.Unmatched),
+		Limit:      input.Limit,
+		PreviousID: parseULID(input.Prev),
+		NextID:     parseULID(input.Next),
+	}
+
+	if input.ResponseCode != 0 {
+		params.ResponseCode = &input.ResponseCode
+	}
+
+	if input.HeaderValue != "" {
+		params.HeaderValue = &input.HeaderValue
+	}
+
+	if path, err := matchers.ParseJSONPath(input.JSONPath); err == nil {
+		params.JSONPath = path
+		if input.JSONValue != "" {
+			params.JSONValue = &input.JSONValue
+		}
+	}
+
+	return params
+}
+
+func (input *ProjectRequestSearchRequest) parseTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+
+	result, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+
+	return &result
+}
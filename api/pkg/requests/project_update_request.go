@@ -1,6 +1,8 @@
 package requests
 
 import (
+	"strings"
+
 	"github.com/NdoleStudio/httpmock/pkg/entities"
 	"github.com/NdoleStudio/httpmock/pkg/services"
 	"github.com/google/uuid"
@@ -13,6 +15,7 @@ type ProjectUpdateRequest struct {
 	Name        string `json:"name"`
 	Subdomain   string `json:"subdomain"`
 	Description string `json:"description"`
+	UpstreamURL string `json:"upstream_url"`
 }
 
 // Sanitize the request by stripping whitespaces
@@ -20,6 +23,7 @@ func (request *ProjectUpdateRequest) Sanitize() *ProjectUpdateRequest {
 	request.Name = request.sanitizeString(request.Name)
 	request.Subdomain = request.sanitizeString(request.Subdomain)
 	request.Description = request.sanitizeString(request.Description)
+	request.UpstreamURL = strings.TrimRight(request.sanitizeString(request.UpstreamURL), "/")
 
 	return request
 }
@@ -30,6 +34,7 @@ func (request *ProjectUpdateRequest) ToProjectUpdatePrams(source string, userID
 		Name:        request.Name,
 		Subdomain:   request.Subdomain,
 		Description: request.Description,
+		UpstreamURL: request.UpstreamURL,
 		ProjectID:   uuid.MustParse(request.ProjectID),
 		Source:      source,
 		UserID:      userID,
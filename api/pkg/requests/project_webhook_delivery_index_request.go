@@ -0,0 +1,19 @@
+package requests
+
+// ProjectWebhookDeliveryIndexRequest is the payload fetching entities.ProjectWebhookDelivery
+type ProjectWebhookDeliveryIndexRequest struct {
+	request
+
+	Limit uint `json:"limit" query:"limit"`
+
+	ProjectID        string `json:"projectId" swaggerignore:"true"`
+	ProjectWebhookID string `json:"projectWebhookId" swaggerignore:"true"`
+}
+
+// Sanitize the request by setting the default limit
+func (input *ProjectWebhookDeliveryIndexRequest) Sanitize() *ProjectWebhookDeliveryIndexRequest {
+	if input.Limit == 0 {
+		input.Limit = 20
+	}
+	return input
+}
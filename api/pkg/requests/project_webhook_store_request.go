@@ -0,0 +1,40 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/services"
+	"github.com/google/uuid"
+)
+
+// ProjectWebhookStoreRequest is the payload for creating a project webhook
+type ProjectWebhookStoreRequest struct {
+	request
+	ProjectID     string `json:"projectId" swaggerignore:"true"`
+	URL           string `json:"url" example:"https://ci.example.com/httpmock"`
+	Mode          string `json:"mode" example:"binary"`
+	SigningSecret string `json:"signing_secret" example:"whsec_4f9c71b8b84e44178408a62274f65a08"`
+}
+
+// Sanitize the request by stripping whitespaces
+func (request *ProjectWebhookStoreRequest) Sanitize() *ProjectWebhookStoreRequest {
+	request.URL = request.sanitizeString(request.URL)
+	request.Mode = strings.ToLower(request.sanitizeString(request.Mode))
+	if request.Mode == "" {
+		request.Mode = string(entities.ProjectWebhookModeBinary)
+	}
+	request.SigningSecret = request.sanitizeString(request.SigningSecret)
+	return request
+}
+
+// ToProjectWebhookStoreParams creates services.ProjectWebhookStoreParams from ProjectWebhookStoreRequest
+func (request *ProjectWebhookStoreRequest) ToProjectWebhookStoreParams(userID entities.UserID) *services.ProjectWebhookStoreParams {
+	return &services.ProjectWebhookStoreParams{
+		UserID:        userID,
+		ProjectID:     uuid.MustParse(request.ProjectID),
+		URL:           request.URL,
+		Mode:          entities.ProjectWebhookMode(request.Mode),
+		SigningSecret: request.SigningSecret,
+	}
+}
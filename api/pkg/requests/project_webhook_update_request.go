@@ -0,0 +1,42 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/services"
+	"github.com/google/uuid"
+)
+
+// ProjectWebhookUpdateRequest is the payload for updating a project webhook
+type ProjectWebhookUpdateRequest struct {
+	request
+	ProjectID        string `json:"projectId" swaggerignore:"true"`
+	ProjectWebhookID string `json:"projectWebhookId" swaggerignore:"true"`
+	URL              string `json:"url" example:"https://ci.example.com/httpmock"`
+	Mode             string `json:"mode" example:"structured"`
+	SigningSecret    string `json:"signing_secret" example:"whsec_4f9c71b8b84e44178408a62274f65a08"`
+}
+
+// Sanitize the request by stripping whitespaces
+func (request *ProjectWebhookUpdateRequest) Sanitize() *ProjectWebhookUpdateRequest {
+	request.URL = request.sanitizeString(request.URL)
+	request.Mode = strings.ToLower(request.sanitizeString(request.Mode))
+	if request.Mode == "" {
+		request.Mode = string(entities.ProjectWebhookModeBinary)
+	}
+	request.SigningSecret = request.sanitizeString(request.SigningSecret)
+	return request
+}
+
+// ToProjectWebhookUpdateParams creates services.ProjectWebhookUpdateParams from ProjectWebhookUpdateRequest
+func (request *ProjectWebhookUpdateRequest) ToProjectWebhookUpdateParams(userID entities.UserID) *services.ProjectWebhookUpdateParams {
+	return &services.ProjectWebhookUpdateParams{
+		UserID:           userID,
+		ProjectID:        uuid.MustParse(request.ProjectID),
+		ProjectWebhookID: uuid.MustParse(request.ProjectWebhookID),
+		URL:              request.URL,
+		Mode:             entities.ProjectWebhookMode(request.Mode),
+		SigningSecret:    request.SigningSecret,
+	}
+}
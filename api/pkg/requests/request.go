@@ -3,8 +3,11 @@ package requests
 import (
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 	"unicode"
+
+	"github.com/oklog/ulid/v2"
 )
 
 type request struct{}
@@ -26,3 +29,26 @@ func (request *request) isDigits(value string) bool {
 	}
 	return true
 }
+
+// parseULID returns nil when the value is not a valid ULID
+func parseULID(value string) *ulid.ULID {
+	if value == "" {
+		return nil
+	}
+
+	id, err := ulid.Parse(value)
+	if err != nil {
+		return nil
+	}
+
+	return &id
+}
+
+// parseBool returns nil when the value is not a valid boolean
+func parseBool(value string) *bool {
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil
+	}
+	return &result
+}
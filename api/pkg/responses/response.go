@@ -1,5 +1,11 @@
 package responses
 
+import (
+	"net/url"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+)
+
 type response struct {
 	Status  string `json:"status" example:"success"`
 	Message string `json:"message" example:"item created successfully"`
@@ -38,6 +44,24 @@ type Unauthorized struct {
 	Data    string `json:"data" example:"Make sure your Bearer token is set in the [Bearer] header in the request"`
 }
 
+// Forbidden is the response with status code is 403
+type Forbidden struct {
+	Status  string `json:"status" example:"error"`
+	Message string `json:"message" example:"You are not allowed to carry out this request."`
+}
+
+// PaymentRequired is the response with status code is 402
+type PaymentRequired struct {
+	Status  string `json:"status" example:"error"`
+	Message string `json:"message" example:"You have reached the limit of projects on your subscription. Upgrade your subscription to create more projects."`
+}
+
+// NotImplemented is the response with status code is 501
+type NotImplemented struct {
+	Status  string `json:"status" example:"error"`
+	Message string `json:"message" example:"Streaming requests is only available with the memory, redis or nats queue backends."`
+}
+
 // NoContent is the response when status code is 204
 type NoContent struct {
 	Status  string `json:"status" example:"success"`
@@ -50,3 +74,16 @@ type Ok[T any] struct {
 	Message string `json:"message" example:"Request handled successfully"`
 	Data    T      `json:"data"`
 }
+
+// ProjectEndpointImport is the result of importing an OpenAPI document into a project
+type ProjectEndpointImport struct {
+	Endpoints []*entities.ProjectEndpoint   `json:"endpoints"`
+	Errors    []*ProjectEndpointImportError `json:"errors"`
+}
+
+// ProjectEndpointImportError is an operation in an OpenAPI document which could not be imported
+type ProjectEndpointImportError struct {
+	RequestMethod string     `json:"request_method" example:"GET"`
+	RequestPath   string     `json:"request_path" example:"/v1/products/{id}"`
+	Errors        url.Values `json:"errors" swaggertype:"object"`
+}
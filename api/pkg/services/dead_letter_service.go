@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/repositories"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// DeadLetterService is responsible for managing entities.DeadLetter
+type DeadLetterService struct {
+	service
+	logger          telemetry.Logger
+	tracer          telemetry.Tracer
+	repository      repositories.DeadLetterRepository
+	eventDispatcher *EventDispatcher
+}
+
+// NewDeadLetterService creates a new DeadLetterService
+func NewDeadLetterService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.DeadLetterRepository,
+	eventDispatcher *EventDispatcher,
+) (s *DeadLetterService) {
+	return &DeadLetterService{
+		logger:          logger.WithCodeNamespace(fmt.Sprintf("%T", s)),
+		tracer:          tracer,
+		repository:      repository,
+		eventDispatcher: eventDispatcher,
+	}
+}
+
+// Index fetches the entities.DeadLetter with a status
+func (service *DeadLetterService) Index(ctx context.Context, status entities.DeadLetterStatus, limit uint, skip uint) ([]*entities.DeadLetter, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	deadLetters, err := service.repository.Index(ctx, status, limit, skip)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch dead letters with status [%s]", status)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return deadLetters, nil
+}
+
+// Replay publishes the event of an entities.DeadLetter to the listener which could not handle it.
+// The updated entities.DeadLetter is returned with the error of the listener when the replay fails.
+func (service *DeadLetterService) Replay(ctx context.Context, deadLetterID uuid.UUID) (*entities.DeadLetter, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	deadLetter, err := service.repository.Load(ctx, deadLetterID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load dead letter with ID [%s]", deadLetterID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if deadLetter.Status == entities.DeadLetterStatusReplayed {
+		ctxLogger.Info(fmt.Sprintf("dead letter with ID [%s] was already replayed at [%s]", deadLetter.ID, deadLetter.ReplayedAt))
+		return deadLetter, nil
+	}
+
+	replayErr := service.eventDispatcher.Replay(ctx, deadLetter)
+
+	deadLetter.Attempts++
+	deadLetter.UpdatedAt = time.Now().UTC()
+	if replayErr == nil {
+		deadLetter.Status = entities.DeadLetterStatusReplayed
+		deadLetter.ReplayedAt = &deadLetter.UpdatedAt
+	} else {
+		deadLetter.Error = replayErr.Error()
+	}
+
+	if err = service.repository.Update(ctx, deadLetter); err != nil {
+		msg := fmt.Sprintf("cannot update dead letter with ID [%s] after replaying it", deadLetter.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if replayErr != nil {
+		msg := fmt.Sprintf("cannot replay dead letter with ID [%s] to listener [%s]", deadLetter.ID, deadLetter.Listener)
+		return deadLetter, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(replayErr, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("dead letter with ID [%s] replayed successfully to listener [%s]", deadLetter.ID, deadLetter.Listener))
+	return deadLetter, nil
+}
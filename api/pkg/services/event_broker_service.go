@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/queue"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// ProjectRequestsTopic is the EventBroker topic of the events.ProjectEndpointRequest events of an entities.Project
+func ProjectRequestsTopic(userID entities.UserID, projectID uuid.UUID) string {
+	return fmt.Sprintf("users/%s/projects/%s/requests", userID, projectID)
+}
+
+// EventBroker publishes cloudevents.Event to the subscribers of a topic.
+// The events are relayed to every instance of the application with a queue.PubSub, without it they are only delivered in the same process.
+// A subscriber which does not consume its events fast enough misses the events which do not fit in its buffer.
+type EventBroker struct {
+	logger      telemetry.Logger
+	tracer      telemetry.Tracer
+	pubSub      queue.PubSub
+	channel     string
+	enabled     bool
+	cancel      context.CancelFunc
+	mutex       sync.RWMutex
+	subscribers map[string]map[*eventSubscriber]struct{}
+	closed      bool
+}
+
+// eventBrokerMessage is an event which is relayed to the other instances of the application
+type eventBrokerMessage struct {
+	Topic string            `json:"topic"`
+	Event cloudevents.Event `json:"event"`
+}
+
+type eventSubscriber struct {
+	events chan cloudevents.Event
+	once   sync.Once
+}
+
+func (subscriber *eventSubscriber) close() {
+	subscriber.once.Do(func() { close(subscriber.events) })
+}
+
+// NewEventBroker creates a new EventBroker which relays the events on a queue.PubSub channel when pubSub is not nil.
+// A broker is disabled when the events of a project may be consumed by another instance and there is no queue.PubSub to relay them.
+func NewEventBroker(logger telemetry.Logger, tracer telemetry.Tracer, pubSub queue.PubSub, channel string, enabled bool) (broker *EventBroker) {
+	return &EventBroker{
+		logger:      logger.WithCodeNamespace(fmt.Sprintf("%T", broker)),
+		tracer:      tracer,
+		pubSub:      pubSub,
+		channel:     channel,
+		enabled:     enabled || pubSub != nil,
+		subscribers: map[string]map[*eventSubscriber]struct{}{},
+	}
+}
+
+// Enabled checks if the subscribers receive the events of all the instances of the application
+func (broker *EventBroker) Enabled() bool {
+	return broker.enabled
+}
+
+// Start receiving the events which are relayed by the other instances of the application until the broker is closed
+func (broker *EventBroker) Start(ctx context.Context) error {
+	if broker.pubSub == nil {
+		return nil
+	}
+
+	ctx, broker.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	if err := broker.pubSub.Subscribe(ctx, broker.channel, broker.receive); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot subscribe to the events on channel [%s]", broker.channel))
+	}
+	return nil
+}
+
+// Subscribe to the events of a topic. The channel is closed when the returned function is called or when the broker is closed.
+func (broker *EventBroker) Subscribe(topic string, bufferSize int) (<-chan cloudevents.Event, func()) {
+	subscriber := &eventSubscriber{events: make(chan cloudevents.Event, bufferSize)}
+
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	if broker.closed {
+		subscriber.close()
+		return subscriber.events, func() {}
+	}
+
+	if _, ok := broker.subscribers[topic]; !ok {
+		broker.subscribers[topic] = map[*eventSubscriber]struct{}{}
+	}
+	broker.subscribers[topic][subscriber] = struct{}{}
+
+	return subscriber.events, func() { broker.unsubscribe(topic, subscriber) }
+}
+
+// Publish an event to the subscribers of a topic on all the instances of the application without waiting for them
+func (broker *EventBroker) Publish(ctx context.Context, topic string, event cloudevents.Event) {
+	ctx, span, ctxLogger := broker.tracer.StartWithLogger(ctx, broker.logger)
+	defer span.End()
+
+	if broker.pubSub == nil {
+		broker.deliver(ctxLogger, topic, event)
+		return
+	}
+
+	message, err := json.Marshal(&eventBrokerMessage{Topic: topic, Event: event})
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot marshal [%s] event with ID [%s] for topic [%s]", event.Type(), event.ID(), topic)))
+		return
+	}
+
+	if err = broker.pubSub.Publish(ctx, broker.channel, message); err != nil {
+		msg := fmt.Sprintf("cannot relay [%s] event with ID [%s] on channel [%s], it is only delivered to this instance", event.Type(), event.ID(), broker.channel)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		broker.deliver(ctxLogger, topic, event)
+	}
+}
+
+// receive an event which was relayed by an instance of the application
+func (broker *EventBroker) receive(ctx context.Context, content []byte) {
+	_, span, ctxLogger := broker.tracer.StartWithLogger(ctx, broker.logger)
+	defer span.End()
+
+	var message eventBrokerMessage
+	if err := json.Unmarshal(content, &message); err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("cannot unmarshal [%s] into [%T]", string(content), message)))
+		return
+	}
+
+	broker.deliver(ctxLogger, message.Topic, message.Event)
+}
+
+// deliver an event to the subscribers of a topic in this process
+func (broker *EventBroker) deliver(ctxLogger telemetry.Logger, topic string, event cloudevents.Event) {
+	broker.mutex.RLock()
+	defer broker.mutex.RUnlock()
+
+	for subscriber := range broker.subscribers[topic] {
+		select {
+		case subscriber.events <- event:
+		default:
+			msg := fmt.Sprintf("dropped [%s] event with ID [%s] for a slow subscriber of topic [%s]", event.Type(), event.ID(), topic)
+			ctxLogger.Warn(stacktrace.NewError(msg))
+		}
+	}
+}
+
+// Close the channels of all the subscribers, events which are published afterwards are discarded
+func (broker *EventBroker) Close() {
+	if broker.cancel != nil {
+		broker.cancel()
+	}
+
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	for topic, subscribers := range broker.subscribers {
+		for subscriber := range subscribers {
+			subscriber.close()
+		}
+		delete(broker.subscribers, topic)
+	}
+	broker.closed = true
+}
+
+func (broker *EventBroker) unsubscribe(topic string, subscriber *eventSubscriber) {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	subscriber.close()
+
+	delete(broker.subscribers[topic], subscriber)
+	if len(broker.subscribers[topic]) == 0 {
+		delete(broker.subscribers, topic)
+	}
+}
@@ -3,12 +3,19 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/NdoleStudio/httpmock/pkg/entities"
 	"github.com/NdoleStudio/httpmock/pkg/events"
+	"github.com/NdoleStudio/httpmock/pkg/repositories"
+	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/metric"
 	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
 
@@ -20,34 +27,50 @@ import (
 
 // EventDispatcher dispatches a new event
 type EventDispatcher struct {
-	logger      telemetry.Logger
-	tracer      telemetry.Tracer
-	queue       queue.Client
-	meter       metric.Float64Histogram
-	consumerURL string
-	listeners   map[string][]events.EventListener
+	logger               telemetry.Logger
+	tracer               telemetry.Tracer
+	queue                queue.Client
+	meter                metric.Float64Histogram
+	consumerURL          string
+	listeners            map[string][]events.EventListener
+	deadLetterRepository repositories.DeadLetterRepository
+	listenerMaxAttempts  uint
+	listenerBackoff      time.Duration
 }
 
-// NewEventDispatcher creates a new EventDispatcher
+// NewEventDispatcher creates a new EventDispatcher.
+// A listener which fails is retried listenerMaxAttempts times with an exponential backoff before the event is saved as an entities.DeadLetter
 func NewEventDispatcher(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	meter metric.Float64Histogram,
 	queue queue.Client,
 	consumerURL string,
+	deadLetterRepository repositories.DeadLetterRepository,
+	listenerMaxAttempts uint,
+	listenerBackoff time.Duration,
 ) (dispatcher *EventDispatcher) {
 	return &EventDispatcher{
-		logger:      logger,
-		listeners:   make(map[string][]events.EventListener),
-		tracer:      tracer,
-		meter:       meter,
-		consumerURL: consumerURL,
-		queue:       queue,
+		logger:               logger,
+		listeners:            make(map[string][]events.EventListener),
+		tracer:               tracer,
+		meter:                meter,
+		consumerURL:          consumerURL,
+		queue:                queue,
+		deadLetterRepository: deadLetterRepository,
+		listenerMaxAttempts:  max(listenerMaxAttempts, 1),
+		listenerBackoff:      listenerBackoff,
 	}
 }
 
 // Dispatch a new event by adding it to the queue to be processed async
 func (dispatcher *EventDispatcher) Dispatch(ctx context.Context, event *cloudevents.Event) error {
+	return dispatcher.DispatchAt(ctx, event, time.Time{})
+}
+
+// DispatchAt adds a new event to the queue to be processed async when it is due, a zero time means as soon as possible.
+// The queue holds the event until it is due so no consumer is blocked while waiting.
+func (dispatcher *EventDispatcher) DispatchAt(ctx context.Context, event *cloudevents.Event, dueAt time.Time) error {
 	ctx, span, ctxLogger := dispatcher.tracer.StartWithLogger(ctx, dispatcher.logger)
 	defer span.End()
 
@@ -61,6 +84,7 @@ func (dispatcher *EventDispatcher) Dispatch(ctx context.Context, event *cloudeve
 		msg := fmt.Sprintf("cannot create push queue task for event with ID [%s] and type [%s]", event.ID(), event.Type())
 		return dispatcher.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
+	task.DueAt = dueAt
 
 	taskID, err := dispatcher.queue.Enqueue(ctx, task)
 	if err != nil {
@@ -81,8 +105,40 @@ func (dispatcher *EventDispatcher) Subscribe(eventType string, listener events.E
 	dispatcher.listeners[eventType] = append(dispatcher.listeners[eventType], listener)
 }
 
-// Publish an event to subscribers
+// Publish an event to subscribers. The errors of the subscribers are logged.
 func (dispatcher *EventDispatcher) Publish(ctx context.Context, event cloudevents.Event) {
+	_ = dispatcher.publish(ctx, event)
+}
+
+// Replay an entities.DeadLetter by publishing its event once to the listener which could not handle it
+func (dispatcher *EventDispatcher) Replay(ctx context.Context, deadLetter *entities.DeadLetter) error {
+	ctx, span := dispatcher.tracer.Start(ctx)
+	defer span.End()
+
+	var event cloudevents.Event
+	if err := json.Unmarshal(deadLetter.Event, &event); err != nil {
+		msg := fmt.Sprintf("cannot unmarshall event of dead letter with ID [%s] into [%T]", deadLetter.ID, event)
+		return dispatcher.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	for _, sub := range dispatcher.listeners[event.Type()] {
+		if dispatcher.listenerName(sub) != deadLetter.Listener {
+			continue
+		}
+
+		if err := sub(ctx, event); err != nil {
+			msg := fmt.Sprintf("subscriber [%s] cannot handle event [%s] with ID [%s]", deadLetter.Listener, event.Type(), event.ID())
+			return dispatcher.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		return nil
+	}
+
+	msg := fmt.Sprintf("subscriber [%s] is not listening to event [%s]", deadLetter.Listener, event.Type())
+	return dispatcher.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+}
+
+// publish an event to subscribers and return the errors of the subscribers which could not handle the event and could not be saved as an entities.DeadLetter
+func (dispatcher *EventDispatcher) publish(ctx context.Context, event cloudevents.Event) error {
 	ctx, span, ctxLogger := dispatcher.tracer.StartWithLogger(ctx, dispatcher.logger)
 	defer span.End()
 
@@ -90,19 +146,17 @@ func (dispatcher *EventDispatcher) Publish(ctx context.Context, event cloudevent
 	subscribers, ok := dispatcher.listeners[event.Type()]
 	if !ok {
 		ctxLogger.Info(fmt.Sprintf("no listener is configured for event type [%s] with id [%s]", event.Type(), event.ID()))
-		return
+		return nil
 	}
 
 	var wg sync.WaitGroup
-	for _, sub := range subscribers {
+	failures := make([]error, len(subscribers))
+	for index, sub := range subscribers {
 		wg.Add(1)
-		go func(ctx context.Context, sub events.EventListener) {
-			if err := sub(ctx, event); err != nil {
-				msg := fmt.Sprintf("subscriber [%T] cannot handle event [%s]", sub, event.Type())
-				ctxLogger.Error(stacktrace.Propagate(err, msg))
-			}
+		go func(ctx context.Context, index int, sub events.EventListener) {
+			failures[index] = dispatcher.handle(ctx, ctxLogger, event, sub)
 			wg.Done()
-		}(ctx, sub)
+		}(ctx, index, sub)
 	}
 
 	wg.Wait()
@@ -115,6 +169,126 @@ func (dispatcher *EventDispatcher) Publish(ctx context.Context, event cloudevent
 			semconv.CloudeventsEventSpecVersion(event.SpecVersion()),
 		),
 	)
+
+	return errors.Join(failures...)
+}
+
+// Consume a queue.Task which was created by Dispatch and publish its event to subscribers.
+// An error is returned when a subscriber fails and its entities.DeadLetter cannot be saved so that the queue retries the task,
+// subscribers can therefore receive an event more than once.
+func (dispatcher *EventDispatcher) Consume(ctx context.Context, task *queue.Task) error {
+	ctx, span := dispatcher.tracer.Start(ctx)
+	defer span.End()
+
+	var event cloudevents.Event
+	if err := json.Unmarshal(task.Body, &event); err != nil {
+		msg := fmt.Sprintf("cannot unmarshall [%s] into [%T]", string(task.Body), event)
+		return dispatcher.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := event.Validate(); err != nil {
+		msg := fmt.Sprintf("cannot consume event with ID [%s] and type [%s] because it is invalid", event.ID(), event.Type())
+		return dispatcher.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err := dispatcher.publish(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot publish event with ID [%s] and type [%s] to all subscribers", event.ID(), event.Type())
+		return dispatcher.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// handle an event with a subscriber, the event is saved as an entities.DeadLetter when the subscriber fails on every attempt
+func (dispatcher *EventDispatcher) handle(ctx context.Context, ctxLogger telemetry.Logger, event cloudevents.Event, sub events.EventListener) error {
+	name := dispatcher.listenerName(sub)
+
+	var err error
+	var attempt uint
+	for attempt = 1; attempt <= dispatcher.listenerMaxAttempts; attempt++ {
+		if err = sub(ctx, event); err == nil {
+			return nil
+		}
+
+		msg := fmt.Sprintf("subscriber [%s] cannot handle event [%s] with ID [%s] on attempt [%d]", name, event.Type(), event.ID(), attempt)
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+
+		if attempt == dispatcher.listenerMaxAttempts || !dispatcher.wait(ctx, dispatcher.listenerBackoff<<(attempt-1)) {
+			break
+		}
+	}
+
+	msg := fmt.Sprintf("subscriber [%s] cannot handle event [%s] with ID [%s]", name, event.Type(), event.ID())
+	ctxLogger.Error(stacktrace.Propagate(err, msg))
+
+	if storeErr := dispatcher.storeDeadLetter(ctx, event, name, attempt, err); storeErr != nil {
+		msg = fmt.Sprintf("cannot save dead letter for subscriber [%s] and event [%s] with ID [%s]", name, event.Type(), event.ID())
+		return stacktrace.Propagate(errors.Join(err, storeErr), msg)
+	}
+
+	return nil
+}
+
+func (dispatcher *EventDispatcher) storeDeadLetter(ctx context.Context, event cloudevents.Event, listener string, attempts uint, cause error) error {
+	content, err := json.Marshal(event)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot marshall [%T] with ID [%s]", event, event.ID()))
+	}
+
+	deadLetter := &entities.DeadLetter{
+		ID:        uuid.New(),
+		EventID:   event.ID(),
+		EventType: event.Type(),
+		UserID:    dispatcher.eventUserID(event),
+		Event:     content,
+		Listener:  listener,
+		Status:    entities.DeadLetterStatusFailed,
+		Error:     cause.Error(),
+		Attempts:  attempts,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	// the dead letter is saved even when the context of the event is canceled
+	if err = dispatcher.deadLetterRepository.Store(context.WithoutCancel(ctx), deadLetter); err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot store dead letter with ID [%s]", deadLetter.ID))
+	}
+
+	return nil
+}
+
+// eventUserID returns the ID of the user in the payload of an event, it is empty when the event is not related to a user
+func (dispatcher *EventDispatcher) eventUserID(event cloudevents.Event) entities.UserID {
+	var payload struct {
+		UserID entities.UserID `json:"user_id"`
+	}
+	if err := event.DataAs(&payload); err != nil {
+		return ""
+	}
+	return payload.UserID
+}
+
+// wait for the backoff duration before retrying a listener, it returns false when the context is done
+func (dispatcher *EventDispatcher) wait(ctx context.Context, backoff time.Duration) bool {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// listenerName identifies a listener using the name of its method e.g listeners.(*ProjectEndpointRequestListener).onProjectEndpointRequest
+func (dispatcher *EventDispatcher) listenerName(listener events.EventListener) string {
+	name := runtime.FuncForPC(reflect.ValueOf(listener).Pointer()).Name()
+	name = strings.TrimSuffix(name, "-fm")
+	if index := strings.LastIndex(name, "/"); index >= 0 {
+		name = name[index+1:]
+	}
+	return name
 }
 
 func (dispatcher *EventDispatcher) createTask(event *cloudevents.Event) (*queue.Task, error) {
@@ -21,6 +21,7 @@ type LemonsqueezyService struct {
 	tracer          telemetry.Tracer
 	eventDispatcher *EventDispatcher
 	userRepository  repositories.UserRepository
+	client          *lemonsqueezy.Client
 }
 
 // NewLemonsqueezyService creates a new LemonsqueezyService
@@ -29,12 +30,14 @@ func NewLemonsqueezyService(
 	tracer telemetry.Tracer,
 	repository repositories.UserRepository,
 	eventDispatcher *EventDispatcher,
+	client *lemonsqueezy.Client,
 ) (s *LemonsqueezyService) {
 	return &LemonsqueezyService{
 		logger:          logger.WithCodeNamespace(fmt.Sprintf("%T", s)),
 		tracer:          tracer,
 		userRepository:  repository,
 		eventDispatcher: eventDispatcher,
+		client:          client,
 	}
 }
 
@@ -72,6 +75,10 @@ func (service *LemonsqueezyService) HandleSubscriptionCanceledEvent(ctx context.
 	defer span.End()
 
 	user, err := service.userRepository.LoadBySubscriptionID(ctx, request.Data.ID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Info(fmt.Sprintf("ignoring cancelled subscription [%s] because the user account has been deleted", request.Data.ID))
+		return nil
+	}
 	if err != nil {
 		msg := fmt.Sprintf("cannot load user with subscription ID [%s]", request.Data.ID)
 		return stacktrace.Propagate(err, msg)
@@ -100,6 +107,20 @@ func (service *LemonsqueezyService) HandleSubscriptionCanceledEvent(ctx context.
 	return nil
 }
 
+// CancelSubscription cancels a lemonsqueezy subscription so that the user is not billed anymore
+func (service *LemonsqueezyService) CancelSubscription(ctx context.Context, subscriptionID string) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if _, _, err := service.client.Subscriptions.Cancel(ctx, subscriptionID); err != nil {
+		msg := fmt.Sprintf("cannot cancel lemonsqueezy subscription with ID [%s]", subscriptionID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("lemonsqueezy subscription [%s] cancelled", subscriptionID))
+	return nil
+}
+
 func (service *LemonsqueezyService) subscriptionName(variant string) entities.SubscriptionName {
 	if strings.Contains(strings.ToLower(variant), "10k-monthly") {
 		return entities.SubscriptionName10kMonthly
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/palantir/stacktrace"
+)
+
+// ErrCodeForbiddenAddress is returned when a request to a URL provided by a user would connect to an IP address which is not public
+const ErrCodeForbiddenAddress = stacktrace.ErrorCode(2002)
+
+// nonPublicNetworks are the IP ranges which are not covered by the net.IP helpers but must not be reachable from a user provided URL
+// e.g. the carrier-grade NAT range which is used by the metadata service of some cloud providers.
+var nonPublicNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"100.64.0.0/10",
+	"192.0.0.0/24",
+	"198.18.0.0/15",
+	"240.0.0.0/4",
+	"64:ff9b::/96",
+)
+
+// IsPublicIP checks if an IP address is a public unicast address which can be reached by requests to user provided URLs.
+// Loopback, private, link-local (including the 169.254.169.254 metadata service) and reserved addresses are not public.
+func IsPublicIP(ip net.IP) bool {
+	if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewPublicDialer creates a net.Dialer which refuses to connect to an IP address which is not public.
+// The address is checked after the host name is resolved so a DNS record which points to a private IP address is also refused.
+func NewPublicDialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout: timeout,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return stacktrace.Propagate(err, fmt.Sprintf("cannot parse the address [%s]", address))
+			}
+
+			if !IsPublicIP(net.ParseIP(host)) {
+				return stacktrace.NewErrorWithCode(ErrCodeForbiddenAddress, fmt.Sprintf("cannot connect to [%s] because it is not a public IP address", host))
+			}
+
+			return nil
+		},
+	}
+}
+
+// validateOutboundURL checks that a URL which is rendered from a user provided template is an absolute http or https URL.
+// The IP address of the host is checked by the dialer when the connection is opened.
+func validateOutboundURL(value string) error {
+	target, err := url.Parse(value)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot parse the url [%s]", value))
+	}
+
+	if (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return stacktrace.NewErrorWithCode(ErrCodeForbiddenAddress, fmt.Sprintf("the url [%s] must be an absolute http or https URL", value))
+	}
+
+	return nil
+}
+
+func mustParseCIDRs(values ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			panic(stacktrace.Propagate(err, fmt.Sprintf("cannot parse CIDR [%s]", value)))
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oklog/ulid/v2"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/events"
+	"github.com/NdoleStudio/httpmock/pkg/repositories"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/NdoleStudio/httpmock/pkg/templates"
+	"github.com/palantir/stacktrace"
+)
+
+// maxCallbackResponseBodySize is the maximum size of the response body of a callback which is saved in an attempt
+const maxCallbackResponseBodySize = 16 * 1024
+
+// callbackRetryBackoff is the delay before the first retry of a failed callback, it doubles after every failed attempt
+const callbackRetryBackoff = time.Second
+
+// ProjectEndpointCallbackService is responsible for sending entities.ProjectEndpointCallback
+type ProjectEndpointCallbackService struct {
+	service
+	logger          telemetry.Logger
+	tracer          telemetry.Tracer
+	httpClient      *http.Client
+	repository      repositories.ProjectEndpointCallbackAttemptRepository
+	eventDispatcher *EventDispatcher
+}
+
+// NewProjectEndpointCallbackService creates a new ProjectEndpointCallbackService
+func NewProjectEndpointCallbackService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	httpClient *http.Client,
+	repository repositories.ProjectEndpointCallbackAttemptRepository,
+	eventDispatcher *EventDispatcher,
+) (s *ProjectEndpointCallbackService) {
+	return &ProjectEndpointCallbackService{
+		logger:          logger.WithCodeNamespace(fmt.Sprintf("%T", s)),
+		tracer:          tracer,
+		httpClient:      httpClient,
+		repository:      repository,
+		eventDispatcher: eventDispatcher,
+	}
+}
+
+// Index fetches the entities.ProjectEndpointCallbackAttempt of an entities.ProjectEndpointRequest
+func (service *ProjectEndpointCallbackService) Index(ctx context.Context, userID entities.UserID, requestID ulid.ULID) ([]*entities.ProjectEndpointCallbackAttempt, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	attempts, err := service.repository.Index(ctx, userID, requestID.String())
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch callback attempts for user with ID [%s] and request ID [%s]", userID, requestID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return attempts, nil
+}
+
+// Delete the entities.ProjectEndpointCallbackAttempt of an entities.ProjectEndpointRequest
+func (service *ProjectEndpointCallbackService) Delete(ctx context.Context, userID entities.UserID, requestID string) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if err := service.repository.DeleteByRequest(ctx, userID, requestID); err != nil {
+		msg := fmt.Sprintf("cannot delete callback attempts for user with ID [%s] and request ID [%s]", userID, requestID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// Schedule dispatches an events.ProjectEndpointCallback event for every entities.ProjectEndpointCallback of an endpoint.
+// The URL, the body and the headers of the callbacks are rendered with the request which triggered them.
+func (service *ProjectEndpointCallbackService) Schedule(ctx context.Context, source string, endpoint *entities.ProjectEndpoint, requestID ulid.ULID, data *templates.RequestData) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	for index, callback := range endpoint.Callbacks {
+		payload, err := service.render(callback, data)
+		if err != nil {
+			msg := fmt.Sprintf("cannot render callback [%d] of endpoint [%s] for request [%s]", index, endpoint.ID, requestID)
+			ctxLogger.Warn(stacktrace.Propagate(err, msg))
+			continue
+		}
+
+		payload.UserID = endpoint.UserID
+		payload.ProjectID = endpoint.ProjectID
+		payload.ProjectEndpointID = endpoint.ID
+		payload.ProjectEndpointRequestID = requestID
+		payload.CallbackIndex = uint(index)
+		payload.Attempt = 1
+		payload.MaxAttempts = callback.Retries + 1
+		payload.Timestamp = time.Now().UTC()
+		payload.ScheduledAt = payload.Timestamp.Add(time.Duration(callback.DelayInMilliseconds) * time.Millisecond)
+
+		service.dispatch(ctx, ctxLogger, source, payload)
+	}
+}
+
+// Send an entities.ProjectEndpointCallback and store the entities.ProjectEndpointCallbackAttempt, the queue delivers the event when it is due.
+// A failed attempt is retried with an exponential backoff until the maximum number of attempts is reached.
+func (service *ProjectEndpointCallbackService) Send(ctx context.Context, source string, payload *events.ProjectEndpointCallbackPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	attempt := service.send(ctx, payload)
+	if err := service.repository.Store(ctx, attempt); err != nil {
+		msg := fmt.Sprintf("cannot store attempt [%d] of callback [%d] for request [%s]", attempt.Attempt, attempt.CallbackIndex, attempt.ProjectEndpointRequestID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if attempt.Succeeded || payload.Attempt >= payload.MaxAttempts {
+		ctxLogger.Info(fmt.Sprintf("sent attempt [%d/%d] of callback [%d] for request [%s] with success [%t]", payload.Attempt, payload.MaxAttempts, payload.CallbackIndex, payload.ProjectEndpointRequestID, attempt.Succeeded))
+		return nil
+	}
+
+	retry := *payload
+	retry.Attempt++
+	retry.Timestamp = time.Now().UTC()
+	retry.ScheduledAt = retry.Timestamp.Add(callbackRetryBackoff << (payload.Attempt - 1))
+
+	service.dispatch(ctx, ctxLogger, source, &retry)
+	return nil
+}
+
+// send the HTTP request of a callback, the errors are saved in the entities.ProjectEndpointCallbackAttempt
+func (service *ProjectEndpointCallbackService) send(ctx context.Context, payload *events.ProjectEndpointCallbackPayload) *entities.ProjectEndpointCallbackAttempt {
+	attempt := &entities.ProjectEndpointCallbackAttempt{
+		ID:                       uuid.New(),
+		ProjectID:                payload.ProjectID,
+		ProjectEndpointID:        payload.ProjectEndpointID,
+		ProjectEndpointRequestID: payload.ProjectEndpointRequestID.String(),
+		UserID:                   payload.UserID,
+		CallbackIndex:            payload.CallbackIndex,
+		Attempt:                  payload.Attempt,
+		RequestURL:               payload.URL,
+		RequestMethod:            payload.Method,
+		RequestHeaders:           payload.Headers,
+		RequestBody:              payload.Body,
+		CreatedAt:                time.Now().UTC(),
+	}
+
+	var body io.Reader
+	if payload.Body != nil {
+		body = strings.NewReader(*payload.Body)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, payload.Method, payload.URL, body)
+	if err != nil {
+		return service.failAttempt(attempt, err)
+	}
+
+	if payload.Headers != nil && *payload.Headers != "" {
+		var headers []map[string]string
+		if err = json.Unmarshal([]byte(*payload.Headers), &headers); err != nil {
+			return service.failAttempt(attempt, err)
+		}
+		for _, header := range headers {
+			for key, value := range header {
+				request.Header.Set(key, value)
+			}
+		}
+	}
+
+	start := time.Now()
+	response, err := service.httpClient.Do(request)
+	attempt.DurationInMilliseconds = uint(time.Since(start).Milliseconds())
+	if err != nil {
+		return service.failAttempt(attempt, err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	code := uint(response.StatusCode)
+	attempt.ResponseCode = &code
+	attempt.Succeeded = response.StatusCode >= http.StatusOK && response.StatusCode < http.StatusMultipleChoices
+
+	content, err := io.ReadAll(io.LimitReader(response.Body, maxCallbackResponseBodySize))
+	if err != nil {
+		return service.failAttempt(attempt, err)
+	}
+
+	if len(content) > 0 {
+		responseBody := string(content)
+		attempt.ResponseBody = &responseBody
+	}
+
+	return attempt
+}
+
+func (service *ProjectEndpointCallbackService) failAttempt(attempt *entities.ProjectEndpointCallbackAttempt, err error) *entities.ProjectEndpointCallbackAttempt {
+	message := err.Error()
+	attempt.Error = &message
+	attempt.Succeeded = false
+	return attempt
+}
+
+func (service *ProjectEndpointCallbackService) render(callback *entities.ProjectEndpointCallback, data *templates.RequestData) (*events.ProjectEndpointCallbackPayload, error) {
+	url, err := templates.Render(callback.URL, data)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot render the callback url")
+	}
+
+	payload := &events.ProjectEndpointCallbackPayload{URL: strings.TrimSpace(url), Method: callback.Method}
+	if err = validateOutboundURL(payload.URL); err != nil {
+		return nil, stacktrace.Propagate(err, "cannot send a callback to the rendered url")
+	}
+
+	if callback.Body != nil {
+		body, err := templates.Render(*callback.Body, data)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "cannot render the callback body")
+		}
+		payload.Body = &body
+	}
+
+	if callback.Headers == nil || *callback.Headers == "" {
+		return payload, nil
+	}
+
+	var headers []map[string]string
+	if err = json.Unmarshal([]byte(*callback.Headers), &headers); err != nil {
+		return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot unmarshal the callback headers [%s]", *callback.Headers))
+	}
+
+	for _, header := range headers {
+		for key, value := range header {
+			if header[key], err = templates.Render(value, data); err != nil {
+				return nil, stacktrace.Propagate(err, fmt.Sprintf("cannot render the callback header [%s]", key))
+			}
+		}
+	}
+
+	result, err := json.Marshal(headers)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "cannot marshal the rendered callback headers")
+	}
+
+	rendered := string(result)
+	payload.Headers = &rendered
+	return payload, nil
+}
+
+// dispatch an events.ProjectEndpointCallback event which is due at the ScheduledAt time of the payload.
+// The errors are logged because the callback cannot be sent again.
+func (service *ProjectEndpointCallbackService) dispatch(ctx context.Context, ctxLogger telemetry.Logger, source string, payload *events.ProjectEndpointCallbackPayload) {
+	event, err := service.createEvent(events.ProjectEndpointCallback, source, payload)
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for callback [%d] of request [%s]", events.ProjectEndpointCallback, payload.CallbackIndex, payload.ProjectEndpointRequestID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return
+	}
+
+	if err = service.eventDispatcher.DispatchAt(ctx, event, payload.ScheduledAt); err != nil {
+		msg := fmt.Sprintf("cannot dispatch [%s] event for callback [%d] of request [%s]", event.Type(), payload.CallbackIndex, payload.ProjectEndpointRequestID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+	}
+}
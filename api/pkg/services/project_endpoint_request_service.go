@@ -1,46 +1,100 @@
 package services
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/oklog/ulid/v2"
 
+	"github.com/NdoleStudio/httpmock/pkg/cache"
 	"github.com/NdoleStudio/httpmock/pkg/entities"
 	"github.com/NdoleStudio/httpmock/pkg/events"
+	"github.com/NdoleStudio/httpmock/pkg/matchers"
 	"github.com/NdoleStudio/httpmock/pkg/repositories"
 	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/NdoleStudio/httpmock/pkg/templates"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/proxy"
 	"github.com/palantir/stacktrace"
+	"github.com/valyala/fasthttp"
 )
 
+// maxPassthroughBodySize is the maximum size of a proxied request or response body which is stored in the request log
+const maxPassthroughBodySize = 64 * 1024
+
+// recordingExcludedHeaders are upstream response headers which are not saved when recording an entities.ProjectEndpoint
+// either because they contain credentials or because they are computed when the mock response is written.
+var recordingExcludedHeaders = []string{
+	fiber.HeaderAuthorization,
+	fiber.HeaderProxyAuthorization,
+	fiber.HeaderProxyAuthenticate,
+	fiber.HeaderWWWAuthenticate,
+	fiber.HeaderCookie,
+	fiber.HeaderSetCookie,
+	fiber.HeaderConnection,
+	fiber.HeaderKeepAlive,
+	fiber.HeaderTransferEncoding,
+	fiber.HeaderContentLength,
+	fiber.HeaderContentEncoding,
+	fiber.HeaderDate,
+}
+
+// recordingSensitiveHeaderParts are used to detect custom upstream headers which contain credentials e.g. X-Api-Key
+var recordingSensitiveHeaderParts = []string{"token", "secret", "api-key", "apikey", "password", "signature", "session"}
+
 // ProjectEndpointRequestService is responsible for managing entities.ProjectEndpointRequest
 type ProjectEndpointRequestService struct {
 	service
 	logger                           telemetry.Logger
 	tracer                           telemetry.Tracer
+	upstreamClient                   *fasthttp.Client
 	projectEndpointRequestRepository repositories.ProjectEndpointRequestRepository
 	projectEndpointRepository        repositories.ProjectEndpointRepository
+	projectRepository                repositories.ProjectRepository
+	projectEndpointService           *ProjectEndpointService
+	subscriptionService              *SubscriptionService
+	callbackService                  *ProjectEndpointCallbackService
 	eventDispatcher                  *EventDispatcher
+	expiredRequestsDeletedAt         *cache.LRUCache[entities.UserID, time.Time]
 }
 
 // NewProjectEndpointRequestService creates a new ProjectEndpointRequestService
 func NewProjectEndpointRequestService(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
+	upstreamClient *fasthttp.Client,
 	projectEndpointRepository repositories.ProjectEndpointRepository,
 	projectEndpointRequestRepository repositories.ProjectEndpointRequestRepository,
+	projectRepository repositories.ProjectRepository,
+	projectEndpointService *ProjectEndpointService,
+	subscriptionService *SubscriptionService,
+	callbackService *ProjectEndpointCallbackService,
 	eventDispatcher *EventDispatcher,
 ) (s *ProjectEndpointRequestService) {
 	return &ProjectEndpointRequestService{
 		logger:                           logger.WithCodeNamespace(fmt.Sprintf("%T", s)),
 		tracer:                           tracer,
 		projectEndpointRepository:        projectEndpointRepository,
+		projectRepository:                projectRepository,
+		projectEndpointService:           projectEndpointService,
+		subscriptionService:              subscriptionService,
+		callbackService:                  callbackService,
+		upstreamClient:                   upstreamClient,
 		eventDispatcher:                  eventDispatcher,
 		projectEndpointRequestRepository: projectEndpointRequestRepository,
+		expiredRequestsDeletedAt:         cache.NewLRUCache[entities.UserID, time.Time](expiredRequestsCacheSize),
 	}
 }
 
@@ -60,6 +114,15 @@ func (service *ProjectEndpointRequestService) Delete(ctx context.Context, userID
 		return stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg)
 	}
 
+	if err = service.callbackService.Delete(ctx, userID, request.ID); err != nil {
+		msg := fmt.Sprintf("cannot delete callback attempts of endpoint request with ID [%s] for user ID [%s]", request.ID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if request.Passthrough || request.Unmatched {
+		return nil
+	}
+
 	if err = service.projectEndpointRepository.DecreaseRequestCount(ctx, request.ProjectEndpointID); err != nil {
 		msg := fmt.Sprintf("cannot decrease request for [%T] with ID [%s] for project with ID [%s] and user with ID [%s]", &entities.ProjectEndpoint{}, request.ProjectEndpointID, request.ProjectID, request.UserID)
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
@@ -82,6 +145,20 @@ func (service *ProjectEndpointRequestService) Index(ctx context.Context, userID
 	return requests, nil
 }
 
+// Search fetches the entities.ProjectEndpointRequest of all the endpoints of a project which match the repositories.ProjectEndpointRequestSearchParams
+func (service *ProjectEndpointRequestService) Search(ctx context.Context, userID entities.UserID, params *repositories.ProjectEndpointRequestSearchParams) ([]*entities.ProjectEndpointRequest, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	requests, err := service.projectEndpointRequestRepository.Search(ctx, userID, params)
+	if err != nil {
+		msg := fmt.Sprintf("cannot search project endpoint requests for user with ID [%s] and project ID [%s]", userID, params.ProjectID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return requests, nil
+}
+
 // HandleHTTPRequest registers a new HTTP request for an endpoint
 func (service *ProjectEndpointRequestService) HandleHTTPRequest(ctx context.Context, c *fiber.Ctx, stopwatch time.Time, endpoint *entities.ProjectEndpoint) {
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
@@ -89,39 +166,622 @@ func (service *ProjectEndpointRequestService) HandleHTTPRequest(ctx context.Cont
 
 	requestID := ulid.Make()
 
-	service.storeProjectEndpointRequestEvent(ctx, requestID, stopwatch, c, endpoint)
-	headers := service.getHTTPHeaders(ctxLogger, c, endpoint)
+	response := service.getHTTPResponse(ctx, ctxLogger, c, requestID, stopwatch, endpoint)
+	service.applyFaults(response, endpoint)
+
+	service.storeProjectEndpointRequestEvent(ctx, requestID, stopwatch, c, endpoint, response)
+	service.updateScenarioState(ctx, endpoint)
 
-	delay := endpoint.ResponseDelayInMilliseconds - uint(time.Since(stopwatch).Milliseconds())
-	if endpoint.ResponseDelayInMilliseconds > 0 && delay > 0 {
-		time.Sleep(time.Duration(delay) * time.Millisecond)
+	if delay := response.delay - time.Since(stopwatch); delay > 0 {
+		time.Sleep(delay)
+	}
+
+	if len(endpoint.Callbacks) > 0 {
+		service.callbackService.Schedule(ctx, c.BaseURL()+c.OriginalURL(), endpoint, requestID, service.getTemplateData(c, requestID, stopwatch, endpoint))
 	}
 
 	ctxLogger.Debug(fmt.Sprintf("finished handling request with URL [%s] in [%s] and request ID [%s]", c.BaseURL()+c.OriginalURL(), time.Since(stopwatch).String(), requestID))
-	for _, header := range headers {
+	if service.writeFault(ctxLogger, c, response) {
+		return
+	}
+
+	service.writeHeaders(c, response)
+
+	if response.body != nil {
+		if _, err := c.Response().BodyWriter().Write([]byte(*response.body)); err != nil {
+			msg := fmt.Sprintf("error while writing response body for request [%s] with method [%s] and request ID [%s]", c.BaseURL()+c.OriginalURL(), c.Method(), requestID)
+			ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		}
+	}
+}
+
+// HandlePassthroughRequest proxies a request which does not match any endpoint to the upstream URL of the entities.Project
+func (service *ProjectEndpointRequestService) HandlePassthroughRequest(ctx context.Context, c *fiber.Ctx, stopwatch time.Time, project *entities.Project) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	requestID := ulid.Make()
+	source := c.BaseURL() + c.OriginalURL()
+	requestBody := service.truncateBody(service.getRequestBody(c))
+	requestHeaders := service.getRequestHeaders(ctxLogger, c)
+
+	// the body is not compressed so that it is readable in the request log
+	c.Request().Header.Del(fiber.HeaderAcceptEncoding)
+
+	upstreamURL := project.UpstreamURL + c.OriginalURL()
+	if err := proxy.Do(c, upstreamURL, service.upstreamClient); err != nil {
+		msg := fmt.Sprintf("cannot proxy request [%s] with method [%s] to upstream URL [%s]", source, c.Method(), upstreamURL)
+		ctxLogger.Warn(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+
+		c.Response().Reset()
+		_ = c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"status":  "error",
+			"message": fmt.Sprintf("We could not forward the request to the upstream URL [%s]", upstreamURL),
+		})
+	} else if project.RecordingEnabled {
+		service.recordProjectEndpoint(ctx, project, c)
+	}
+
+	ctxLogger.Debug(fmt.Sprintf("finished proxying request with URL [%s] to [%s] in [%s] and request ID [%s]", source, upstreamURL, time.Since(stopwatch).String(), requestID))
+
+	service.dispatchProjectEndpointRequestEvent(ctx, ctxLogger, source, &events.ProjectEndpointRequestPayload{
+		UserID:                      project.UserID,
+		ProjectID:                   project.ID,
+		ProjectEndpointRequestID:    requestID,
+		RequestURL:                  source,
+		RequestMethod:               c.Method(),
+		RequestBody:                 requestBody,
+		RequestHeaders:              requestHeaders,
+		ResponseCode:                uint(c.Response().StatusCode()),
+		ResponseBody:                service.getResponseBody(c),
+		ResponseHeaders:             service.getResponseHeaders(c),
+		ResponseDelayInMilliseconds: uint(time.Since(stopwatch).Milliseconds()),
+		Passthrough:                 true,
+		RequestIPAddress:            c.IP(),
+		Timestamp:                   stopwatch,
+	})
+}
+
+// HandleUnmatchedRequest logs a request to an entities.Project which does not match any entities.ProjectEndpoint after the response is written
+func (service *ProjectEndpointRequestService) HandleUnmatchedRequest(ctx context.Context, c *fiber.Ctx, stopwatch time.Time, project *entities.Project) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	requestID := ulid.Make()
+	source := c.BaseURL() + c.OriginalURL()
+
+	ctxLogger.Debug(fmt.Sprintf("request with URL [%s] and method [%s] does not match any endpoint of project [%s] and has request ID [%s]", source, c.Method(), project.ID, requestID))
+
+	service.dispatchProjectEndpointRequestEvent(ctx, ctxLogger, source, &events.ProjectEndpointRequestPayload{
+		UserID:                      project.UserID,
+		ProjectID:                   project.ID,
+		ProjectEndpointRequestID:    requestID,
+		RequestURL:                  source,
+		RequestMethod:               c.Method(),
+		RequestBody:                 service.truncateBody(service.getRequestBody(c)),
+		RequestHeaders:              service.getRequestHeaders(ctxLogger, c),
+		ResponseCode:                uint(c.Response().StatusCode()),
+		ResponseBody:                service.getResponseBody(c),
+		ResponseHeaders:             service.getResponseHeaders(c),
+		ResponseDelayInMilliseconds: uint(time.Since(stopwatch).Milliseconds()),
+		Unmatched:                   true,
+		RequestIPAddress:            c.IP(),
+		Timestamp:                   stopwatch,
+	})
+}
+
+// getResponseHeaders returns the headers of the response which has been written as a JSON array
+func (service *ProjectEndpointRequestService) getResponseHeaders(c *fiber.Ctx) *string {
+	var headers []map[string]string
+	c.Response().Header.VisitAll(func(key, value []byte) {
+		headers = append(headers, map[string]string{string(key): string(value)})
+	})
+
+	result, err := json.Marshal(headers)
+	if err != nil {
+		return nil
+	}
+
+	resultString := string(result)
+	return &resultString
+}
+
+// getResponseBody returns the truncated body of the response which has been written
+func (service *ProjectEndpointRequestService) getResponseBody(c *fiber.Ctx) *string {
+	if len(c.Response().Body()) == 0 {
+		return nil
+	}
+	content := string(c.Response().Body())
+	return service.truncateBody(&content)
+}
+
+// recordProjectEndpoint saves the upstream response of a proxied request as a new entities.ProjectEndpoint
+func (service *ProjectEndpointRequestService) recordProjectEndpoint(ctx context.Context, project *entities.Project, c *fiber.Ctx) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	requestPath := c.Path()
+	if strings.ContainsAny(requestPath, "{}*?[") {
+		ctxLogger.Info(fmt.Sprintf("cannot record request path [%s] for project [%s] because it contains reserved characters", requestPath, project.ID))
+		return
+	}
+
+	if len(c.Response().Body()) > maxPassthroughBodySize {
+		ctxLogger.Info(fmt.Sprintf("cannot record [%s %s] for project [%s] because the response body has [%d] bytes", c.Method(), requestPath, project.ID, len(c.Response().Body())))
+		return
+	}
+
+	_, err := service.projectEndpointRepository.LoadConflicting(ctx, &entities.ProjectEndpoint{
+		UserID:        project.UserID,
+		ProjectID:     project.ID,
+		RequestMethod: c.Method(),
+		RequestPath:   requestPath,
+	})
+	if err == nil {
+		ctxLogger.Info(fmt.Sprintf("[%s %s] has already been recorded for project [%s]", c.Method(), requestPath, project.ID))
+		return
+	}
+
+	if stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("cannot check if [%s %s] has already been recorded for project [%s]", c.Method(), requestPath, project.ID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return
+	}
+
+	var responseBody *string
+	if body := c.Response().Body(); len(body) > 0 {
+		content := string(body)
+		responseBody = &content
+	}
+
+	description := fmt.Sprintf("Recorded from %s on %s", project.UpstreamURL, time.Now().UTC().Format(time.RFC1123))
+	endpoint, err := service.projectEndpointService.Store(ctx, project, &ProjectEndpointStoreParams{
+		RequestMethod:   c.Method(),
+		RequestPath:     requestPath,
+		ResponseCode:    uint(c.Response().StatusCode()),
+		ResponseBody:    responseBody,
+		ResponseHeaders: service.getRecordedHeaders(ctxLogger, c),
+		Description:     &description,
+		ProjectID:       project.ID,
+		UserID:          project.UserID,
+	})
+	if stacktrace.GetCode(err) == ErrCodeSubscriptionLimit {
+		ctxLogger.Info(fmt.Sprintf("cannot record [%s %s] for project [%s] because the endpoint limit of the subscription is reached", c.Method(), requestPath, project.ID))
+		return
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot record [%s %s] for project [%s]", c.Method(), requestPath, project.ID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		return
+	}
+
+	ctxLogger.Info(fmt.Sprintf("recorded [%s %s] as endpoint [%s] for project [%s]", endpoint.RequestMethod, endpoint.RequestPath, endpoint.ID, project.ID))
+}
+
+func (service *ProjectEndpointRequestService) getRecordedHeaders(ctxLogger telemetry.Logger, c *fiber.Ctx) *string {
+	var headers []map[string]string
+	c.Response().Header.VisitAll(func(key, value []byte) {
+		if service.isRecordingExcludedHeader(string(key)) {
+			return
+		}
+		headers = append(headers, map[string]string{string(key): string(value)})
+	})
+
+	if len(headers) == 0 {
+		return nil
+	}
+
+	result, err := json.Marshal(headers)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagate(err, fmt.Sprintf("error while marshalling recorded headers [%s]", headers)))
+		return nil
+	}
+
+	resultString := string(result)
+	return &resultString
+}
+
+func (service *ProjectEndpointRequestService) isRecordingExcludedHeader(key string) bool {
+	for _, header := range recordingExcludedHeaders {
+		if strings.EqualFold(header, key) {
+			return true
+		}
+	}
+
+	key = strings.ToLower(key)
+	for _, part := range recordingSensitiveHeaderParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (service *ProjectEndpointRequestService) truncateBody(body *string) *string {
+	if body == nil || len(*body) <= maxPassthroughBodySize {
+		return body
+	}
+	truncated := (*body)[:maxPassthroughBodySize]
+	return &truncated
+}
+
+func (service *ProjectEndpointRequestService) writeHeaders(c *fiber.Ctx, response *httpResponse) {
+	for _, header := range response.headers {
 		for key, value := range header {
 			c.Response().Header.Set(key, value)
 		}
 	}
 
-	c.Response().SetStatusCode(int(endpoint.ResponseCode))
+	c.Response().SetStatusCode(int(response.code))
+}
 
-	if endpoint.ResponseBody != nil {
-		if _, err := c.Response().BodyWriter().Write([]byte(*endpoint.ResponseBody)); err != nil {
-			msg := fmt.Sprintf("error while writing response body for request [%s] with method [%s] and request ID [%s]", c.BaseURL()+c.OriginalURL(), c.Method(), requestID)
-			ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+// applyFaults selects the entities.ProjectEndpointFault which will be simulated for the request.
+// Latency faults are added to the response delay while the first response fault which fires replaces the normal response.
+func (service *ProjectEndpointRequestService) applyFaults(response *httpResponse, endpoint *entities.ProjectEndpoint) {
+	response.delay = time.Duration(endpoint.ResponseDelayInMilliseconds) * time.Millisecond
+
+	for _, fault := range endpoint.Faults {
+		if rand.Float64() >= fault.Probability {
+			continue
+		}
+
+		if fault.Type == entities.ProjectEndpointFaultTypeLatency {
+			response.delay += service.getFaultLatency(fault)
+			if response.fault == nil {
+				response.fault = fault
+			}
+			continue
+		}
+
+		if response.fault == nil || response.fault.Type == entities.ProjectEndpointFaultTypeLatency {
+			response.fault = fault
 		}
 	}
+
+	if response.fault != nil && response.fault.Type == entities.ProjectEndpointFaultTypeEmptyResponse {
+		response.body = nil
+	}
 }
 
-// LoadByRequest a project endpoint by request method and path
-func (service *ProjectEndpointRequestService) LoadByRequest(ctx context.Context, subdomain string, requestMethod, requestPath string) (*entities.ProjectEndpoint, error) {
-	return service.projectEndpointRepository.LoadByRequest(ctx, subdomain, requestMethod, requestPath)
+func (service *ProjectEndpointRequestService) getFaultLatency(fault *entities.ProjectEndpointFault) time.Duration {
+	minimum := float64(fault.LatencyMinInMilliseconds)
+	maximum := float64(fault.LatencyMaxInMilliseconds)
+
+	var latency float64
+	switch fault.LatencyDistribution {
+	case entities.ProjectEndpointLatencyDistributionNormal:
+		latency = (minimum+maximum)/2 + rand.NormFloat64()*(maximum-minimum)/6
+	case entities.ProjectEndpointLatencyDistributionExponential:
+		latency = minimum + rand.ExpFloat64()*(maximum-minimum)/4
+	default:
+		latency = minimum + rand.Float64()*(maximum-minimum)
+	}
+
+	return time.Duration(math.Max(minimum, math.Min(maximum, latency))) * time.Millisecond
+}
+
+// writeFault simulates a response fault and returns true if the normal response must not be sent
+func (service *ProjectEndpointRequestService) writeFault(ctxLogger telemetry.Logger, c *fiber.Ctx, response *httpResponse) bool {
+	if response.fault == nil {
+		return false
+	}
+
+	switch response.fault.Type {
+	case entities.ProjectEndpointFaultTypeConnectionReset, entities.ProjectEndpointFaultTypeConnectionClose:
+		reset := response.fault.Type == entities.ProjectEndpointFaultTypeConnectionReset
+		c.Context().HijackSetNoResponse(true)
+		c.Context().Hijack(func(conn net.Conn) {
+			if reset {
+				service.setLinger(conn)
+			}
+			if err := conn.Close(); err != nil {
+				ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot close connection for [%s] fault", response.fault.Type)))
+			}
+		})
+		return true
+	case entities.ProjectEndpointFaultTypeEmptyResponse:
+		service.writeHeaders(c, response)
+		return true
+	case entities.ProjectEndpointFaultTypeMalformedChunk:
+		c.Context().HijackSetNoResponse(true)
+		c.Context().Hijack(func(conn net.Conn) {
+			if _, err := conn.Write(service.getMalformedChunkResponse(response)); err != nil {
+				ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot write [%s] fault", response.fault.Type)))
+			}
+		})
+		return true
+	case entities.ProjectEndpointFaultTypeDrip:
+		service.writeHeaders(c, response)
+		service.writeDripBody(c, response)
+		return true
+	default:
+		return false
+	}
+}
+
+// setLinger makes the connection send a TCP RST instead of a FIN when it is closed
+func (service *ProjectEndpointRequestService) setLinger(conn net.Conn) {
+	if unwrapper, ok := conn.(interface{ UnsafeConn() net.Conn }); ok {
+		conn = unwrapper.UnsafeConn()
+	}
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		_ = tcpConn.SetLinger(0)
+	}
+}
+
+func (service *ProjectEndpointRequestService) getMalformedChunkResponse(response *httpResponse) []byte {
+	buffer := new(bytes.Buffer)
+	buffer.WriteString(fmt.Sprintf("HTTP/1.1 %d %s\r\n", response.code, fasthttp.StatusMessage(int(response.code))))
+	for _, header := range response.headers {
+		for key, value := range header {
+			buffer.WriteString(fmt.Sprintf("%s: %s\r\n", key, value))
+		}
+	}
+	buffer.WriteString("Transfer-Encoding: chunked\r\nConnection: close\r\n\r\n")
+
+	body := ""
+	if response.body != nil {
+		body = *response.body
+	}
+
+	// a valid chunk with the first half of the body followed by a chunk with an invalid size
+	half := body[:len(body)/2]
+	buffer.WriteString(fmt.Sprintf("%x\r\n%s\r\n", len(half), half))
+	buffer.WriteString("zz\r\n" + body[len(half):])
+
+	return buffer.Bytes()
+}
+
+func (service *ProjectEndpointRequestService) writeDripBody(c *fiber.Ctx, response *httpResponse) {
+	if response.body == nil || *response.body == "" {
+		return
+	}
+
+	body := []byte(*response.body)
+	chunks := min(len(body), 50)
+	interval := time.Duration(response.fault.DripDurationInMilliseconds) * time.Millisecond / time.Duration(chunks)
+
+	c.Context().SetBodyStreamWriter(func(writer *bufio.Writer) {
+		for index := 0; index < chunks; index++ {
+			start, end := index*len(body)/chunks, (index+1)*len(body)/chunks
+			if _, err := writer.Write(body[start:end]); err != nil {
+				return
+			}
+			if err := writer.Flush(); err != nil {
+				return
+			}
+			if index < chunks-1 {
+				time.Sleep(interval)
+			}
+		}
+	})
+}
+
+// httpResponse is the response which is sent for a request to an entities.ProjectEndpoint
+type httpResponse struct {
+	delay         time.Duration
+	fault         *entities.ProjectEndpointFault
+	index         *uint
+	code          uint
+	body          *string
+	headers       []map[string]string
+	headersString *string
+}
+
+func (service *ProjectEndpointRequestService) getHTTPResponse(ctx context.Context, ctxLogger telemetry.Logger, c *fiber.Ctx, requestID ulid.ULID, stopwatch time.Time, endpoint *entities.ProjectEndpoint) *httpResponse {
+	response := &httpResponse{
+		code:          endpoint.ResponseCode,
+		body:          endpoint.ResponseBody,
+		headers:       service.getHTTPHeaders(ctxLogger, c, endpoint.ResponseHeaders),
+		headersString: endpoint.ResponseHeaders,
+	}
+
+	if index, ok := service.selectResponseIndex(ctx, ctxLogger, endpoint); ok {
+		variant := endpoint.Responses[index]
+		response = &httpResponse{
+			index:         &index,
+			code:          variant.ResponseCode,
+			body:          variant.ResponseBody,
+			headers:       service.getHTTPHeaders(ctxLogger, c, variant.ResponseHeaders),
+			headersString: variant.ResponseHeaders,
+		}
+	}
+
+	if !endpoint.ResponseTemplateEnabled {
+		return response
+	}
+
+	if err := service.renderHTTPResponse(response, service.getTemplateData(c, requestID, stopwatch, endpoint)); err != nil {
+		msg := fmt.Sprintf("cannot render response template for endpoint [%s] and request [%s] with method [%s]", endpoint.ID, c.BaseURL()+c.OriginalURL(), c.Method())
+		ctxLogger.Warn(stacktrace.Propagate(err, msg))
+
+		body := fmt.Sprintf(`{"status":"error","message":%q}`, "We could not render the response template for this endpoint: "+stacktrace.RootCause(err).Error())
+		headers := `[{"Content-Type":"application/json"}]`
+		return &httpResponse{
+			code:          fiber.StatusInternalServerError,
+			body:          &body,
+			headers:       []map[string]string{{fiber.HeaderContentType: fiber.MIMEApplicationJSON}},
+			headersString: &headers,
+		}
+	}
+
+	return response
+}
+
+// selectResponseIndex selects one of the entities.ProjectEndpointResponse of an endpoint using the entities.ProjectEndpointResponseMode
+func (service *ProjectEndpointRequestService) selectResponseIndex(ctx context.Context, ctxLogger telemetry.Logger, endpoint *entities.ProjectEndpoint) (uint, bool) {
+	count := uint(len(endpoint.Responses))
+	if count == 0 {
+		return 0, false
+	}
+
+	if endpoint.ResponseMode == entities.ProjectEndpointResponseModeWeightedRandom {
+		total := uint(0)
+		for _, response := range endpoint.Responses {
+			total += response.Weight
+		}
+		if total == 0 {
+			return uint(rand.Intn(int(count))), true
+		}
+
+		value := uint(rand.Intn(int(total)))
+		for index, response := range endpoint.Responses {
+			if value < response.Weight {
+				return uint(index), true
+			}
+			value -= response.Weight
+		}
+		return count - 1, true
+	}
+
+	counter, err := service.projectEndpointRepository.IncreaseResponseCounter(ctx, endpoint.ID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot increase the response counter for endpoint [%s] in project [%s]", endpoint.ID, endpoint.ProjectID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return 0, true
+	}
+
+	if endpoint.ResponseMode == entities.ProjectEndpointResponseModeSequential {
+		return min(counter-1, count-1), true
+	}
+
+	return (counter - 1) % count, true
+}
+
+func (service *ProjectEndpointRequestService) renderHTTPResponse(response *httpResponse, data *templates.RequestData) error {
+	if response.body != nil {
+		body, err := templates.Render(*response.body, data)
+		if err != nil {
+			return stacktrace.Propagate(err, "cannot render the response body")
+		}
+		response.body = &body
+	}
+
+	for _, header := range response.headers {
+		for key, value := range header {
+			result, err := templates.Render(value, data)
+			if err != nil {
+				return stacktrace.Propagate(err, fmt.Sprintf("cannot render the response header [%s]", key))
+			}
+			header[key] = result
+		}
+	}
+
+	if len(response.headers) > 0 {
+		headers, err := json.Marshal(response.headers)
+		if err != nil {
+			return stacktrace.Propagate(err, "cannot marshal the rendered response headers")
+		}
+		headersString := string(headers)
+		response.headersString = &headersString
+	}
+
+	return nil
+}
+
+func (service *ProjectEndpointRequestService) getTemplateData(c *fiber.Ctx, requestID ulid.ULID, stopwatch time.Time, endpoint *entities.ProjectEndpoint) *templates.RequestData {
+	pathParams := map[string]string{}
+	if template, err := matchers.ParsePath(endpoint.RequestPath); err == nil {
+		if params, ok := template.Match(c.Path()); ok {
+			pathParams = params
+		}
+	}
+
+	headers := map[string]string{}
+	for key, values := range c.GetReqHeaders() {
+		if len(values) > 0 {
+			headers[key] = values[0]
+		}
+	}
+
+	var body interface{}
+	if len(c.Body()) > 0 {
+		_ = json.Unmarshal(c.Body(), &body)
+	}
+
+	return &templates.RequestData{
+		RequestID:  requestID.String(),
+		Method:     c.Method(),
+		URL:        c.BaseURL() + c.OriginalURL(),
+		Path:       c.Path(),
+		PathParams: pathParams,
+		Query:      c.Queries(),
+		Headers:    headers,
+		Body:       body,
+		RawBody:    string(c.Body()),
+		Timestamp:  stopwatch.UTC(),
+	}
+}
+
+// LoadProject loads the entities.Project which owns a subdomain
+func (service *ProjectEndpointRequestService) LoadProject(ctx context.Context, subdomain string) (*entities.Project, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	project, err := service.projectRepository.LoadWithSubdomain(ctx, subdomain)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load project with subdomain [%s]", subdomain)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	return project, nil
+}
+
+// LoadByRequest a project endpoint by the request method, path, request conditions and the scenario states of the project
+func (service *ProjectEndpointRequestService) LoadByRequest(ctx context.Context, project *entities.Project, c *fiber.Ctx) (*entities.ProjectEndpoint, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	request := service.getMatcherRequest(c)
+	request.ScenarioStates = project.ScenarioStates
+
+	endpoint, err := service.projectEndpointRepository.LoadByRequest(ctx, project.Subdomain, request)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load endpoint for project [%s] with method [%s] and path [%s]", project.ID, request.Method, request.Path)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	return endpoint, nil
+}
+
+func (service *ProjectEndpointRequestService) updateScenarioState(ctx context.Context, endpoint *entities.ProjectEndpoint) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if endpoint.ScenarioName == "" || endpoint.ScenarioNewState == "" {
+		return
+	}
+
+	if err := service.projectRepository.UpdateScenarioState(ctx, endpoint.ProjectID, endpoint.ScenarioName, endpoint.ScenarioNewState); err != nil {
+		msg := fmt.Sprintf("cannot update scenario [%s] to state [%s] for project [%s]", endpoint.ScenarioName, endpoint.ScenarioNewState, endpoint.ProjectID)
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+	}
+}
+
+func (service *ProjectEndpointRequestService) getMatcherRequest(c *fiber.Ctx) *matchers.Request {
+	headers := http.Header{}
+	for key, values := range c.GetReqHeaders() {
+		for _, value := range values {
+			headers.Add(key, value)
+		}
+	}
+
+	query, _ := url.ParseQuery(string(c.Request().URI().QueryString()))
+
+	return &matchers.Request{
+		Method:  c.Method(),
+		Path:    c.Path(),
+		Headers: headers,
+		Query:   query,
+		Body:    c.Body(),
+	}
 }
 
 // Store a project endpoint request
 func (service *ProjectEndpointRequestService) Store(ctx context.Context, request *entities.ProjectEndpointRequest) error {
-	ctx, span := service.tracer.Start(ctx)
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
 	if err := service.projectEndpointRequestRepository.Store(ctx, request); err != nil {
@@ -129,6 +789,16 @@ func (service *ProjectEndpointRequestService) Store(ctx context.Context, request
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
 	}
 
+	// expired requests are deleted when a new request is stored so that the request log stays within the retention of the subscription
+	if err := service.deleteExpiredRequests(ctx, request.UserID); err != nil {
+		msg := fmt.Sprintf("cannot delete expired requests for user with ID [%s]", request.UserID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+	}
+
+	if request.Passthrough || request.Unmatched {
+		return nil
+	}
+
 	if err := service.projectEndpointRepository.IncreaseRequestCount(ctx, request.ProjectEndpointID); err != nil {
 		msg := fmt.Sprintf("cannot register request for [%T] with ID [%s] for project with ID [%s] and user with ID [%s]", &entities.ProjectEndpoint{}, request.ProjectEndpointID, request.ProjectID, request.UserID)
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
@@ -137,15 +807,73 @@ func (service *ProjectEndpointRequestService) Store(ctx context.Context, request
 	return nil
 }
 
-func (service *ProjectEndpointRequestService) getHTTPHeaders(ctxLogger telemetry.Logger, c *fiber.Ctx, endpoint *entities.ProjectEndpoint) []map[string]string {
+const (
+	// expiredRequestsBatchSize is the maximum number of expired requests which are deleted when a new request is stored
+	expiredRequestsBatchSize = 100
+
+	// expiredRequestsInterval is the minimum time between two deletions of the expired requests of a user once they are all deleted
+	expiredRequestsInterval = time.Minute
+
+	// expiredRequestsCacheSize is the maximum number of users whose last deletion of expired requests is kept in memory
+	expiredRequestsCacheSize = 10000
+)
+
+// deleteExpiredRequests deletes the entities.ProjectEndpointRequest which are older than the request retention of the subscription of a user.
+// It is skipped for expiredRequestsInterval after a deletion which did not fill a batch so that busy endpoints do not query the retention on every request.
+func (service *ProjectEndpointRequestService) deleteExpiredRequests(ctx context.Context, userID entities.UserID) error {
+	if deletedAt, ok := service.expiredRequestsDeletedAt.Get(userID); ok && time.Since(deletedAt) < expiredRequestsInterval {
+		return nil
+	}
+
+	retention, err := service.subscriptionService.RequestRetention(ctx, userID)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot load request retention for user with ID [%s]", userID))
+	}
+
+	if retention == 0 {
+		service.expiredRequestsDeletedAt.Add(userID, time.Now())
+		return nil
+	}
+
+	requests, err := service.projectEndpointRequestRepository.DeleteBefore(ctx, userID, time.Now().UTC().Add(-retention), expiredRequestsBatchSize)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot delete requests older than [%s] for user with ID [%s]", retention, userID))
+	}
+
+	if len(requests) < expiredRequestsBatchSize {
+		service.expiredRequestsDeletedAt.Add(userID, time.Now())
+	}
+
+	// the endpoint of an expired request may have been deleted so every request count is decreased before returning the errors
+	var failures []error
+	for _, request := range requests {
+		if err = service.callbackService.Delete(ctx, userID, request.ID); err != nil {
+			msg := fmt.Sprintf("cannot delete callback attempts of expired request [%s]", request.ID)
+			failures = append(failures, stacktrace.Propagate(err, msg))
+		}
+
+		if request.Passthrough || request.Unmatched {
+			continue
+		}
+
+		if err = service.projectEndpointRepository.DecreaseRequestCount(ctx, request.ProjectEndpointID); err != nil {
+			msg := fmt.Sprintf("cannot decrease request count for endpoint with ID [%s] after deleting expired request [%s]", request.ProjectEndpointID, request.ID)
+			failures = append(failures, stacktrace.Propagate(err, msg))
+		}
+	}
+
+	return errors.Join(failures...)
+}
+
+func (service *ProjectEndpointRequestService) getHTTPHeaders(ctxLogger telemetry.Logger, c *fiber.Ctx, responseHeaders *string) []map[string]string {
 	var headers []map[string]string
 
-	if endpoint.ResponseHeaders == nil || *endpoint.ResponseHeaders == "" {
+	if responseHeaders == nil || *responseHeaders == "" {
 		return headers
 	}
 
-	if err := json.Unmarshal([]byte(*endpoint.ResponseHeaders), &headers); err != nil {
-		msg := fmt.Sprintf("error while unmarshalling response headers [%s] for request [%s] with method [%s]", *endpoint.ResponseHeaders, c.BaseURL()+c.OriginalURL(), c.Method())
+	if err := json.Unmarshal([]byte(*responseHeaders), &headers); err != nil {
+		msg := fmt.Sprintf("error while unmarshalling response headers [%s] for request [%s] with method [%s]", *responseHeaders, c.BaseURL()+c.OriginalURL(), c.Method())
 		ctxLogger.Error(stacktrace.Propagate(err, msg))
 	}
 
@@ -158,17 +886,13 @@ func (service *ProjectEndpointRequestService) storeProjectEndpointRequestEvent(
 	stopwatch time.Time,
 	c *fiber.Ctx,
 	endpoint *entities.ProjectEndpoint,
+	response *httpResponse,
 ) {
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
-	requestBody := c.Body()
-	if len(requestBody) == 0 {
-		requestBody = nil
-	}
-
 	source := c.BaseURL() + c.OriginalURL()
-	event, err := service.createEvent(events.ProjectEndpointRequest, source, &events.ProjectEndpointRequestPayload{
+	service.dispatchProjectEndpointRequestEvent(ctx, ctxLogger, source, &events.ProjectEndpointRequestPayload{
 		UserID:                      endpoint.UserID,
 		ProjectID:                   endpoint.ProjectID,
 		ProjectEndpointID:           endpoint.ID,
@@ -177,25 +901,36 @@ func (service *ProjectEndpointRequestService) storeProjectEndpointRequestEvent(
 		RequestMethod:               c.Method(),
 		RequestBody:                 service.getRequestBody(c),
 		RequestHeaders:              service.getRequestHeaders(ctxLogger, c),
-		ResponseCode:                endpoint.ResponseCode,
-		ResponseBody:                endpoint.ResponseBody,
-		ResponseHeaders:             endpoint.ResponseHeaders,
-		ResponseDelayInMilliseconds: endpoint.ResponseDelayInMilliseconds,
+		ResponseCode:                response.code,
+		ResponseBody:                response.body,
+		ResponseHeaders:             response.headersString,
+		ResponseDelayInMilliseconds: uint(response.delay.Milliseconds()),
+		ResponseFault:               service.getFaultType(response),
+		ResponseIndex:               response.index,
 		RequestIPAddress:            c.IP(),
 		Timestamp:                   stopwatch,
 	})
+}
+
+func (service *ProjectEndpointRequestService) dispatchProjectEndpointRequestEvent(ctx context.Context, ctxLogger telemetry.Logger, source string, payload *events.ProjectEndpointRequestPayload) {
+	event, err := service.createEvent(events.ProjectEndpointRequest, source, payload)
 	if err != nil {
-		msg := fmt.Sprintf("cannot create [%s] event for  project endpiont request with ID [%s]", events.ProjectEndpointRequest, requestID)
-		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		msg := fmt.Sprintf("cannot create [%s] event for  project endpiont request with ID [%s]", events.ProjectEndpointRequest, payload.ProjectEndpointRequestID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
 		return
 	}
 
 	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
-		msg := fmt.Sprintf("cannot dispatch [%s] event for project endpiont request with ID [%s]", event.Type(), requestID)
-		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+		msg := fmt.Sprintf("cannot dispatch [%s] event for project endpiont request with ID [%s]", event.Type(), payload.ProjectEndpointRequestID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
 	}
+}
 
-	return
+func (service *ProjectEndpointRequestService) getFaultType(response *httpResponse) *entities.ProjectEndpointFaultType {
+	if response.fault == nil {
+		return nil
+	}
+	return &response.fault.Type
 }
 
 func (service *ProjectEndpointRequestService) getRequestHeaders(ctxLogger telemetry.Logger, c *fiber.Ctx) *string {
@@ -2,10 +2,12 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/matchers"
 	"github.com/NdoleStudio/httpmock/pkg/repositories"
 	"github.com/NdoleStudio/httpmock/pkg/telemetry"
 	"github.com/google/uuid"
@@ -19,6 +21,7 @@ type ProjectEndpointService struct {
 	tracer                           telemetry.Tracer
 	repository                       repositories.ProjectEndpointRepository
 	projectEndpointRequestRepository repositories.ProjectEndpointRequestRepository
+	subscriptionService              *SubscriptionService
 }
 
 // NewProjectEndpointService creates a new ProjectEndpointService
@@ -27,12 +30,14 @@ func NewProjectEndpointService(
 	tracer telemetry.Tracer,
 	repository repositories.ProjectEndpointRepository,
 	projectEndpointRequestRepository repositories.ProjectEndpointRequestRepository,
+	subscriptionService *SubscriptionService,
 ) (s *ProjectEndpointService) {
 	return &ProjectEndpointService{
 		logger:                           logger.WithCodeNamespace(fmt.Sprintf("%T", s)),
 		tracer:                           tracer,
 		projectEndpointRequestRepository: projectEndpointRequestRepository,
 		repository:                       repository,
+		subscriptionService:              subscriptionService,
 	}
 }
 
@@ -82,10 +87,20 @@ func (service *ProjectEndpointService) Index(ctx context.Context, userID entitie
 type ProjectEndpointStoreParams struct {
 	RequestMethod               string
 	RequestPath                 string
+	RequestConditions           []*entities.ProjectEndpointCondition
+	ScenarioName                string
+	ScenarioRequiredState       string
+	ScenarioNewState            string
+	Priority                    uint
 	ResponseCode                uint
 	ResponseBody                *string
 	ResponseHeaders             *string
+	Responses                   []*entities.ProjectEndpointResponse
+	ResponseMode                entities.ProjectEndpointResponseMode
+	ResponseTemplateEnabled     bool
 	ResponseDelayInMilliseconds uint
+	Faults                      []*entities.ProjectEndpointFault
+	Callbacks                   []*entities.ProjectEndpointCallback
 	Description                 *string
 
 	ProjectID uuid.UUID
@@ -97,16 +112,41 @@ func (service *ProjectEndpointService) Store(ctx context.Context, project *entit
 	ctx, span, _ := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
+	if err := service.subscriptionService.CheckEndpointLimit(ctx, params.UserID, params.ProjectID, 1); err != nil {
+		msg := fmt.Sprintf("cannot store project endpoint for user with ID [%s] and project ID [%s]", params.UserID, params.ProjectID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	endpoint, err := service.store(ctx, project, params)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, err)
+	}
+
+	return endpoint, nil
+}
+
+// store a new entities.ProjectEndpoint without checking the limits of the subscription
+func (service *ProjectEndpointService) store(ctx context.Context, project *entities.Project, params *ProjectEndpointStoreParams) (*entities.ProjectEndpoint, error) {
 	endpoint := &entities.ProjectEndpoint{
 		ID:                          uuid.New(),
 		UserID:                      params.UserID,
 		ProjectID:                   params.ProjectID,
 		RequestMethod:               params.RequestMethod,
 		RequestPath:                 params.RequestPath,
+		RequestConditions:           params.RequestConditions,
+		ScenarioName:                params.ScenarioName,
+		ScenarioRequiredState:       params.ScenarioRequiredState,
+		ScenarioNewState:            params.ScenarioNewState,
+		Priority:                    params.Priority,
 		ResponseCode:                params.ResponseCode,
 		ResponseBody:                params.ResponseBody,
 		ResponseDelayInMilliseconds: params.ResponseDelayInMilliseconds,
+		Faults:                      params.Faults,
+		Callbacks:                   params.Callbacks,
 		ResponseHeaders:             params.ResponseHeaders,
+		Responses:                   params.Responses,
+		ResponseMode:                params.ResponseMode,
+		ResponseTemplateEnabled:     params.ResponseTemplateEnabled,
 		ProjectSubdomain:            project.Subdomain,
 		Description:                 params.Description,
 		RequestCount:                0,
@@ -116,20 +156,283 @@ func (service *ProjectEndpointService) Store(ctx context.Context, project *entit
 
 	if err := service.repository.Store(ctx, endpoint); err != nil {
 		msg := fmt.Sprintf("could store project endpoint for user with ID [%s] and project ID [%s]", params.UserID, params.ProjectID)
-		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		return nil, stacktrace.Propagate(err, msg)
 	}
 
 	return endpoint, nil
 }
 
+// Import stores the entities.ProjectEndpoint generated from an OpenAPI document
+func (service *ProjectEndpointService) Import(ctx context.Context, project *entities.Project, params []*ProjectEndpointStoreParams) ([]*entities.ProjectEndpoint, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	// the limit is checked for all the endpoints so that an import is not partially stored
+	if err := service.subscriptionService.CheckEndpointLimit(ctx, project.UserID, project.ID, uint(len(params))); err != nil {
+		msg := fmt.Sprintf("cannot import [%d] endpoints into project [%s]", len(params), project.ID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	endpoints := make([]*entities.ProjectEndpoint, 0, len(params))
+	for _, param := range params {
+		endpoint, err := service.store(ctx, project, param)
+		if err != nil {
+			msg := fmt.Sprintf("cannot import endpoint [%s %s] into project [%s] after storing [%d] endpoints", param.RequestMethod, param.RequestPath, project.ID, len(endpoints))
+			return endpoints, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	ctxLogger.Info(fmt.Sprintf("imported [%d] endpoints into project [%s] for user [%s]", len(endpoints), project.ID, project.UserID))
+	return endpoints, nil
+}
+
+// ProjectEndpointSyncResult contains the changes made to the entities.ProjectEndpoint of a project during a sync
+type ProjectEndpointSyncResult struct {
+	DryRun    bool                         `json:"dry_run" example:"true"`
+	Created   []*entities.ProjectEndpoint  `json:"created"`
+	Updated   []*ProjectEndpointSyncUpdate `json:"updated"`
+	Deleted   []*entities.ProjectEndpoint  `json:"deleted"`
+	Unchanged uint                         `json:"unchanged" example:"3"`
+}
+
+// ProjectEndpointSyncUpdate is an entities.ProjectEndpoint which is updated during a sync
+type ProjectEndpointSyncUpdate struct {
+	Endpoint *entities.ProjectEndpoint `json:"endpoint"`
+	Fields   []string                  `json:"fields" example:"response_body"`
+}
+
+// Sync makes the entities.ProjectEndpoint of a project identical to the params.
+// Endpoints are identified by their method, path, request conditions and scenario state.
+// When dryRun is true, the changes are computed without being applied.
+func (service *ProjectEndpointService) Sync(ctx context.Context, project *entities.Project, params []*ProjectEndpointStoreParams, dryRun bool) (*ProjectEndpointSyncResult, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	existing, err := service.repository.Fetch(ctx, project.UserID, project.ID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch endpoints for project [%s] and user [%s]", project.ID, project.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	// endpoints which are not in the params are deleted so the project ends up with one endpoint per param
+	if len(params) > len(existing) {
+		if err = service.subscriptionService.CheckEndpointTotal(ctx, project.UserID, uint(len(params))); err != nil {
+			msg := fmt.Sprintf("cannot sync [%d] endpoints into project [%s]", len(params), project.ID)
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+		}
+	}
+
+	result := &ProjectEndpointSyncResult{
+		DryRun:  dryRun,
+		Created: []*entities.ProjectEndpoint{},
+		Updated: []*ProjectEndpointSyncUpdate{},
+		Deleted: []*entities.ProjectEndpoint{},
+	}
+
+	matched := map[uuid.UUID]bool{}
+	for _, param := range params {
+		endpoint := service.findSyncEndpoint(existing, matched, param)
+		if endpoint == nil {
+			created, err := service.syncCreate(ctx, project, param, dryRun)
+			if err != nil {
+				return result, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot sync project [%s]", project.ID)))
+			}
+			result.Created = append(result.Created, created)
+			continue
+		}
+
+		matched[endpoint.ID] = true
+		fields := service.changedFields(endpoint, param)
+		if len(fields) == 0 {
+			result.Unchanged++
+			continue
+		}
+
+		updated, err := service.syncUpdate(ctx, endpoint, param, dryRun)
+		if err != nil {
+			return result, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot sync project [%s]", project.ID)))
+		}
+		result.Updated = append(result.Updated, &ProjectEndpointSyncUpdate{Endpoint: updated, Fields: fields})
+	}
+
+	for _, endpoint := range existing {
+		if matched[endpoint.ID] {
+			continue
+		}
+
+		if !dryRun {
+			if err = service.repository.Delete(ctx, endpoint); err != nil {
+				msg := fmt.Sprintf("cannot delete endpoint [%s] while syncing project [%s]", endpoint.ID, project.ID)
+				return result, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+			}
+		}
+		result.Deleted = append(result.Deleted, endpoint)
+	}
+
+	ctxLogger.Info(fmt.Sprintf("synced project [%s] with dry run [%t]: created [%d], updated [%d], deleted [%d] and unchanged [%d]", project.ID, dryRun, len(result.Created), len(result.Updated), len(result.Deleted), result.Unchanged))
+	return result, nil
+}
+
+func (service *ProjectEndpointService) findSyncEndpoint(endpoints []*entities.ProjectEndpoint, matched map[uuid.UUID]bool, params *ProjectEndpointStoreParams) *entities.ProjectEndpoint {
+	candidate := &entities.ProjectEndpoint{
+		RequestMethod:         params.RequestMethod,
+		RequestPath:           params.RequestPath,
+		RequestConditions:     params.RequestConditions,
+		ScenarioName:          params.ScenarioName,
+		ScenarioRequiredState: params.ScenarioRequiredState,
+	}
+
+	for _, endpoint := range endpoints {
+		if !matched[endpoint.ID] && endpoint.RequestMethod == candidate.RequestMethod && matchers.Conflicts(candidate, endpoint) {
+			return endpoint
+		}
+	}
+
+	return nil
+}
+
+func (service *ProjectEndpointService) syncCreate(ctx context.Context, project *entities.Project, params *ProjectEndpointStoreParams, dryRun bool) (*entities.ProjectEndpoint, error) {
+	if !dryRun {
+		return service.store(ctx, project, params)
+	}
+
+	return &entities.ProjectEndpoint{
+		ProjectID:                   project.ID,
+		ProjectSubdomain:            project.Subdomain,
+		UserID:                      project.UserID,
+		RequestMethod:               params.RequestMethod,
+		RequestPath:                 params.RequestPath,
+		RequestConditions:           params.RequestConditions,
+		ScenarioName:                params.ScenarioName,
+		ScenarioRequiredState:       params.ScenarioRequiredState,
+		ScenarioNewState:            params.ScenarioNewState,
+		Priority:                    params.Priority,
+		ResponseCode:                params.ResponseCode,
+		ResponseBody:                params.ResponseBody,
+		ResponseHeaders:             params.ResponseHeaders,
+		Responses:                   params.Responses,
+		ResponseMode:                params.ResponseMode,
+		ResponseTemplateEnabled:     params.ResponseTemplateEnabled,
+		ResponseDelayInMilliseconds: params.ResponseDelayInMilliseconds,
+		Faults:                      params.Faults,
+		Callbacks:                   params.Callbacks,
+		Description:                 params.Description,
+	}, nil
+}
+
+func (service *ProjectEndpointService) syncUpdate(ctx context.Context, endpoint *entities.ProjectEndpoint, params *ProjectEndpointStoreParams, dryRun bool) (*entities.ProjectEndpoint, error) {
+	updateParams := &ProjectEndpointUpdateParams{
+		RequestMethod:               params.RequestMethod,
+		RequestPath:                 params.RequestPath,
+		RequestConditions:           params.RequestConditions,
+		ScenarioName:                params.ScenarioName,
+		ScenarioRequiredState:       params.ScenarioRequiredState,
+		ScenarioNewState:            params.ScenarioNewState,
+		Priority:                    params.Priority,
+		ResponseCode:                params.ResponseCode,
+		ResponseBody:                params.ResponseBody,
+		ResponseHeaders:             params.ResponseHeaders,
+		Responses:                   params.Responses,
+		ResponseMode:                params.ResponseMode,
+		ResponseTemplateEnabled:     params.ResponseTemplateEnabled,
+		ResponseDelayInMilliseconds: params.ResponseDelayInMilliseconds,
+		Faults:                      params.Faults,
+		Callbacks:                   params.Callbacks,
+		Description:                 params.Description,
+		ProjectEndpointID:           endpoint.ID,
+		ProjectID:                   endpoint.ProjectID,
+		UserID:                      endpoint.UserID,
+	}
+
+	if !dryRun {
+		return service.Update(ctx, updateParams)
+	}
+
+	preview := *endpoint
+	preview.RequestPath = params.RequestPath
+	preview.ScenarioNewState = params.ScenarioNewState
+	preview.Priority = params.Priority
+	preview.ResponseCode = params.ResponseCode
+	preview.ResponseBody = params.ResponseBody
+	preview.ResponseHeaders = params.ResponseHeaders
+	preview.Responses = params.Responses
+	preview.ResponseMode = params.ResponseMode
+	preview.ResponseTemplateEnabled = params.ResponseTemplateEnabled
+	preview.ResponseDelayInMilliseconds = params.ResponseDelayInMilliseconds
+	preview.Faults = params.Faults
+	preview.Callbacks = params.Callbacks
+	preview.Description = params.Description
+	return &preview, nil
+}
+
+// changedFields returns the JSON names of the fields which are different between an entities.ProjectEndpoint and the params
+func (service *ProjectEndpointService) changedFields(endpoint *entities.ProjectEndpoint, params *ProjectEndpointStoreParams) []string {
+	var fields []string
+	for _, field := range []struct {
+		name    string
+		current any
+		desired any
+	}{
+		{"request_path", endpoint.RequestPath, params.RequestPath},
+		{"scenario_new_state", endpoint.ScenarioNewState, params.ScenarioNewState},
+		{"priority", endpoint.Priority, params.Priority},
+		{"response_code", endpoint.ResponseCode, params.ResponseCode},
+		{"response_body", service.stringValue(endpoint.ResponseBody), service.stringValue(params.ResponseBody)},
+		{"response_headers", service.stringValue(endpoint.ResponseHeaders), service.stringValue(params.ResponseHeaders)},
+		{"responses", endpoint.Responses, params.Responses},
+		{"response_mode", endpoint.ResponseMode, params.ResponseMode},
+		{"response_template_enabled", endpoint.ResponseTemplateEnabled, params.ResponseTemplateEnabled},
+		{"response_delay_in_milliseconds", endpoint.ResponseDelayInMilliseconds, params.ResponseDelayInMilliseconds},
+		{"faults", endpoint.Faults, params.Faults},
+		{"callbacks", endpoint.Callbacks, params.Callbacks},
+		{"description", service.stringValue(endpoint.Description), service.stringValue(params.Description)},
+	} {
+		if !service.equalJSON(field.current, field.desired) {
+			fields = append(fields, field.name)
+		}
+	}
+	return fields
+}
+
+// equalJSON compares 2 values using their JSON encoding so that nil and empty slices are equal
+func (service *ProjectEndpointService) equalJSON(current any, desired any) bool {
+	currentJSON, _ := json.Marshal(current)
+	desiredJSON, _ := json.Marshal(desired)
+	if string(currentJSON) == "[]" {
+		currentJSON = []byte("null")
+	}
+	if string(desiredJSON) == "[]" {
+		desiredJSON = []byte("null")
+	}
+	return string(currentJSON) == string(desiredJSON)
+}
+
+func (service *ProjectEndpointService) stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
 // ProjectEndpointUpdateParams are the parameters for updating a project endpoint.
 type ProjectEndpointUpdateParams struct {
 	RequestMethod               string
 	RequestPath                 string
+	RequestConditions           []*entities.ProjectEndpointCondition
+	ScenarioName                string
+	ScenarioRequiredState       string
+	ScenarioNewState            string
+	Priority                    uint
 	ResponseCode                uint
 	ResponseBody                *string
 	ResponseHeaders             *string
+	Responses                   []*entities.ProjectEndpointResponse
+	ResponseMode                entities.ProjectEndpointResponseMode
+	ResponseTemplateEnabled     bool
 	ResponseDelayInMilliseconds uint
+	Faults                      []*entities.ProjectEndpointFault
+	Callbacks                   []*entities.ProjectEndpointCallback
 	Description                 *string
 
 	ProjectEndpointID uuid.UUID
@@ -150,10 +453,21 @@ func (service *ProjectEndpointService) Update(ctx context.Context, params *Proje
 
 	endpoint.RequestMethod = params.RequestMethod
 	endpoint.RequestPath = params.RequestPath
+	endpoint.RequestConditions = params.RequestConditions
+	endpoint.ScenarioName = params.ScenarioName
+	endpoint.ScenarioRequiredState = params.ScenarioRequiredState
+	endpoint.ScenarioNewState = params.ScenarioNewState
+	endpoint.Priority = params.Priority
 	endpoint.ResponseCode = params.ResponseCode
 	endpoint.ResponseBody = params.ResponseBody
 	endpoint.ResponseHeaders = params.ResponseHeaders
+	endpoint.Responses = params.Responses
+	endpoint.ResponseMode = params.ResponseMode
+	endpoint.ResponseCounter = 0
+	endpoint.ResponseTemplateEnabled = params.ResponseTemplateEnabled
 	endpoint.ResponseDelayInMilliseconds = params.ResponseDelayInMilliseconds
+	endpoint.Faults = params.Faults
+	endpoint.Callbacks = params.Callbacks
 	endpoint.Description = params.Description
 	endpoint.UpdatedAt = time.Now().UTC()
 
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/exporters"
+	"github.com/NdoleStudio/httpmock/pkg/repositories"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// ProjectExportFormat is the format used to export an entities.Project
+type ProjectExportFormat string
+
+const (
+	// ProjectExportFormatOpenAPI exports the endpoints of a project as an OpenAPI 3.0 document
+	ProjectExportFormatOpenAPI = ProjectExportFormat("openapi")
+
+	// ProjectExportFormatPostman exports the endpoints of a project as a Postman v2.1 collection
+	ProjectExportFormatPostman = ProjectExportFormat("postman")
+
+	// ProjectExportFormatHAR exports the recorded requests of a project as a HAR file
+	ProjectExportFormatHAR = ProjectExportFormat("har")
+)
+
+// maxExportRequests is the maximum number of entities.ProjectEndpointRequest in an exported HAR file
+const maxExportRequests = 1000
+
+// ProjectExportService is responsible for exporting an entities.Project
+type ProjectExportService struct {
+	service
+	logger                           telemetry.Logger
+	tracer                           telemetry.Tracer
+	hostname                         string
+	repository                       repositories.ProjectRepository
+	projectEndpointRepository        repositories.ProjectEndpointRepository
+	projectEndpointRequestRepository repositories.ProjectEndpointRequestRepository
+}
+
+// NewProjectExportService creates a new ProjectExportService
+func NewProjectExportService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	hostname string,
+	repository repositories.ProjectRepository,
+	projectEndpointRepository repositories.ProjectEndpointRepository,
+	projectEndpointRequestRepository repositories.ProjectEndpointRequestRepository,
+) (s *ProjectExportService) {
+	return &ProjectExportService{
+		logger:                           logger.WithCodeNamespace(fmt.Sprintf("%T", s)),
+		tracer:                           tracer,
+		hostname:                         hostname,
+		repository:                       repository,
+		projectEndpointRepository:        projectEndpointRepository,
+		projectEndpointRequestRepository: projectEndpointRequestRepository,
+	}
+}
+
+// Export an entities.Project in the given ProjectExportFormat
+func (service *ProjectExportService) Export(ctx context.Context, userID entities.UserID, projectID uuid.UUID, format ProjectExportFormat) (any, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	project, err := service.repository.Load(ctx, userID, projectID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load project [%s] for user with ID [%s]", projectID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if format == ProjectExportFormatHAR {
+		requests, err := service.projectEndpointRequestRepository.FetchLatest(ctx, userID, projectID, maxExportRequests)
+		if err != nil {
+			msg := fmt.Sprintf("cannot fetch requests for project [%s] and user with ID [%s]", projectID, userID)
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+		return exporters.HTTPArchive(requests), nil
+	}
+
+	endpoints, err := service.projectEndpointRepository.Fetch(ctx, userID, projectID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch endpoints for project [%s] and user with ID [%s]", projectID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	switch format {
+	case ProjectExportFormatOpenAPI:
+		return exporters.OpenAPI(project, service.baseURL(project), endpoints), nil
+	case ProjectExportFormatPostman:
+		return exporters.Postman(project, service.baseURL(project), endpoints), nil
+	default:
+		msg := fmt.Sprintf("cannot export project [%s] with unsupported format [%s]", projectID, format)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewError(msg))
+	}
+}
+
+func (service *ProjectExportService) baseURL(project *entities.Project) string {
+	return fmt.Sprintf("https://%s.%s", project.Subdomain, service.hostname)
+}
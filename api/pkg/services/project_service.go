@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"time"
 
 	"github.com/NdoleStudio/httpmock/pkg/entities"
@@ -21,6 +23,11 @@ type ProjectService struct {
 	repository                       repositories.ProjectRepository
 	eventDispatcher                  *EventDispatcher
 	projectEndpointRequestRepository repositories.ProjectEndpointRequestRepository
+	projectEndpointRepository        repositories.ProjectEndpointRepository
+	callbackAttemptRepository        repositories.ProjectEndpointCallbackAttemptRepository
+	webhookRepository                repositories.ProjectWebhookRepository
+	webhookDeliveryRepository        repositories.ProjectWebhookDeliveryRepository
+	subscriptionService              *SubscriptionService
 }
 
 // NewProjectService creates a new ProjectService
@@ -29,14 +36,24 @@ func NewProjectService(
 	tracer telemetry.Tracer,
 	eventDispatcher *EventDispatcher,
 	projectEndpointRequestRepository repositories.ProjectEndpointRequestRepository,
+	projectEndpointRepository repositories.ProjectEndpointRepository,
+	callbackAttemptRepository repositories.ProjectEndpointCallbackAttemptRepository,
+	webhookRepository repositories.ProjectWebhookRepository,
+	webhookDeliveryRepository repositories.ProjectWebhookDeliveryRepository,
 	repository repositories.ProjectRepository,
+	subscriptionService *SubscriptionService,
 ) (s *ProjectService) {
 	return &ProjectService{
 		logger:                           logger.WithCodeNamespace(fmt.Sprintf("%T", s)),
 		tracer:                           tracer,
 		eventDispatcher:                  eventDispatcher,
 		projectEndpointRequestRepository: projectEndpointRequestRepository,
+		projectEndpointRepository:        projectEndpointRepository,
+		callbackAttemptRepository:        callbackAttemptRepository,
+		webhookRepository:                webhookRepository,
+		webhookDeliveryRepository:        webhookDeliveryRepository,
 		repository:                       repository,
+		subscriptionService:              subscriptionService,
 	}
 }
 
@@ -68,6 +85,121 @@ func (service *ProjectService) Load(ctx context.Context, userID entities.UserID,
 	return project, nil
 }
 
+// Scenarios returns the current state of all the scenarios used by the endpoints of an entities.Project
+func (service *ProjectService) Scenarios(ctx context.Context, userID entities.UserID, projectID uuid.UUID) ([]*entities.ProjectScenario, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	project, err := service.repository.Load(ctx, userID, projectID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load project for user with ID [%s] and projectID [%s]", userID, projectID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	endpoints, err := service.projectEndpointRepository.Fetch(ctx, userID, projectID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch endpoints for user with ID [%s] and projectID [%s]", userID, projectID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	scenarios := map[string]*entities.ProjectScenario{}
+	var names []string
+	for _, endpoint := range endpoints {
+		if endpoint.ScenarioName == "" {
+			continue
+		}
+
+		scenario, ok := scenarios[endpoint.ScenarioName]
+		if !ok {
+			scenario = &entities.ProjectScenario{
+				Name:   endpoint.ScenarioName,
+				State:  project.ScenarioState(endpoint.ScenarioName),
+				States: []string{entities.ProjectScenarioStateStarted},
+			}
+			scenarios[endpoint.ScenarioName] = scenario
+			names = append(names, endpoint.ScenarioName)
+		}
+
+		for _, state := range []string{endpoint.ScenarioRequiredState, endpoint.ScenarioNewState} {
+			if state != "" && !slices.Contains(scenario.States, state) {
+				scenario.States = append(scenario.States, state)
+			}
+		}
+	}
+
+	sort.Strings(names)
+	result := make([]*entities.ProjectScenario, 0, len(names))
+	for _, name := range names {
+		result = append(result, scenarios[name])
+	}
+
+	return result, nil
+}
+
+// ResetScenarios resets all the scenarios of an entities.Project to their initial state
+func (service *ProjectService) ResetScenarios(ctx context.Context, userID entities.UserID, projectID uuid.UUID) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, projectID); err != nil {
+		msg := fmt.Sprintf("cannot load project [%s] for user ID [%s]", projectID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.repository.ResetScenarioStates(ctx, projectID); err != nil {
+		msg := fmt.Sprintf("cannot reset scenarios for project [%s] and user ID [%s]", projectID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// Record starts recording the upstream responses of an entities.Project as new endpoints
+func (service *ProjectService) Record(ctx context.Context, userID entities.UserID, projectID uuid.UUID) (*entities.Project, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	project, err := service.updateRecording(ctx, userID, projectID, true)
+	if err != nil {
+		msg := fmt.Sprintf("cannot start recording for project [%s] and user ID [%s]", projectID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	return project, nil
+}
+
+// Playback stops recording the upstream responses of an entities.Project so that the recorded endpoints are served
+func (service *ProjectService) Playback(ctx context.Context, userID entities.UserID, projectID uuid.UUID) (*entities.Project, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	project, err := service.updateRecording(ctx, userID, projectID, false)
+	if err != nil {
+		msg := fmt.Sprintf("cannot stop recording for project [%s] and user ID [%s]", projectID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	return project, nil
+}
+
+func (service *ProjectService) updateRecording(ctx context.Context, userID entities.UserID, projectID uuid.UUID, enabled bool) (*entities.Project, error) {
+	project, err := service.repository.Load(ctx, userID, projectID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load project [%s] for user ID [%s]", projectID, userID)
+		return nil, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg)
+	}
+
+	project.RecordingEnabled = enabled
+	project.UpdatedAt = time.Now().UTC()
+
+	if err = service.repository.Update(ctx, project); err != nil {
+		msg := fmt.Sprintf("cannot update project [%s] for user ID [%s] with recording enabled [%t]", projectID, userID, enabled)
+		return nil, stacktrace.Propagate(err, msg)
+	}
+
+	return project, nil
+}
+
 // Index fetches all entities.Project for an authenticated user
 func (service *ProjectService) Index(ctx context.Context, userID entities.UserID) ([]*entities.Project, error) {
 	ctx, span := service.tracer.Start(ctx)
@@ -87,6 +219,7 @@ type ProjectCreateParams struct {
 	Name        string
 	Description string
 	Subdomain   string
+	UpstreamURL string
 	Source      string
 	UserID      entities.UserID
 }
@@ -96,12 +229,18 @@ func (service *ProjectService) Create(ctx context.Context, params *ProjectCreate
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
+	if err := service.subscriptionService.CheckProjectLimit(ctx, params.UserID); err != nil {
+		msg := fmt.Sprintf("cannot create project [%s] for user with ID [%s]", params.Name, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
 	project := &entities.Project{
 		ID:          uuid.New(),
 		UserID:      params.UserID,
 		Subdomain:   params.Subdomain,
 		Name:        params.Name,
 		Description: params.Description,
+		UpstreamURL: params.UpstreamURL,
 		CreatedAt:   time.Now().UTC(),
 		UpdatedAt:   time.Now().UTC(),
 	}
@@ -141,6 +280,7 @@ type ProjectUpdateParams struct {
 	Subdomain   string
 	Name        string
 	Description string
+	UpstreamURL string
 	Source      string
 }
 
@@ -159,6 +299,8 @@ func (service *ProjectService) Update(ctx context.Context, params *ProjectUpdate
 	project.UpdatedAt = time.Now().UTC()
 	project.Subdomain = params.Subdomain
 	project.Description = params.Description
+	project.UpstreamURL = params.UpstreamURL
+	project.RecordingEnabled = project.RecordingEnabled && params.UpstreamURL != ""
 
 	if err = service.repository.Update(ctx, project); err != nil {
 		msg := fmt.Sprintf("could update project [%s] for user with ID [%s]", project.ID, project.UserID)
@@ -222,3 +364,110 @@ func (service *ProjectService) Delete(ctx context.Context, source string, userID
 
 	return nil
 }
+
+// projectCleanupBatchSize is the maximum number of documents which are deleted at once when cleaning up a deleted entities.Project
+const projectCleanupBatchSize = 100
+
+// Cleanup deletes the endpoints, the requests, the callback attempts and the webhooks of a deleted entities.Project in batches.
+// It can be called again after a failure since only the remaining documents of the project are deleted.
+func (service *ProjectService) Cleanup(ctx context.Context, source string, userID entities.UserID, projectID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	// endpoints are deleted first so that the subdomain routes stop resolving before the request logs are deleted
+	endpoints, err := service.cleanup(ctx, ctxLogger, source, userID, projectID, events.ProjectCleanupResourceEndpoints, service.projectEndpointRepository.DeleteByProject)
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete endpoints of project [%s] for user ID [%s]", projectID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	requests, err := service.cleanup(ctx, ctxLogger, source, userID, projectID, events.ProjectCleanupResourceRequests, service.projectEndpointRequestRepository.DeleteByProject)
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete requests of project [%s] for user ID [%s]", projectID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	attempts, err := service.cleanup(ctx, ctxLogger, source, userID, projectID, events.ProjectCleanupResourceCallbackAttempts, service.callbackAttemptRepository.DeleteByProject)
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete callback attempts of project [%s] for user ID [%s]", projectID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	// webhooks are deleted before their deliveries so that no delivery is stored after the cleanup
+	webhooks, err := service.cleanup(ctx, ctxLogger, source, userID, projectID, events.ProjectCleanupResourceWebhooks, service.webhookRepository.DeleteByProject)
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete webhooks of project [%s] for user ID [%s]", projectID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	deliveries, err := service.cleanup(ctx, ctxLogger, source, userID, projectID, events.ProjectCleanupResourceWebhookDeliveries, service.webhookDeliveryRepository.DeleteByProject)
+	if err != nil {
+		msg := fmt.Sprintf("cannot delete webhook deliveries of project [%s] for user ID [%s]", projectID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted [%d] endpoints, [%d] requests, [%d] callback attempts, [%d] webhooks and [%d] webhook deliveries of project [%s]", endpoints, requests, attempts, webhooks, deliveries, projectID))
+
+	service.dispatch(ctx, ctxLogger, events.ProjectCleanupCompleted, source, projectID, &events.ProjectCleanupCompletedPayload{
+		UserID:                   userID,
+		ProjectID:                projectID,
+		EndpointsDeleted:         endpoints,
+		RequestsDeleted:          requests,
+		CallbackAttemptsDeleted:  attempts,
+		WebhooksDeleted:          webhooks,
+		WebhookDeliveriesDeleted: deliveries,
+		Timestamp:                time.Now().UTC(),
+	})
+
+	return nil
+}
+
+func (service *ProjectService) cleanup(
+	ctx context.Context,
+	ctxLogger telemetry.Logger,
+	source string,
+	userID entities.UserID,
+	projectID uuid.UUID,
+	resource string,
+	deleteByProject func(ctx context.Context, userID entities.UserID, projectID uuid.UUID, limit uint) (uint, error),
+) (uint, error) {
+	var total uint
+	for {
+		deleted, err := deleteByProject(ctx, userID, projectID, projectCleanupBatchSize)
+		if err != nil {
+			msg := fmt.Sprintf("cannot delete [%s] of project [%s] after deleting [%d]", resource, projectID, total)
+			return total, stacktrace.Propagate(err, msg)
+		}
+
+		total += deleted
+		if deleted > 0 {
+			service.dispatch(ctx, ctxLogger, events.ProjectCleanupProgress, source, projectID, &events.ProjectCleanupProgressPayload{
+				UserID:       userID,
+				ProjectID:    projectID,
+				Resource:     resource,
+				Deleted:      deleted,
+				TotalDeleted: total,
+				Timestamp:    time.Now().UTC(),
+			})
+		}
+
+		if deleted < projectCleanupBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// dispatch an event for an entities.Project, the errors are logged because the event is informational
+func (service *ProjectService) dispatch(ctx context.Context, ctxLogger telemetry.Logger, eventType string, source string, projectID uuid.UUID, payload any) {
+	event, err := service.createEvent(eventType, source, payload)
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for project [%s]", eventType, projectID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch [%s] event for project [%s]", event.Type(), projectID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+	}
+}
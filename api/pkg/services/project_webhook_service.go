@@ -0,0 +1,473 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/cache"
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/events"
+	"github.com/NdoleStudio/httpmock/pkg/repositories"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// ProjectWebhookSignatureHeader is the HTTP header which contains the signature of a webhook delivery
+	ProjectWebhookSignatureHeader = "X-Httpmock-Signature"
+
+	// webhookMaxAttempts is the number of times an event is sent to a webhook before it is discarded
+	webhookMaxAttempts = 5
+
+	// webhookRetryBackoff is the delay before the first retry of a failed delivery, it doubles after every failed attempt
+	webhookRetryBackoff = time.Second
+
+	// webhookSigningSecretPrefix is the prefix of the generated signing secrets
+	webhookSigningSecretPrefix = "whsec_"
+
+	// expiredWebhookDeliveriesBatchSize is the maximum number of expired deliveries which are deleted when a new delivery is stored
+	expiredWebhookDeliveriesBatchSize = 100
+
+	// expiredWebhookDeliveriesInterval is the minimum time between two deletions of the expired deliveries of a user once they are all deleted
+	expiredWebhookDeliveriesInterval = time.Minute
+
+	// expiredWebhookDeliveriesCacheSize is the maximum number of users whose last deletion of expired deliveries is kept in memory
+	expiredWebhookDeliveriesCacheSize = 10000
+)
+
+// ProjectWebhookService is responsible for managing entities.ProjectWebhook and delivering events to them
+type ProjectWebhookService struct {
+	service
+	logger                     telemetry.Logger
+	tracer                     telemetry.Tracer
+	httpClient                 *http.Client
+	repository                 repositories.ProjectWebhookRepository
+	deliveryRepository         repositories.ProjectWebhookDeliveryRepository
+	subscriptionService        *SubscriptionService
+	eventDispatcher            *EventDispatcher
+	expiredDeliveriesDeletedAt *cache.LRUCache[entities.UserID, time.Time]
+}
+
+// NewProjectWebhookService creates a new ProjectWebhookService
+func NewProjectWebhookService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	httpClient *http.Client,
+	repository repositories.ProjectWebhookRepository,
+	deliveryRepository repositories.ProjectWebhookDeliveryRepository,
+	subscriptionService *SubscriptionService,
+	eventDispatcher *EventDispatcher,
+) (s *ProjectWebhookService) {
+	return &ProjectWebhookService{
+		logger:                     logger.WithCodeNamespace(fmt.Sprintf("%T", s)),
+		tracer:                     tracer,
+		httpClient:                 httpClient,
+		repository:                 repository,
+		deliveryRepository:         deliveryRepository,
+		subscriptionService:        subscriptionService,
+		eventDispatcher:            eventDispatcher,
+		expiredDeliveriesDeletedAt: cache.NewLRUCache[entities.UserID, time.Time](expiredWebhookDeliveriesCacheSize),
+	}
+}
+
+// Index fetches the entities.ProjectWebhook of a project
+func (service *ProjectWebhookService) Index(ctx context.Context, userID entities.UserID, projectID uuid.UUID) ([]*entities.ProjectWebhook, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	webhooks, err := service.repository.Index(ctx, userID, projectID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch webhooks for user with ID [%s] and project ID [%s]", userID, projectID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return webhooks, nil
+}
+
+// ProjectWebhookStoreParams are the parameters for creating a new entities.ProjectWebhook
+type ProjectWebhookStoreParams struct {
+	UserID        entities.UserID
+	ProjectID     uuid.UUID
+	URL           string
+	Mode          entities.ProjectWebhookMode
+	SigningSecret string
+}
+
+// Store a new entities.ProjectWebhook, a signing secret is generated when it is not provided
+func (service *ProjectWebhookService) Store(ctx context.Context, params *ProjectWebhookStoreParams) (*entities.ProjectWebhook, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	secret, err := service.signingSecret(params.SigningSecret)
+	if err != nil {
+		msg := fmt.Sprintf("cannot generate signing secret for webhook of project [%s]", params.ProjectID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	webhook := &entities.ProjectWebhook{
+		ID:            uuid.New(),
+		ProjectID:     params.ProjectID,
+		UserID:        params.UserID,
+		URL:           params.URL,
+		Mode:          params.Mode,
+		SigningSecret: secret,
+		CreatedAt:     time.Now().UTC(),
+		UpdatedAt:     time.Now().UTC(),
+	}
+
+	if err = service.repository.Store(ctx, webhook); err != nil {
+		msg := fmt.Sprintf("cannot store webhook [%s] for project [%s] and user with ID [%s]", webhook.URL, params.ProjectID, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return webhook, nil
+}
+
+// ProjectWebhookUpdateParams are the parameters for updating an entities.ProjectWebhook
+type ProjectWebhookUpdateParams struct {
+	UserID           entities.UserID
+	ProjectID        uuid.UUID
+	ProjectWebhookID uuid.UUID
+	URL              string
+	Mode             entities.ProjectWebhookMode
+	SigningSecret    string
+}
+
+// Update an entities.ProjectWebhook, the signing secret is not changed when it is not provided
+func (service *ProjectWebhookService) Update(ctx context.Context, params *ProjectWebhookUpdateParams) (*entities.ProjectWebhook, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	webhook, err := service.repository.Load(ctx, params.UserID, params.ProjectID, params.ProjectWebhookID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load webhook [%s] for project [%s] and user with ID [%s]", params.ProjectWebhookID, params.ProjectID, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	webhook.URL = params.URL
+	webhook.Mode = params.Mode
+	if params.SigningSecret != "" {
+		webhook.SigningSecret = params.SigningSecret
+	}
+	webhook.UpdatedAt = time.Now().UTC()
+
+	if err = service.repository.Update(ctx, webhook); err != nil {
+		msg := fmt.Sprintf("cannot update webhook [%s] for project [%s] and user with ID [%s]", webhook.ID, params.ProjectID, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return webhook, nil
+}
+
+// Delete an entities.ProjectWebhook and its entities.ProjectWebhookDelivery
+func (service *ProjectWebhookService) Delete(ctx context.Context, userID entities.UserID, projectID uuid.UUID, webhookID uuid.UUID) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if err := service.repository.Delete(ctx, userID, projectID, webhookID); err != nil {
+		msg := fmt.Sprintf("cannot delete webhook [%s] for project [%s] and user with ID [%s]", webhookID, projectID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	if err := service.deliveryRepository.DeleteByWebhook(ctx, userID, webhookID); err != nil {
+		msg := fmt.Sprintf("cannot delete deliveries of webhook [%s] for user with ID [%s]", webhookID, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+// Deliveries fetches the latest entities.ProjectWebhookDelivery of an entities.ProjectWebhook
+func (service *ProjectWebhookService) Deliveries(ctx context.Context, userID entities.UserID, projectID uuid.UUID, webhookID uuid.UUID, limit uint) ([]*entities.ProjectWebhookDelivery, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, projectID, webhookID); err != nil {
+		msg := fmt.Sprintf("cannot load webhook [%s] for project [%s] and user with ID [%s]", webhookID, projectID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	deliveries, err := service.deliveryRepository.Index(ctx, userID, webhookID, limit)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch deliveries of webhook [%s] for user with ID [%s]", webhookID, userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	return deliveries, nil
+}
+
+// Schedule dispatches an events.ProjectWebhookDelivery event for every entities.ProjectWebhook of a project
+func (service *ProjectWebhookService) Schedule(ctx context.Context, userID entities.UserID, projectID uuid.UUID, event cloudevents.Event) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	webhooks, err := service.repository.Index(ctx, userID, projectID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch webhooks for user with ID [%s] and project ID [%s]", userID, projectID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	content, err := json.Marshal(event)
+	if err != nil {
+		msg := fmt.Sprintf("cannot marshal [%s] event with ID [%s]", event.Type(), event.ID())
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	for _, webhook := range webhooks {
+		payload := &events.ProjectWebhookDeliveryPayload{
+			UserID:           userID,
+			ProjectID:        projectID,
+			ProjectWebhookID: webhook.ID,
+			Event:            content,
+			Attempt:          1,
+			MaxAttempts:      webhookMaxAttempts,
+			Timestamp:        time.Now().UTC(),
+		}
+		payload.ScheduledAt = payload.Timestamp
+
+		service.dispatch(ctx, ctxLogger, event.Source(), payload)
+	}
+
+	return nil
+}
+
+// Deliver an event to an entities.ProjectWebhook and store the entities.ProjectWebhookDelivery, the queue delivers the event when it is due.
+// A failed delivery is retried with an exponential backoff until the maximum number of attempts is reached.
+func (service *ProjectWebhookService) Deliver(ctx context.Context, source string, payload *events.ProjectWebhookDeliveryPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	webhook, err := service.repository.Load(ctx, payload.UserID, payload.ProjectID, payload.ProjectWebhookID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Info(fmt.Sprintf("webhook [%s] of project [%s] has been deleted, the event is not delivered", payload.ProjectWebhookID, payload.ProjectID))
+		return nil
+	}
+	if err != nil {
+		msg := fmt.Sprintf("cannot load webhook [%s] for project [%s] and user with ID [%s]", payload.ProjectWebhookID, payload.ProjectID, payload.UserID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	event := cloudevents.NewEvent()
+	if err = json.Unmarshal(payload.Event, &event); err != nil {
+		msg := fmt.Sprintf("cannot unmarshal the event [%s] for webhook [%s]", payload.Event, webhook.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	delivery := service.send(ctx, webhook, payload.Attempt, event)
+	if err = service.deliveryRepository.Store(ctx, delivery); err != nil {
+		msg := fmt.Sprintf("cannot store attempt [%d] of event [%s] for webhook [%s]", delivery.Attempt, delivery.EventID, webhook.ID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.deleteExpiredDeliveries(ctx, payload.UserID); err != nil {
+		msg := fmt.Sprintf("cannot delete expired webhook deliveries for user with ID [%s]", payload.UserID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+	}
+
+	if delivery.Succeeded || payload.Attempt >= payload.MaxAttempts {
+		ctxLogger.Info(fmt.Sprintf("sent attempt [%d/%d] of event [%s] to webhook [%s] with success [%t]", payload.Attempt, payload.MaxAttempts, delivery.EventID, webhook.ID, delivery.Succeeded))
+		return nil
+	}
+
+	retry := *payload
+	retry.Attempt++
+	retry.Timestamp = time.Now().UTC()
+	retry.ScheduledAt = retry.Timestamp.Add(webhookRetryBackoff << (payload.Attempt - 1))
+
+	service.dispatch(ctx, ctxLogger, source, &retry)
+	return nil
+}
+
+// send an event to a webhook, the errors are saved in the entities.ProjectWebhookDelivery
+func (service *ProjectWebhookService) send(ctx context.Context, webhook *entities.ProjectWebhook, attempt uint, event cloudevents.Event) *entities.ProjectWebhookDelivery {
+	delivery := &entities.ProjectWebhookDelivery{
+		ID:               uuid.New(),
+		ProjectWebhookID: webhook.ID,
+		ProjectID:        webhook.ProjectID,
+		UserID:           webhook.UserID,
+		EventID:          event.ID(),
+		EventType:        event.Type(),
+		Attempt:          attempt,
+		RequestURL:       webhook.URL,
+		CreatedAt:        time.Now().UTC(),
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, nil)
+	if err != nil {
+		return service.failDelivery(delivery, err)
+	}
+
+	encodingCtx := binding.WithForceBinary(ctx)
+	if webhook.Mode == entities.ProjectWebhookModeStructured {
+		encodingCtx = binding.WithForceStructured(ctx)
+	}
+
+	if err = cehttp.WriteRequest(encodingCtx, binding.ToMessage(&event), request); err != nil {
+		return service.failDelivery(delivery, err)
+	}
+
+	if err = service.sign(request, webhook.SigningSecret); err != nil {
+		return service.failDelivery(delivery, err)
+	}
+
+	start := time.Now()
+	response, err := service.httpClient.Do(request)
+	delivery.DurationInMilliseconds = uint(time.Since(start).Milliseconds())
+	if err != nil {
+		return service.failDelivery(delivery, err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	code := uint(response.StatusCode)
+	delivery.ResponseCode = &code
+	delivery.Succeeded = response.StatusCode >= http.StatusOK && response.StatusCode < http.StatusMultipleChoices
+
+	content, err := io.ReadAll(io.LimitReader(response.Body, maxCallbackResponseBodySize))
+	if err != nil {
+		return service.failDelivery(delivery, err)
+	}
+
+	if len(content) > 0 {
+		responseBody := string(content)
+		delivery.ResponseBody = &responseBody
+	}
+
+	return delivery
+}
+
+// sign adds the ProjectWebhookSignatureHeader to a request. The signature is the hex encoded HMAC-SHA256 of
+// "<timestamp>.<ce headers><body>" so that the receiver can reject old deliveries. The ce headers are the "<name>:<value>\n"
+// lines of the ce-* headers sorted by lower case name, they carry the event attributes in the binary mode and are empty in the structured mode.
+func (service *ProjectWebhookService) sign(request *http.Request, secret string) error {
+	var body []byte
+	if request.Body != nil {
+		content, err := io.ReadAll(request.Body)
+		if err != nil {
+			return stacktrace.Propagate(err, "cannot read the body of the webhook request")
+		}
+		body = content
+	}
+
+	request.Body = io.NopCloser(bytes.NewReader(body))
+	request.ContentLength = int64(len(body))
+	request.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write([]byte(service.signedHeaders(request.Header)))
+	mac.Write(body)
+
+	request.Header.Set(ProjectWebhookSignatureHeader, fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil))))
+	return nil
+}
+
+// signedHeaders returns the "<name>:<value>\n" lines of the ce-* headers sorted by lower case name
+func (service *ProjectWebhookService) signedHeaders(header http.Header) string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		if strings.HasPrefix(strings.ToLower(name), "ce-") {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+
+	builder := new(strings.Builder)
+	for _, name := range names {
+		for _, value := range header.Values(name) {
+			builder.WriteString(strings.ToLower(name) + ":" + value + "\n")
+		}
+	}
+	return builder.String()
+}
+
+func (service *ProjectWebhookService) failDelivery(delivery *entities.ProjectWebhookDelivery, err error) *entities.ProjectWebhookDelivery {
+	message := err.Error()
+	delivery.Error = &message
+	delivery.Succeeded = false
+	return delivery
+}
+
+// signingSecret returns the secret or a new random secret when it is empty
+func (service *ProjectWebhookService) signingSecret(secret string) (string, error) {
+	if secret != "" {
+		return secret, nil
+	}
+
+	content := make([]byte, 24)
+	if _, err := rand.Read(content); err != nil {
+		return "", stacktrace.Propagate(err, "cannot read random bytes")
+	}
+
+	return webhookSigningSecretPrefix + hex.EncodeToString(content), nil
+}
+
+// deleteExpiredDeliveries deletes the entities.ProjectWebhookDelivery which are older than the request retention of the subscription of a user.
+// It is skipped for expiredWebhookDeliveriesInterval after a deletion which did not fill a batch so that busy webhooks do not query the retention on every delivery.
+func (service *ProjectWebhookService) deleteExpiredDeliveries(ctx context.Context, userID entities.UserID) error {
+	if deletedAt, ok := service.expiredDeliveriesDeletedAt.Get(userID); ok && time.Since(deletedAt) < expiredWebhookDeliveriesInterval {
+		return nil
+	}
+
+	retention, err := service.subscriptionService.RequestRetention(ctx, userID)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot load request retention for user with ID [%s]", userID))
+	}
+
+	if retention == 0 {
+		service.expiredDeliveriesDeletedAt.Add(userID, time.Now())
+		return nil
+	}
+
+	deleted, err := service.deliveryRepository.DeleteBefore(ctx, userID, time.Now().UTC().Add(-retention), expiredWebhookDeliveriesBatchSize)
+	if err != nil {
+		return stacktrace.Propagate(err, fmt.Sprintf("cannot delete webhook deliveries older than [%s] for user with ID [%s]", retention, userID))
+	}
+
+	if deleted < expiredWebhookDeliveriesBatchSize {
+		service.expiredDeliveriesDeletedAt.Add(userID, time.Now())
+	}
+
+	return nil
+}
+
+// dispatch an events.ProjectWebhookDelivery event which is due at the ScheduledAt time of the payload.
+// The errors are logged because the event cannot be delivered again.
+func (service *ProjectWebhookService) dispatch(ctx context.Context, ctxLogger telemetry.Logger, source string, payload *events.ProjectWebhookDeliveryPayload) {
+	event, err := service.createEvent(events.ProjectWebhookDelivery, source, payload)
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for webhook [%s]", events.ProjectWebhookDelivery, payload.ProjectWebhookID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return
+	}
+
+	if err = service.eventDispatcher.DispatchAt(ctx, event, payload.ScheduledAt); err != nil {
+		msg := fmt.Sprintf("cannot dispatch [%s] event for webhook [%s]", event.Type(), payload.ProjectWebhookID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+	}
+}
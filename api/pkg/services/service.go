@@ -9,6 +9,14 @@ import (
 	"github.com/palantir/stacktrace"
 )
 
+const (
+	// ErrCodeSubscriptionLimit is returned when an action exceeds a limit of the subscription of a user
+	ErrCodeSubscriptionLimit = stacktrace.ErrorCode(2000)
+
+	// ErrCodeRequestQuota is returned when a user has used all the mock requests of the current period
+	ErrCodeRequestQuota = stacktrace.ErrorCode(2001)
+)
+
 type service struct{}
 
 func (service *service) createEvent(eventType string, source string, payload any) (*cloudevents.Event, error) {
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/events"
+	"github.com/NdoleStudio/httpmock/pkg/repositories"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// SubscriptionService keeps the subscription of an entities.User in sync and enforces the entities.SubscriptionLimits
+type SubscriptionService struct {
+	service
+	logger                    telemetry.Logger
+	tracer                    telemetry.Tracer
+	userRepository            repositories.UserRepository
+	projectRepository         repositories.ProjectRepository
+	projectEndpointRepository repositories.ProjectEndpointRepository
+	usageRepository           repositories.UserUsageRepository
+	eventDispatcher           *EventDispatcher
+	limitsEnabled             bool
+	hardLimitPercentage       uint
+}
+
+// NewSubscriptionService creates a new SubscriptionService.
+// The limits are not enforced when limitsEnabled is false e.g. in local mode.
+// Requests are rejected when the usage reaches hardLimitPercentage of the monthly requests of a subscription.
+func NewSubscriptionService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	userRepository repositories.UserRepository,
+	projectRepository repositories.ProjectRepository,
+	projectEndpointRepository repositories.ProjectEndpointRepository,
+	usageRepository repositories.UserUsageRepository,
+	eventDispatcher *EventDispatcher,
+	limitsEnabled bool,
+	hardLimitPercentage uint,
+) (s *SubscriptionService) {
+	return &SubscriptionService{
+		logger:                    logger.WithCodeNamespace(fmt.Sprintf("%T", s)),
+		tracer:                    tracer,
+		userRepository:            userRepository,
+		projectRepository:         projectRepository,
+		projectEndpointRepository: projectEndpointRepository,
+		usageRepository:           usageRepository,
+		eventDispatcher:           eventDispatcher,
+		limitsEnabled:             limitsEnabled,
+		hardLimitPercentage:       max(hardLimitPercentage, 100),
+	}
+}
+
+// SubscriptionUpdateParams are the parameters for updating the subscription of a user
+type SubscriptionUpdateParams struct {
+	UserID               entities.UserID
+	SubscriptionID       string
+	SubscriptionName     entities.SubscriptionName
+	SubscriptionStatus   string
+	SubscriptionRenewsAt *time.Time
+	SubscriptionEndsAt   *time.Time
+}
+
+// Update the subscription of an entities.User
+func (service *SubscriptionService) Update(ctx context.Context, params *SubscriptionUpdateParams) (*entities.User, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	user, _, err := service.userRepository.LoadOrStore(ctx, entities.AuthUser{ID: params.UserID})
+	if err != nil {
+		msg := fmt.Sprintf("cannot load user with ID [%s]", params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	user.SubscriptionID = params.SubscriptionID
+	user.SubscriptionName = params.SubscriptionName
+	user.SubscriptionStatus = params.SubscriptionStatus
+	user.SubscriptionRenewsAt = params.SubscriptionRenewsAt
+	user.SubscriptionEndsAt = params.SubscriptionEndsAt
+	user.UpdatedAt = time.Now().UTC()
+
+	if err = service.userRepository.Update(ctx, user); err != nil {
+		msg := fmt.Sprintf("cannot update subscription [%s] for user with ID [%s]", params.SubscriptionID, params.UserID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("subscription [%s] of user [%s] updated to [%s] with status [%s]", user.SubscriptionID, user.ID, user.SubscriptionName, user.SubscriptionStatus))
+	return user, nil
+}
+
+// Limits returns the entities.SubscriptionLimits which currently apply to a user
+func (service *SubscriptionService) Limits(ctx context.Context, userID entities.UserID) (*entities.SubscriptionLimits, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	user, err := service.loadUser(ctx, userID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load user with ID [%s]", userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	limits := user.ActiveSubscriptionName(time.Now().UTC()).Limits()
+	return &limits, nil
+}
+
+// loadUser loads an entities.User and falls back to the free plan when the user has never subscribed
+func (service *SubscriptionService) loadUser(ctx context.Context, userID entities.UserID) (*entities.User, error) {
+	user, err := service.userRepository.Load(ctx, userID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return &entities.User{ID: userID, SubscriptionName: entities.SubscriptionNameFree}, nil
+	}
+	return user, err
+}
+
+// CheckProjectLimit returns an error with code ErrCodeSubscriptionLimit when a user cannot create another entities.Project
+func (service *SubscriptionService) CheckProjectLimit(ctx context.Context, userID entities.UserID) error {
+	if !service.limitsEnabled {
+		return nil
+	}
+
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	limits, err := service.Limits(ctx, userID)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot load limits for user [%s]", userID)))
+	}
+
+	projects, err := service.projectRepository.Fetch(ctx, userID)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot fetch projects for user [%s]", userID)))
+	}
+
+	if uint(len(projects)) >= limits.Projects {
+		msg := fmt.Sprintf("you have reached the limit of [%d] projects on your subscription, upgrade your subscription to create more projects", limits.Projects)
+		return stacktrace.NewErrorWithCode(ErrCodeSubscriptionLimit, msg)
+	}
+
+	return nil
+}
+
+// CheckEndpointLimit returns an error with code ErrCodeSubscriptionLimit when a user cannot add count entities.ProjectEndpoint to a project
+func (service *SubscriptionService) CheckEndpointLimit(ctx context.Context, userID entities.UserID, projectID uuid.UUID, count uint) error {
+	if !service.limitsEnabled {
+		return nil
+	}
+
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	endpoints, err := service.projectEndpointRepository.Fetch(ctx, userID, projectID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch endpoints for user [%s] and project [%s]", userID, projectID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.CheckEndpointTotal(ctx, userID, uint(len(endpoints))+count); err != nil {
+		msg := fmt.Sprintf("cannot add [%d] endpoints to project [%s] with [%d] endpoints", count, projectID, len(endpoints))
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCode(err, stacktrace.GetCode(err), msg))
+	}
+
+	return nil
+}
+
+// CheckEndpointTotal returns an error with code ErrCodeSubscriptionLimit when a project of a user cannot have total entities.ProjectEndpoint
+func (service *SubscriptionService) CheckEndpointTotal(ctx context.Context, userID entities.UserID, total uint) error {
+	if !service.limitsEnabled {
+		return nil
+	}
+
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	limits, err := service.Limits(ctx, userID)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot load limits for user [%s]", userID)))
+	}
+
+	if total > limits.EndpointsPerProject {
+		msg := fmt.Sprintf("you have reached the limit of [%d] endpoints per project on your subscription, upgrade your subscription to create more endpoints", limits.EndpointsPerProject)
+		return stacktrace.NewErrorWithCode(ErrCodeSubscriptionLimit, msg)
+	}
+
+	return nil
+}
+
+// SubscriptionUsage is the number of mock requests which a user made in the current billing period
+type SubscriptionUsage struct {
+	SubscriptionName entities.SubscriptionName   `json:"subscription_name" example:"10k-monthly"`
+	PeriodStart      time.Time                   `json:"period_start" example:"2022-06-05T14:26:02.302718+03:00"`
+	PeriodEnd        time.Time                   `json:"period_end" example:"2022-07-05T14:26:02.302718+03:00"`
+	RequestCount     uint                        `json:"request_count" example:"8500"`
+	SoftLimit        uint                        `json:"soft_limit" example:"10000"`
+	HardLimit        uint                        `json:"hard_limit" example:"11000"`
+	Limits           entities.SubscriptionLimits `json:"limits"`
+}
+
+// Usage returns the SubscriptionUsage of a user in the current billing period
+func (service *SubscriptionService) Usage(ctx context.Context, userID entities.UserID) (*SubscriptionUsage, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	user, err := service.loadUser(ctx, userID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load user with ID [%s]", userID)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	usage := service.usage(user, time.Now().UTC())
+
+	current, err := service.usageRepository.Load(ctx, userID, usage.PeriodStart)
+	if err != nil && stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("cannot load usage of user [%s] for period [%s]", userID, usage.PeriodStart)
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if current != nil {
+		usage.RequestCount = current.RequestCount
+	}
+
+	return usage, nil
+}
+
+// MeterRequest atomically counts a mock request of a user in the current billing period.
+// An events.UserUsageWarning event is dispatched when the usage reaches 80% and 100% of the soft limit and
+// an error with code ErrCodeRequestQuota is returned when the usage is above the hard limit.
+func (service *SubscriptionService) MeterRequest(ctx context.Context, source string, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	user, err := service.loadUser(ctx, userID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot load user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	usage := service.usage(user, time.Now().UTC())
+	usage.RequestCount, err = service.usageRepository.IncrementRequestCount(ctx, userID, usage.PeriodStart)
+	if err != nil {
+		msg := fmt.Sprintf("cannot increment request count for user [%s] and period [%s]", userID, usage.PeriodStart)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if !service.limitsEnabled {
+		return nil
+	}
+
+	// the counter is atomic so each threshold is reached by exactly one request in a period
+	for _, percentage := range []uint{80, 100} {
+		if usage.RequestCount == (usage.SoftLimit*percentage+99)/100 {
+			service.dispatchUsageWarning(ctx, ctxLogger, source, user, usage, percentage)
+		}
+	}
+
+	if usage.RequestCount > usage.HardLimit {
+		msg := fmt.Sprintf("user [%s] made [%d] requests which is above the hard limit of [%d] requests", userID, usage.RequestCount, usage.HardLimit)
+		return stacktrace.NewErrorWithCode(ErrCodeRequestQuota, msg)
+	}
+
+	return nil
+}
+
+func (service *SubscriptionService) usage(user *entities.User, now time.Time) *SubscriptionUsage {
+	name := user.ActiveSubscriptionName(now)
+	limits := name.Limits()
+	start, end := user.BillingPeriod(now)
+
+	return &SubscriptionUsage{
+		SubscriptionName: name,
+		PeriodStart:      start,
+		PeriodEnd:        end,
+		SoftLimit:        limits.MonthlyRequests,
+		HardLimit:        limits.MonthlyRequests * service.hardLimitPercentage / 100,
+		Limits:           limits,
+	}
+}
+
+func (service *SubscriptionService) dispatchUsageWarning(ctx context.Context, ctxLogger telemetry.Logger, source string, user *entities.User, usage *SubscriptionUsage, percentage uint) {
+	event, err := service.createEvent(events.UserUsageWarning, source, &events.UserUsageWarningPayload{
+		UserID:           user.ID,
+		SubscriptionName: usage.SubscriptionName,
+		PeriodStart:      usage.PeriodStart,
+		PeriodEnd:        usage.PeriodEnd,
+		RequestCount:     usage.RequestCount,
+		SoftLimit:        usage.SoftLimit,
+		HardLimit:        usage.HardLimit,
+		UsagePercentage:  percentage,
+		Timestamp:        time.Now().UTC(),
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for user [%s]", events.UserUsageWarning, user.ID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch [%s] event for user [%s]", event.Type(), user.ID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+	}
+}
+
+// RequestRetention returns the duration for which the request logs of a user are kept, it is 0 when the request logs are kept forever
+func (service *SubscriptionService) RequestRetention(ctx context.Context, userID entities.UserID) (time.Duration, error) {
+	if !service.limitsEnabled {
+		return 0, nil
+	}
+
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	limits, err := service.Limits(ctx, userID)
+	if err != nil {
+		return 0, service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, fmt.Sprintf("cannot load limits for user [%s]", userID)))
+	}
+
+	return limits.RequestRetention(), nil
+}
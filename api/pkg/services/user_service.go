@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/events"
+	"github.com/NdoleStudio/httpmock/pkg/repositories"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/palantir/stacktrace"
+)
+
+// UserService is responsible for managing the account of an entities.User
+type UserService struct {
+	service
+	logger               telemetry.Logger
+	tracer               telemetry.Tracer
+	userRepository       repositories.UserRepository
+	usageRepository      repositories.UserUsageRepository
+	deadLetterRepository repositories.DeadLetterRepository
+	projectRepository    repositories.ProjectRepository
+	projectService       *ProjectService
+	lemonsqueezyService  *LemonsqueezyService
+	eventDispatcher      *EventDispatcher
+}
+
+// NewUserService creates a new UserService
+func NewUserService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	userRepository repositories.UserRepository,
+	usageRepository repositories.UserUsageRepository,
+	deadLetterRepository repositories.DeadLetterRepository,
+	projectRepository repositories.ProjectRepository,
+	projectService *ProjectService,
+	lemonsqueezyService *LemonsqueezyService,
+	eventDispatcher *EventDispatcher,
+) (s *UserService) {
+	return &UserService{
+		logger:               logger.WithCodeNamespace(fmt.Sprintf("%T", s)),
+		tracer:               tracer,
+		userRepository:       userRepository,
+		usageRepository:      usageRepository,
+		deadLetterRepository: deadLetterRepository,
+		projectRepository:    projectRepository,
+		projectService:       projectService,
+		lemonsqueezyService:  lemonsqueezyService,
+		eventDispatcher:      eventDispatcher,
+	}
+}
+
+// Delete the account of a user. The subscription is cancelled and the entities.User is deleted immediately while
+// the data of the user is purged by the listener of the events.UserAccountDeleted event.
+func (service *UserService) Delete(ctx context.Context, source string, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	user, err := service.userRepository.Load(ctx, userID)
+	if err != nil && stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("cannot load user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if user != nil && user.IsSubscriptionRenewing() {
+		if err = service.lemonsqueezyService.CancelSubscription(ctx, user.SubscriptionID); err != nil {
+			msg := fmt.Sprintf("cannot cancel subscription [%s] of user with ID [%s]", user.SubscriptionID, userID)
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+	}
+
+	if err = service.deleteUser(ctx, userID); err != nil {
+		return service.tracer.WrapErrorSpan(span, err)
+	}
+
+	event, err := service.createEvent(events.UserAccountDeleted, source, &events.UserAccountDeletedPayload{
+		UserID:    userID,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for user [%s]", events.UserAccountDeleted, userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch [%s] event for user [%s]", event.Type(), userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("account of user [%s] deleted", userID))
+	return nil
+}
+
+// Purge deletes all the projects, endpoints, requests, usages and dead letters of a deleted user account and dispatches an
+// events.UserAccountPurged event when done. It can be retried because the data which is already deleted is skipped.
+func (service *UserService) Purge(ctx context.Context, source string, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	projects, err := service.projectRepository.Fetch(ctx, userID)
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch projects of user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	for _, project := range projects {
+		// the project is deleted after the cleanup so that it is fetched again when the cleanup fails and the event is retried
+		if err = service.projectService.Cleanup(ctx, source, userID, project.ID); err != nil {
+			msg := fmt.Sprintf("cannot cleanup project [%s] of user with ID [%s]", project.ID, userID)
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		err = service.projectRepository.Delete(ctx, userID, project.ID)
+		if err != nil && stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+			msg := fmt.Sprintf("cannot delete project [%s] of user with ID [%s]", project.ID, userID)
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+
+		// the subdomain of the project resolves until it is deleted so the requests which were stored during the cleanup are deleted again
+		if err = service.projectService.Cleanup(ctx, source, userID, project.ID); err != nil {
+			msg := fmt.Sprintf("cannot cleanup deleted project [%s] of user with ID [%s]", project.ID, userID)
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+		}
+	}
+
+	if err = service.usageRepository.DeleteByUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("cannot delete usages of user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	if err = service.deadLetterRepository.DeleteByUser(ctx, userID); err != nil {
+		msg := fmt.Sprintf("cannot delete dead letters of user with ID [%s]", userID)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg))
+	}
+
+	// a subscription event which was processed after the account was deleted could have stored the user again
+	if err = service.deleteUser(ctx, userID); err != nil {
+		return service.tracer.WrapErrorSpan(span, err)
+	}
+
+	ctxLogger.Info(fmt.Sprintf("purged [%d] projects of deleted user [%s]", len(projects), userID))
+
+	event, err := service.createEvent(events.UserAccountPurged, source, &events.UserAccountPurgedPayload{
+		UserID:          userID,
+		ProjectsDeleted: uint(len(projects)),
+		Timestamp:       time.Now().UTC(),
+	})
+	if err != nil {
+		msg := fmt.Sprintf("cannot create [%s] event for user [%s]", events.UserAccountPurged, userID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+		return nil
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		msg := fmt.Sprintf("cannot dispatch [%s] event for user [%s]", event.Type(), userID)
+		ctxLogger.Error(stacktrace.Propagate(err, msg))
+	}
+
+	return nil
+}
+
+func (service *UserService) deleteUser(ctx context.Context, userID entities.UserID) error {
+	err := service.userRepository.Delete(ctx, userID)
+	if err != nil && stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("cannot delete user with ID [%s]", userID)
+		return stacktrace.Propagate(err, msg)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package templates
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+var (
+	firstNames = []string{"James", "Mary", "John", "Patricia", "Robert", "Jennifer", "Michael", "Linda", "David", "Elizabeth", "William", "Barbara", "Richard", "Susan", "Joseph", "Jessica", "Thomas", "Sarah", "Charles", "Karen", "Amara", "Chidi", "Fatima", "Kwame", "Yuki", "Mateo", "Sofia", "Lucas", "Aisha", "Noah"}
+	lastNames  = []string{"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Rodriguez", "Martinez", "Hernandez", "Lopez", "Gonzalez", "Wilson", "Anderson", "Thomas", "Taylor", "Moore", "Jackson", "Martin", "Okafor", "Mensah", "Tanaka", "Kowalski", "Nguyen", "Silva", "Rossi", "Dubois", "Schmidt", "Ndiaye"}
+	domains    = []string{"example.com", "example.org", "example.net", "mail.test", "inbox.test"}
+	companies  = []string{"Acme", "Globex", "Initech", "Umbrella", "Hooli", "Stark Industries", "Wayne Enterprises", "Wonka Industries", "Cyberdyne", "Soylent"}
+)
+
+func fakeFirstName() string {
+	return firstNames[rand.Intn(len(firstNames))]
+}
+
+func fakeLastName() string {
+	return lastNames[rand.Intn(len(lastNames))]
+}
+
+func fakeName() string {
+	return fakeFirstName() + " " + fakeLastName()
+}
+
+func fakeEmail() string {
+	return fmt.Sprintf("%s.%s%d@%s", strings.ToLower(fakeFirstName()), strings.ToLower(fakeLastName()), rand.Intn(1000), domains[rand.Intn(len(domains))])
+}
+
+func fakePhone() string {
+	return fmt.Sprintf("+1%03d555%04d", 200+rand.Intn(800), rand.Intn(10000))
+}
+
+func fakeCompany() string {
+	return companies[rand.Intn(len(companies))]
+}
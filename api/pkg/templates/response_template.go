@@ -0,0 +1,287 @@
+package templates
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/cache"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+)
+
+// RequestData is the information about the HTTP request which is available in a response template
+// e.g. {{ .PathParams.id }}, {{ .Query.page }}, {{ index .Headers "Authorization" }} or {{ .Body.email }}
+type RequestData struct {
+	RequestID  string
+	Method     string
+	URL        string
+	Path       string
+	PathParams map[string]string
+	Query      map[string]string
+	Headers    map[string]string
+	Body       interface{}
+	RawBody    string
+	Timestamp  time.Time
+}
+
+// templateCacheSize is the maximum number of parsed templates which are kept in memory
+const templateCacheSize = 1024
+
+const (
+	// missingValueFunction is appended to the pipeline of every action which prints a value
+	missingValueFunction = "httpmockMissingValue"
+
+	// stepFunction is called at the start of every template and of every iteration of a range to limit the execution
+	stepFunction = "httpmockStep"
+
+	// maxRenderSteps is the maximum number of range iterations and template calls when a template is executed
+	maxRenderSteps = 100_000
+
+	// maxRenderSize is the maximum size in bytes of a rendered template
+	maxRenderSize = 1024 * 1024
+
+	// renderTimeout is the maximum duration of the execution of a template
+	renderTimeout = 2 * time.Second
+
+	// maxFormatWidth is the maximum width or precision of a printf verb e.g. %10d
+	maxFormatWidth = 1024
+)
+
+var templateCache = cache.NewLRUCache[string, *template.Template](templateCacheSize)
+
+// Validate checks that the text is a valid response template
+func Validate(text string) error {
+	if _, err := compile(text); err != nil {
+		return stacktrace.Propagate(err, "cannot parse the response template")
+	}
+	return nil
+}
+
+// Render executes the response template using the RequestData
+func Render(text string, data *RequestData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := load(text)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "cannot parse the response template")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), renderTimeout)
+	defer cancel()
+
+	// the clone shares the parsed trees with the cached template, only the functions of this execution are replaced
+	execution, err := tmpl.Clone()
+	if err != nil {
+		return "", stacktrace.Propagate(err, "cannot clone the response template")
+	}
+
+	steps := 0
+	execution.Funcs(template.FuncMap{
+		stepFunction: func() (string, error) {
+			if steps++; steps > maxRenderSteps {
+				return "", fmt.Errorf("the template cannot execute more than %d range iterations and template calls", maxRenderSteps)
+			}
+			if ctx.Err() != nil {
+				return "", fmt.Errorf("the template cannot be executed in more than %s", renderTimeout)
+			}
+			return "", nil
+		},
+	})
+
+	writer := &limitedWriter{ctx: ctx, limit: maxRenderSize}
+	if err = execution.Execute(writer, data); err != nil {
+		return "", stacktrace.Propagate(err, "cannot execute the response template")
+	}
+
+	return writer.buffer.String(), nil
+}
+
+// limitedWriter is a bytes.Buffer which returns an error when the output is larger than the limit or when the context is done
+type limitedWriter struct {
+	ctx    context.Context
+	limit  int
+	buffer bytes.Buffer
+}
+
+func (writer *limitedWriter) Write(content []byte) (int, error) {
+	if writer.ctx.Err() != nil {
+		return 0, fmt.Errorf("the template cannot be executed in more than %s", renderTimeout)
+	}
+	if writer.buffer.Len()+len(content) > writer.limit {
+		return 0, fmt.Errorf("the rendered template cannot be larger than %d bytes", writer.limit)
+	}
+	return writer.buffer.Write(content)
+}
+
+func load(text string) (*template.Template, error) {
+	if tmpl, ok := templateCache.Get(text); ok {
+		return tmpl, nil
+	}
+
+	tmpl, err := compile(text)
+	if err != nil {
+		return nil, err
+	}
+
+	templateCache.Add(text, tmpl)
+	return tmpl, nil
+}
+
+func compile(text string) (*template.Template, error) {
+	tmpl, err := template.New("response").Option("missingkey=zero").Funcs(functions()).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, definition := range tmpl.Templates() {
+		if definition.Tree != nil && definition.Tree.Root != nil {
+			instrument(definition.Tree, definition.Tree.Root)
+			prependStep(definition.Tree, definition.Tree.Root)
+		}
+	}
+	return tmpl, nil
+}
+
+// instrument pipes the value of every action which prints a value to the missingValueFunction so that
+// missing keys in the parsed JSON body are rendered as an empty string instead of "<no value>".
+// It also calls the stepFunction on every iteration of a range so that a range over a large integer is stopped.
+func instrument(tree *parse.Tree, node parse.Node) {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+		for _, child := range node.Nodes {
+			instrument(tree, child)
+		}
+	case *parse.ActionNode:
+		if len(node.Pipe.Decl) == 0 {
+			node.Pipe.Cmds = append(node.Pipe.Cmds, command(tree, node.Pos, missingValueFunction))
+		}
+	case *parse.IfNode:
+		instrument(tree, node.List)
+		instrument(tree, node.ElseList)
+	case *parse.RangeNode:
+		instrument(tree, node.List)
+		instrument(tree, node.ElseList)
+		if node.List != nil {
+			prependStep(tree, node.List)
+		}
+	case *parse.WithNode:
+		instrument(tree, node.List)
+		instrument(tree, node.ElseList)
+	}
+}
+
+// prependStep adds an action which calls the stepFunction at the start of a list of nodes
+func prependStep(tree *parse.Tree, list *parse.ListNode) {
+	action := &parse.ActionNode{
+		NodeType: parse.NodeAction,
+		Pos:      list.Pos,
+		Pipe: &parse.PipeNode{
+			NodeType: parse.NodePipe,
+			Pos:      list.Pos,
+			Cmds:     []*parse.CommandNode{command(tree, list.Pos, stepFunction)},
+		},
+	}
+	list.Nodes = append([]parse.Node{action}, list.Nodes...)
+}
+
+// command creates a parse.CommandNode which calls a function without arguments
+func command(tree *parse.Tree, pos parse.Pos, function string) *parse.CommandNode {
+	return &parse.CommandNode{
+		NodeType: parse.NodeCommand,
+		Pos:      pos,
+		Args:     []parse.Node{parse.NewIdentifier(function).SetTree(tree).SetPos(pos)},
+	}
+}
+
+// sprintf is the printf function of the templates, a large width or precision is refused because it allocates the result before it is written
+func sprintf(format string, args ...interface{}) (string, error) {
+	for index := 0; index < len(format); index++ {
+		if format[index] != '%' {
+			continue
+		}
+
+		width := 0
+		for index++; index < len(format); index++ {
+			character := format[index]
+			if character == '*' {
+				return "", fmt.Errorf("the printf format [%s] cannot contain a * width or precision", format)
+			}
+			if character >= '0' && character <= '9' {
+				width = width*10 + int(character-'0')
+				if width > maxFormatWidth {
+					return "", fmt.Errorf("the width and precision in the printf format [%s] cannot be larger than %d", format, maxFormatWidth)
+				}
+				continue
+			}
+			width = 0
+			if character == '%' || (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z') {
+				break
+			}
+		}
+	}
+
+	return fmt.Sprintf(format, args...), nil
+}
+
+func functions() template.FuncMap {
+	return template.FuncMap{
+		"uuid": func() string {
+			return uuid.NewString()
+		},
+		"randomInt": func(min, max int) int {
+			if max <= min {
+				return min
+			}
+			return min + rand.Intn(max-min+1)
+		},
+		"now": func() time.Time {
+			return time.Now().UTC()
+		},
+		"date": func(layout string, value time.Time) string {
+			return value.Format(layout)
+		},
+		"timestamp": func(value time.Time) int64 {
+			return value.Unix()
+		},
+		"json": func(value interface{}) (string, error) {
+			result, err := json.Marshal(value)
+			if err != nil {
+				return "", fmt.Errorf("cannot encode [%v] as JSON: %w", value, err)
+			}
+			return string(result), nil
+		},
+		"default": func(fallback interface{}, value interface{}) interface{} {
+			if value == nil || value == "" {
+				return fallback
+			}
+			return value
+		},
+		missingValueFunction: func(value interface{}) interface{} {
+			if value == nil {
+				return ""
+			}
+			return value
+		},
+		"printf":        sprintf,
+		"upper":         strings.ToUpper,
+		"lower":         strings.ToLower,
+		"fakeFirstName": fakeFirstName,
+		"fakeLastName":  fakeLastName,
+		"fakeName":      fakeName,
+		"fakeEmail":     fakeEmail,
+		"fakePhone":     fakePhone,
+		"fakeCompany":   fakeCompany,
+	}
+}
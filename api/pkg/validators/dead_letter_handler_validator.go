@@ -0,0 +1,48 @@
+package validators
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/requests"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// DeadLetterHandlerValidator validates models used in handlers.DeadLetterHandler
+type DeadLetterHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewDeadLetterHandlerValidator creates a new handlers.DeadLetterHandler validator
+func NewDeadLetterHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *DeadLetterHandlerValidator) {
+	return &DeadLetterHandlerValidator{
+		logger: logger.WithCodeNamespace(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateIndex validates the requests.DeadLetterIndexRequest
+func (validator *DeadLetterHandlerValidator) ValidateIndex(request *requests.DeadLetterIndexRequest) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: request,
+		Rules: govalidator.MapData{
+			"status": []string{
+				"in:" + string(entities.DeadLetterStatusFailed) + "," + string(entities.DeadLetterStatusReplayed),
+			},
+			"limit": []string{
+				"required",
+				"min:1",
+				"max:100",
+			},
+		},
+	})
+
+	return v.ValidateStruct()
+}
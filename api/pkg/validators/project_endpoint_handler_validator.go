@@ -2,10 +2,14 @@ package validators
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/exporters"
+	"github.com/NdoleStudio/httpmock/pkg/matchers"
 	"github.com/google/uuid"
 
 	"github.com/NdoleStudio/httpmock/pkg/repositories"
@@ -14,9 +18,22 @@ import (
 	"github.com/NdoleStudio/httpmock/pkg/requests"
 
 	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/NdoleStudio/httpmock/pkg/templates"
 	"github.com/thedevsaddam/govalidator"
 )
 
+const (
+	maxRequestConditions = 10
+	maxResponses         = 10
+	maxFaults            = 5
+	maxFaultDuration     = 30_000
+	maxCallbacks         = 5
+	maxCallbackRetries   = 5
+	maxCallbackDelay     = 30_000
+	maxImportDocument    = 2 * 1024 * 1024
+	maxImportEndpoints   = 500
+)
+
 // ProjectEndpointHandlerValidator validates models used in handlers.ProjectEndpointHandler
 type ProjectEndpointHandlerValidator struct {
 	validator
@@ -64,6 +81,20 @@ func (validator *ProjectEndpointHandlerValidator) ValidateUpdate(ctx context.Con
 				"min:1",
 				"max:255",
 			},
+			"scenario_name": []string{
+				"max:100",
+				scenarioName,
+			},
+			"scenario_required_state": []string{
+				"max:100",
+			},
+			"scenario_new_state": []string{
+				"max:100",
+			},
+			"priority": []string{
+				"min:0",
+				"max:1000",
+			},
 			"response_code": []string{
 				"required",
 				"min:100",
@@ -87,11 +118,28 @@ func (validator *ProjectEndpointHandlerValidator) ValidateUpdate(ctx context.Con
 	})
 
 	result := v.ValidateStruct()
+	validator.validateRequestConditions(result, request.RequestConditions)
+	validator.validateScenario(result, request.ScenarioName, request.ScenarioRequiredState, request.ScenarioNewState)
+	validator.validateResponses(result, request.Responses, request.ResponseMode, request.ResponseTemplateEnabled)
+	validator.validateFaults(result, request.Faults)
+	validator.validateCallbacks(result, request.Callbacks)
+	if request.ResponseTemplateEnabled {
+		validator.validateResponseTemplate(result, "response_body", request.ResponseBody, "response_headers", request.ResponseHeaders)
+	}
 	if len(result) != 0 {
 		return result
 	}
 
-	endpoint, err := validator.repository.LoadByRequestForUser(ctx, userID, uuid.MustParse(request.ProjectID), request.RequestMethod, request.RequestPath)
+	endpoint, err := validator.repository.LoadConflicting(ctx, &entities.ProjectEndpoint{
+		ID:                    uuid.MustParse(request.ProjectEndpointID),
+		ProjectID:             uuid.MustParse(request.ProjectID),
+		UserID:                userID,
+		RequestMethod:         request.RequestMethod,
+		RequestPath:           request.RequestPath,
+		RequestConditions:     request.RequestConditions,
+		ScenarioName:          request.ScenarioName,
+		ScenarioRequiredState: request.ScenarioRequiredState,
+	})
 	if err != nil && stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
 		msg := fmt.Sprintf("cannot check if the [%s %s] request path has already been taken.", request.RequestMethod, request.RequestPath)
 		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
@@ -100,8 +148,8 @@ func (validator *ProjectEndpointHandlerValidator) ValidateUpdate(ctx context.Con
 		return result
 	}
 
-	if err == nil && endpoint.ID.String() != request.ProjectEndpointID {
-		result.Add("request_path", fmt.Sprintf("The request path [%s %s] already exists on this project.", endpoint.RequestMethod, request.RequestPath))
+	if err == nil {
+		result.Add("request_path", fmt.Sprintf("The request path [%s %s] conflicts with the [%s %s] endpoint on this project.", request.RequestMethod, request.RequestPath, endpoint.RequestMethod, endpoint.RequestPath))
 		return result
 	}
 
@@ -113,6 +161,124 @@ func (validator *ProjectEndpointHandlerValidator) ValidateStore(ctx context.Cont
 	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
 	defer span.End()
 
+	result := validator.validateStoreRules(request)
+	if len(result) != 0 {
+		return result
+	}
+
+	endpoint, err := validator.repository.LoadConflicting(ctx, &entities.ProjectEndpoint{
+		ProjectID:             uuid.MustParse(request.ProjectID),
+		UserID:                userID,
+		RequestMethod:         request.RequestMethod,
+		RequestPath:           request.RequestPath,
+		RequestConditions:     request.RequestConditions,
+		ScenarioName:          request.ScenarioName,
+		ScenarioRequiredState: request.ScenarioRequiredState,
+	})
+	if err != nil && stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+		msg := fmt.Sprintf("cannot check if the [%s %s] request path has already been taken.", request.RequestMethod, request.RequestPath)
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+
+		result.Add("request_path", fmt.Sprintf("We could not check if the [%s %s] request path has already been taken.", request.RequestMethod, request.RequestPath))
+		return result
+	}
+
+	if err == nil {
+		result.Add("request_path", fmt.Sprintf("The request path [%s %s] conflicts with the [%s %s] endpoint on this project.", request.RequestMethod, request.RequestPath, endpoint.RequestMethod, endpoint.RequestPath))
+		return result
+	}
+
+	return result
+}
+
+// ValidateBundleExport validates the requests.ProjectBundleExportRequest
+func (validator *ProjectEndpointHandlerValidator) ValidateBundleExport(_ context.Context, request *requests.ProjectBundleExportRequest) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: request,
+		Rules: govalidator.MapData{
+			"projectId": []string{
+				"required",
+				"uuid",
+			},
+			"format": []string{
+				"required",
+				"in:json,yaml",
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateBundleApply validates the requests.ProjectBundleApplyRequest and every endpoint in the bundle
+func (validator *ProjectEndpointHandlerValidator) ValidateBundleApply(ctx context.Context, request *requests.ProjectBundleApplyRequest) url.Values {
+	_, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	v := govalidator.New(govalidator.Options{
+		Data: request,
+		Rules: govalidator.MapData{
+			"projectId": []string{
+				"required",
+				"uuid",
+			},
+			"document": []string{
+				"required",
+			},
+		},
+	})
+
+	result := v.ValidateStruct()
+	if len(result) != 0 {
+		return result
+	}
+
+	if len(request.Document) > maxImportDocument {
+		result.Add("document", fmt.Sprintf("The document field cannot be larger than %d bytes", maxImportDocument))
+		return result
+	}
+
+	endpointRequests, err := request.ToProjectEndpointStoreRequests()
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot parse bundle for project [%s]", request.ProjectID)))
+		result.Add("document", fmt.Sprintf("The document field must be a valid version %d project bundle in JSON or YAML format", exporters.ProjectBundleVersion))
+		return result
+	}
+
+	if len(endpointRequests) > maxImportEndpoints {
+		result.Add("document", fmt.Sprintf("The document field cannot contain more than %d endpoints", maxImportEndpoints))
+		return result
+	}
+
+	var endpoints []*entities.ProjectEndpoint
+	for index, endpointRequest := range endpointRequests {
+		for field, errors := range validator.validateStoreRules(endpointRequest) {
+			for _, message := range errors {
+				result.Add(fmt.Sprintf("endpoints[%d].%s", index, field), message)
+			}
+		}
+
+		endpoint := &entities.ProjectEndpoint{
+			RequestMethod:         endpointRequest.RequestMethod,
+			RequestPath:           endpointRequest.RequestPath,
+			RequestConditions:     endpointRequest.RequestConditions,
+			ScenarioName:          endpointRequest.ScenarioName,
+			ScenarioRequiredState: endpointRequest.ScenarioRequiredState,
+		}
+
+		for otherIndex, other := range endpoints {
+			if matchers.Conflicts(endpoint, other) {
+				result.Add(fmt.Sprintf("endpoints[%d].request_path", index), fmt.Sprintf("The request path [%s %s] conflicts with the endpoint at position [%d] in this bundle.", endpoint.RequestMethod, endpoint.RequestPath, otherIndex))
+				break
+			}
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return result
+}
+
+// validateStoreRules validates the requests.ProjectEndpointStoreRequest without checking for conflicting endpoints
+func (validator *ProjectEndpointHandlerValidator) validateStoreRules(request *requests.ProjectEndpointStoreRequest) url.Values {
 	v := govalidator.New(govalidator.Options{
 		Data: request,
 		Rules: govalidator.MapData{
@@ -130,6 +296,20 @@ func (validator *ProjectEndpointHandlerValidator) ValidateStore(ctx context.Cont
 				"min:1",
 				"max:255",
 			},
+			"scenario_name": []string{
+				"max:100",
+				scenarioName,
+			},
+			"scenario_required_state": []string{
+				"max:100",
+			},
+			"scenario_new_state": []string{
+				"max:100",
+			},
+			"priority": []string{
+				"min:0",
+				"max:1000",
+			},
 			"response_code": []string{
 				"required",
 				"min:100",
@@ -152,24 +332,300 @@ func (validator *ProjectEndpointHandlerValidator) ValidateStore(ctx context.Cont
 		},
 	})
 
+	result := v.ValidateStruct()
+	validator.validateRequestConditions(result, request.RequestConditions)
+	validator.validateScenario(result, request.ScenarioName, request.ScenarioRequiredState, request.ScenarioNewState)
+	validator.validateResponses(result, request.Responses, request.ResponseMode, request.ResponseTemplateEnabled)
+	validator.validateFaults(result, request.Faults)
+	validator.validateCallbacks(result, request.Callbacks)
+	if request.ResponseTemplateEnabled {
+		validator.validateResponseTemplate(result, "response_body", request.ResponseBody, "response_headers", request.ResponseHeaders)
+	}
+	return result
+}
+
+// ValidateImport validates the requests.ProjectEndpointImportRequest
+func (validator *ProjectEndpointHandlerValidator) ValidateImport(ctx context.Context, request *requests.ProjectEndpointImportRequest) url.Values {
+	_, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	v := govalidator.New(govalidator.Options{
+		Data: request,
+		Rules: govalidator.MapData{
+			"projectId": []string{
+				"required",
+				"uuid",
+			},
+			"document": []string{
+				"required",
+			},
+		},
+	})
+
 	result := v.ValidateStruct()
 	if len(result) != 0 {
 		return result
 	}
 
-	endpoint, err := validator.repository.LoadByRequestForUser(ctx, userID, uuid.MustParse(request.ProjectID), request.RequestMethod, request.RequestPath)
-	if err != nil && stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
-		msg := fmt.Sprintf("cannot check if the [%s %s] request path has already been taken.", request.RequestMethod, request.RequestPath)
-		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
-
-		result.Add("request_path", fmt.Sprintf("We could not check if the [%s %s] request path has already been taken.", request.RequestMethod, request.RequestPath))
+	if len(request.Document) > maxImportDocument {
+		result.Add("document", fmt.Sprintf("The document field cannot be larger than %d bytes", maxImportDocument))
 		return result
 	}
 
-	if err == nil {
-		result.Add("request_path", fmt.Sprintf("The request path [%s %s] already exists on this project.", endpoint.RequestMethod, request.RequestPath))
+	endpoints, err := request.ToProjectEndpointStoreRequests()
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagate(err, fmt.Sprintf("cannot parse document for project [%s]", request.ProjectID)))
+		result.Add("document", "The document field must be a valid OpenAPI 3.x or Swagger 2.0 document in JSON or YAML format")
 		return result
 	}
 
+	if len(endpoints) == 0 {
+		result.Add("document", "The document field must contain at least 1 operation")
+	}
+
+	if len(endpoints) > maxImportEndpoints {
+		result.Add("document", fmt.Sprintf("The document field cannot contain more than %d operations", maxImportEndpoints))
+	}
+
 	return result
 }
+
+// ValidateImportEndpoints validates the requests.ProjectEndpointStoreRequest generated by an import.
+// The validation errors are returned in the same order as the requests and an empty url.Values means the request is valid.
+func (validator *ProjectEndpointHandlerValidator) ValidateImportEndpoints(ctx context.Context, userID entities.UserID, endpointRequests []*requests.ProjectEndpointStoreRequest) []url.Values {
+	ctx, span := validator.tracer.Start(ctx)
+	defer span.End()
+
+	results := make([]url.Values, len(endpointRequests))
+	var accepted []*entities.ProjectEndpoint
+	for index, request := range endpointRequests {
+		results[index] = validator.ValidateStore(ctx, userID, request)
+		if len(results[index]) != 0 {
+			continue
+		}
+
+		endpoint := &entities.ProjectEndpoint{
+			RequestMethod:         request.RequestMethod,
+			RequestPath:           request.RequestPath,
+			RequestConditions:     request.RequestConditions,
+			ScenarioName:          request.ScenarioName,
+			ScenarioRequiredState: request.ScenarioRequiredState,
+		}
+
+		for _, other := range accepted {
+			if matchers.Conflicts(endpoint, other) {
+				results[index].Add("request_path", fmt.Sprintf("The request path [%s %s] conflicts with the [%s %s] operation in this document.", endpoint.RequestMethod, endpoint.RequestPath, other.RequestMethod, other.RequestPath))
+				break
+			}
+		}
+
+		if len(results[index]) == 0 {
+			accepted = append(accepted, endpoint)
+		}
+	}
+
+	return results
+}
+
+func (validator *ProjectEndpointHandlerValidator) validateRequestConditions(result url.Values, conditions []*entities.ProjectEndpointCondition) {
+	if len(conditions) > maxRequestConditions {
+		result.Add("request_conditions", fmt.Sprintf("The request_conditions field cannot contain more than %d conditions", maxRequestConditions))
+		return
+	}
+
+	for index, condition := range conditions {
+		if condition == nil {
+			result.Add("request_conditions", fmt.Sprintf("The request condition at position [%d] cannot be null", index))
+			continue
+		}
+		if err := matchers.ValidateCondition(condition); err != nil {
+			result.Add("request_conditions", fmt.Sprintf("The request condition at position [%d] is invalid because %s", index, err.Error()))
+		}
+	}
+}
+
+func (validator *ProjectEndpointHandlerValidator) validateResponseTemplate(result url.Values, bodyField string, responseBody string, headersField string, responseHeaders string) {
+	if err := templates.Validate(responseBody); err != nil {
+		result.Add(bodyField, fmt.Sprintf("The %s field is not a valid template because %s", bodyField, stacktrace.RootCause(err).Error()))
+	}
+
+	var headers []map[string]string
+	if responseHeaders == "" || json.Unmarshal([]byte(responseHeaders), &headers) != nil {
+		return
+	}
+
+	for _, header := range headers {
+		for key, value := range header {
+			if err := templates.Validate(value); err != nil {
+				result.Add(headersField, fmt.Sprintf("The value of the [%s] header is not a valid template because %s", key, stacktrace.RootCause(err).Error()))
+			}
+		}
+	}
+}
+
+func (validator *ProjectEndpointHandlerValidator) validateScenario(result url.Values, name string, requiredState string, newState string) {
+	if name == "" && (requiredState != "" || newState != "") {
+		result.Add("scenario_name", "The scenario_name field is required when the scenario_required_state or scenario_new_state field is set")
+	}
+}
+
+func (validator *ProjectEndpointHandlerValidator) validateResponses(result url.Values, responses []*entities.ProjectEndpointResponse, mode string, templateEnabled bool) {
+	if len(responses) == 0 {
+		return
+	}
+
+	if len(responses) > maxResponses {
+		result.Add("responses", fmt.Sprintf("The responses field cannot contain more than %d responses", maxResponses))
+		return
+	}
+
+	switch entities.ProjectEndpointResponseMode(mode) {
+	case entities.ProjectEndpointResponseModeRoundRobin, entities.ProjectEndpointResponseModeSequential, entities.ProjectEndpointResponseModeWeightedRandom:
+		break
+	default:
+		result.Add("response_mode", "The response_mode field must be one of [round_robin, sequential, weighted_random]")
+	}
+
+	totalWeight := uint(0)
+	for index, response := range responses {
+		if response == nil {
+			result.Add("responses", fmt.Sprintf("The response at position [%d] cannot be null", index))
+			continue
+		}
+
+		if response.ResponseCode < 100 || response.ResponseCode > 600 {
+			result.Add("responses", fmt.Sprintf("The response_code of the response at position [%d] must be between 100 and 600", index))
+		}
+
+		body := ""
+		if response.ResponseBody != nil {
+			body = *response.ResponseBody
+		}
+		if len(body) > 1000 {
+			result.Add("responses", fmt.Sprintf("The response_body of the response at position [%d] cannot be longer than 1000 characters", index))
+		}
+
+		headers := ""
+		if response.ResponseHeaders != nil {
+			headers = *response.ResponseHeaders
+		}
+		if headers != "" && (len(headers) > 500 || json.Unmarshal([]byte(headers), &[]map[string]string{}) != nil) {
+			result.Add("responses", fmt.Sprintf("The response_headers of the response at position [%d] must be a JSON array with schema [{\"key\": \"value\"}] of at most 500 characters", index))
+		}
+
+		if response.Weight > 1000 {
+			result.Add("responses", fmt.Sprintf("The weight of the response at position [%d] cannot be greater than 1000", index))
+		}
+		totalWeight += response.Weight
+
+		if templateEnabled {
+			validator.validateResponseTemplate(result, "responses", body, "responses", headers)
+		}
+	}
+
+	if entities.ProjectEndpointResponseMode(mode) == entities.ProjectEndpointResponseModeWeightedRandom && totalWeight == 0 {
+		result.Add("responses", "At least one response must have a weight greater than 0 when the response_mode is [weighted_random]")
+	}
+}
+
+func (validator *ProjectEndpointHandlerValidator) validateFaults(result url.Values, faults []*entities.ProjectEndpointFault) {
+	if len(faults) > maxFaults {
+		result.Add("faults", fmt.Sprintf("The faults field cannot contain more than %d faults", maxFaults))
+		return
+	}
+
+	for index, fault := range faults {
+		if fault == nil {
+			result.Add("faults", fmt.Sprintf("The fault at position [%d] cannot be null", index))
+			continue
+		}
+
+		if fault.Probability <= 0 || fault.Probability > 1 {
+			result.Add("faults", fmt.Sprintf("The probability of the fault at position [%d] must be greater than 0 and less than or equal to 1", index))
+		}
+
+		switch fault.Type {
+		case entities.ProjectEndpointFaultTypeLatency:
+			switch fault.LatencyDistribution {
+			case entities.ProjectEndpointLatencyDistributionUniform, entities.ProjectEndpointLatencyDistributionNormal, entities.ProjectEndpointLatencyDistributionExponential:
+				break
+			default:
+				result.Add("faults", fmt.Sprintf("The latency_distribution of the fault at position [%d] must be one of [uniform, normal, exponential]", index))
+			}
+			if fault.LatencyMaxInMilliseconds == 0 || fault.LatencyMinInMilliseconds > fault.LatencyMaxInMilliseconds || fault.LatencyMaxInMilliseconds > maxFaultDuration {
+				result.Add("faults", fmt.Sprintf("The latency of the fault at position [%d] must have a min less than or equal to the max and a max between 1 and %d milliseconds", index, maxFaultDuration))
+			}
+		case entities.ProjectEndpointFaultTypeDrip:
+			if fault.DripDurationInMilliseconds == 0 || fault.DripDurationInMilliseconds > maxFaultDuration {
+				result.Add("faults", fmt.Sprintf("The drip_duration_in_milliseconds of the fault at position [%d] must be between 1 and %d", index, maxFaultDuration))
+			}
+		case entities.ProjectEndpointFaultTypeConnectionReset,
+			entities.ProjectEndpointFaultTypeConnectionClose,
+			entities.ProjectEndpointFaultTypeEmptyResponse,
+			entities.ProjectEndpointFaultTypeMalformedChunk:
+			break
+		default:
+			result.Add("faults", fmt.Sprintf("The type of the fault at position [%d] must be one of [latency, connection_reset, connection_close, empty_response, malformed_chunk, drip]", index))
+		}
+	}
+}
+
+func (validator *ProjectEndpointHandlerValidator) validateCallbacks(result url.Values, callbacks []*entities.ProjectEndpointCallback) {
+	if len(callbacks) > maxCallbacks {
+		result.Add("callbacks", fmt.Sprintf("The callbacks field cannot contain more than %d callbacks", maxCallbacks))
+		return
+	}
+
+	for index, callback := range callbacks {
+		if callback == nil {
+			result.Add("callbacks", fmt.Sprintf("The callback at position [%d] cannot be null", index))
+			continue
+		}
+
+		if strings.Contains(callback.URL, "{{") {
+			if err := templates.Validate(callback.URL); err != nil {
+				result.Add("callbacks", fmt.Sprintf("The url of the callback at position [%d] is not a valid template because %s", index, stacktrace.RootCause(err).Error()))
+			} else if !strings.HasPrefix(callback.URL, "http://") && !strings.HasPrefix(callback.URL, "https://") {
+				result.Add("callbacks", fmt.Sprintf("The url of the callback at position [%d] must start with http:// or https://", index))
+			}
+		} else if target, err := url.Parse(callback.URL); err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			result.Add("callbacks", fmt.Sprintf("The url of the callback at position [%d] must be a valid http or https URL", index))
+		}
+		if len(callback.URL) > 2048 {
+			result.Add("callbacks", fmt.Sprintf("The url of the callback at position [%d] cannot be longer than 2048 characters", index))
+		}
+
+		switch callback.Method {
+		case "GET", "POST", "PUT", "PATCH", "DELETE":
+			break
+		default:
+			result.Add("callbacks", fmt.Sprintf("The method of the callback at position [%d] must be one of [GET, POST, PUT, PATCH, DELETE]", index))
+		}
+
+		body := ""
+		if callback.Body != nil {
+			body = *callback.Body
+		}
+		if len(body) > 5000 {
+			result.Add("callbacks", fmt.Sprintf("The body of the callback at position [%d] cannot be longer than 5000 characters", index))
+		}
+
+		headers := ""
+		if callback.Headers != nil {
+			headers = *callback.Headers
+		}
+		if headers != "" && (len(headers) > 500 || json.Unmarshal([]byte(headers), &[]map[string]string{}) != nil) {
+			result.Add("callbacks", fmt.Sprintf("The headers of the callback at position [%d] must be a JSON array with schema [{\"key\": \"value\"}] of at most 500 characters", index))
+		}
+
+		if callback.DelayInMilliseconds > maxCallbackDelay {
+			result.Add("callbacks", fmt.Sprintf("The delay_in_milliseconds of the callback at position [%d] cannot be greater than %d", index, maxCallbackDelay))
+		}
+
+		if callback.Retries > maxCallbackRetries {
+			result.Add("callbacks", fmt.Sprintf("The retries of the callback at position [%d] cannot be greater than %d", index, maxCallbackRetries))
+		}
+
+		validator.validateResponseTemplate(result, "callbacks", body, "callbacks", headers)
+	}
+}
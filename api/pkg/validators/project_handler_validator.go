@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"net/url"
 
+	"github.com/NdoleStudio/httpmock/pkg/entities"
 	"github.com/NdoleStudio/httpmock/pkg/repositories"
+	"github.com/google/uuid"
 	"github.com/palantir/stacktrace"
 
 	"github.com/NdoleStudio/httpmock/pkg/requests"
@@ -61,6 +63,10 @@ func (validator *ProjectHandlerValidator) ValidateUpdate(ctx context.Context, re
 			"description": []string{
 				"max:500",
 			},
+			"upstream_url": []string{
+				upstreamURL,
+				"max:255",
+			},
 		},
 	})
 
@@ -86,6 +92,51 @@ func (validator *ProjectHandlerValidator) ValidateUpdate(ctx context.Context, re
 	return result
 }
 
+// ValidateRecord validates that an entities.Project can record the responses from its upstream URL
+func (validator *ProjectHandlerValidator) ValidateRecord(ctx context.Context, userID entities.UserID, projectID uuid.UUID) url.Values {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	result := url.Values{}
+
+	project, err := validator.repository.Load(ctx, userID, projectID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return result
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("cannot load project [%s] for user [%s]", projectID, userID)
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+
+		result.Add("upstream_url", "We could not check if the project has an upstream URL.")
+		return result
+	}
+
+	if project.UpstreamURL == "" {
+		result.Add("upstream_url", "The project must have an upstream URL before you can start recording.")
+	}
+
+	return result
+}
+
+// ValidateExport validates the requests.ProjectExportRequest
+func (validator *ProjectHandlerValidator) ValidateExport(_ context.Context, request *requests.ProjectExportRequest) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: request,
+		Rules: govalidator.MapData{
+			"projectId": []string{
+				"required",
+				"uuid",
+			},
+			"format": []string{
+				"required",
+				"in:openapi,postman,har",
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
+
 // ValidateCreate validates the requests.ProjectCreateRequest
 func (validator *ProjectHandlerValidator) ValidateCreate(ctx context.Context, request *requests.ProjectCreateRequest) url.Values {
 	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
@@ -108,6 +159,10 @@ func (validator *ProjectHandlerValidator) ValidateCreate(ctx context.Context, re
 			"description": []string{
 				"max:500",
 			},
+			"upstream_url": []string{
+				upstreamURL,
+				"max:255",
+			},
 		},
 	})
 
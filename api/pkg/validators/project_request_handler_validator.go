@@ -0,0 +1,143 @@
+package validators
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/NdoleStudio/httpmock/pkg/matchers"
+	"github.com/NdoleStudio/httpmock/pkg/requests"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/oklog/ulid/v2"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// ProjectRequestHandlerValidator validates models used in handlers.ProjectRequestHandler
+type ProjectRequestHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewProjectRequestHandlerValidator creates a new handlers.ProjectRequestHandler validator
+func NewProjectRequestHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *ProjectRequestHandlerValidator) {
+	return &ProjectRequestHandlerValidator{
+		logger: logger.WithCodeNamespace(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateIndex validates the requests.ProjectRequestIndexRequest
+func (validator *ProjectRequestHandlerValidator) ValidateIndex(request *requests.ProjectRequestIndexRequest) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: request,
+		Rules: govalidator.MapData{
+			"projectId": []string{
+				"required",
+				"uuid",
+			},
+			"unmatched": []string{
+				"in:true,false",
+			},
+			"limit": []string{
+				"required",
+				"min:1",
+				"max:100",
+			},
+		},
+	})
+
+	validationErrors := v.ValidateStruct()
+	validator.validateCursor(validationErrors, request.Prev, request.Next)
+	return validationErrors
+}
+
+// ValidateSearch validates the requests.ProjectRequestSearchRequest
+func (validator *ProjectRequestHandlerValidator) ValidateSearch(request *requests.ProjectRequestSearchRequest) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: request,
+		Rules: govalidator.MapData{
+			"projectId": []string{
+				"required",
+				"uuid",
+			},
+			"method": []string{
+				"alpha",
+				"max:20",
+			},
+			"response_code": []string{
+				"min:100",
+				"max:599",
+			},
+			"path_prefix": []string{
+				"max:255",
+				"regex:^/",
+			},
+			"ip_address": []string{
+				"ip",
+			},
+			"header_key": []string{
+				"max:255",
+			},
+			"query": []string{
+				"max:255",
+			},
+			"json_path": []string{
+				"max:255",
+			},
+			"unmatched": []string{
+				"in:true,false",
+			},
+			"limit": []string{
+				"required",
+				"min:1",
+				"max:100",
+			},
+		},
+	})
+
+	validationErrors := v.ValidateStruct()
+	validator.validateCursor(validationErrors, request.Prev, request.Next)
+
+	from, fromErr := time.Parse(time.RFC3339, request.From)
+	if request.From != "" && fromErr != nil {
+		validationErrors.Add("from", fmt.Sprintf("The from query param [%s] must be an RFC3339 timestamp like 2024-01-02T15:04:05Z", request.From))
+	}
+
+	to, toErr := time.Parse(time.RFC3339, request.To)
+	if request.To != "" && toErr != nil {
+		validationErrors.Add("to", fmt.Sprintf("The to query param [%s] must be an RFC3339 timestamp like 2024-01-02T15:04:05Z", request.To))
+	}
+
+	if request.From != "" && request.To != "" && fromErr == nil && toErr == nil && !from.Before(to) {
+		validationErrors.Add("to", fmt.Sprintf("The to query param [%s] must be after the from query param [%s]", request.To, request.From))
+	}
+
+	if request.HeaderValue != "" && request.HeaderKey == "" {
+		validationErrors.Add("header_value", "The header_value query param can only be used with the header_key query param")
+	}
+
+	if _, err := matchers.ParseJSONPath(request.JSONPath); request.JSONPath != "" && err != nil {
+		validationErrors.Add("json_path", fmt.Sprintf("The json_path query param [%s] must be a JSONPath expression like $.data.items[0].name", request.JSONPath))
+	}
+
+	if request.JSONValue != "" && request.JSONPath == "" {
+		validationErrors.Add("json_value", "The json_value query param can only be used with the json_path query param")
+	}
+
+	return validationErrors
+}
+
+// validateCursor adds an error when the prev or next pagination cursor is not a valid ULID
+func (validator *ProjectRequestHandlerValidator) validateCursor(validationErrors url.Values, prev string, next string) {
+	if _, err := ulid.Parse(prev); prev != "" && err != nil {
+		validationErrors.Add("prev", fmt.Sprintf("The prev query param [%s] must be a valid ULID https://github.com/ulid/spec", prev))
+	}
+
+	if _, err := ulid.Parse(next); next != "" && err != nil {
+		validationErrors.Add("next", fmt.Sprintf("The next query param [%s] must be a valid ULID https://github.com/ulid/spec", next))
+	}
+}
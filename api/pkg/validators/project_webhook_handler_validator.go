@@ -0,0 +1,142 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/NdoleStudio/httpmock/pkg/entities"
+	"github.com/NdoleStudio/httpmock/pkg/repositories"
+	"github.com/NdoleStudio/httpmock/pkg/requests"
+	"github.com/NdoleStudio/httpmock/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/palantir/stacktrace"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// maxProjectWebhooks is the maximum number of entities.ProjectWebhook in a project
+const maxProjectWebhooks = 5
+
+// ProjectWebhookHandlerValidator validates models used in handlers.ProjectWebhookHandler
+type ProjectWebhookHandlerValidator struct {
+	validator
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.ProjectWebhookRepository
+}
+
+// NewProjectWebhookHandlerValidator creates a new handlers.ProjectWebhookHandler validator
+func NewProjectWebhookHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.ProjectWebhookRepository,
+) (v *ProjectWebhookHandlerValidator) {
+	return &ProjectWebhookHandlerValidator{
+		logger:     logger.WithCodeNamespace(fmt.Sprintf("%T", v)),
+		tracer:     tracer,
+		repository: repository,
+	}
+}
+
+// ValidateStore validates the requests.ProjectWebhookStoreRequest
+func (validator *ProjectWebhookHandlerValidator) ValidateStore(ctx context.Context, userID entities.UserID, request *requests.ProjectWebhookStoreRequest) url.Values {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	v := govalidator.New(govalidator.Options{
+		Data: request,
+		Rules: govalidator.MapData{
+			"projectId": []string{
+				"required",
+				"uuid",
+			},
+			"url": []string{
+				"required",
+				webhookURL,
+				"max:255",
+			},
+			"mode": []string{
+				"required",
+				fmt.Sprintf("in:%s,%s", entities.ProjectWebhookModeBinary, entities.ProjectWebhookModeStructured),
+			},
+			"signing_secret": []string{
+				"max:100",
+			},
+		},
+	})
+
+	result := v.ValidateStruct()
+	if len(result) != 0 {
+		return result
+	}
+
+	webhooks, err := validator.repository.Index(ctx, userID, uuid.MustParse(request.ProjectID))
+	if err != nil {
+		msg := fmt.Sprintf("cannot fetch webhooks of project [%s] for user [%s]", request.ProjectID, userID)
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagate(err, msg)))
+
+		result.Add("url", "We could not check the number of webhooks in the project.")
+		return result
+	}
+
+	if len(webhooks) >= maxProjectWebhooks {
+		result.Add("url", fmt.Sprintf("A project cannot have more than %d webhooks.", maxProjectWebhooks))
+	}
+
+	return result
+}
+
+// ValidateUpdate validates the requests.ProjectWebhookUpdateRequest
+func (validator *ProjectWebhookHandlerValidator) ValidateUpdate(request *requests.ProjectWebhookUpdateRequest) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: request,
+		Rules: govalidator.MapData{
+			"projectId": []string{
+				"required",
+				"uuid",
+			},
+			"projectWebhookId": []string{
+				"required",
+				"uuid",
+			},
+			"url": []string{
+				"required",
+				webhookURL,
+				"max:255",
+			},
+			"mode": []string{
+				"required",
+				fmt.Sprintf("in:%s,%s", entities.ProjectWebhookModeBinary, entities.ProjectWebhookModeStructured),
+			},
+			"signing_secret": []string{
+				"max:100",
+			},
+		},
+	})
+
+	return v.ValidateStruct()
+}
+
+// ValidateDeliveries validates the requests.ProjectWebhookDeliveryIndexRequest
+func (validator *ProjectWebhookHandlerValidator) ValidateDeliveries(request *requests.ProjectWebhookDeliveryIndexRequest) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: request,
+		Rules: govalidator.MapData{
+			"projectId": []string{
+				"required",
+				"uuid",
+			},
+			"projectWebhookId": []string{
+				"required",
+				"uuid",
+			},
+			"limit": []string{
+				"required",
+				"min:1",
+				"max:100",
+			},
+		},
+	})
+
+	return v.ValidateStruct()
+}
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"regexp"
+	"strings"
 
+	"github.com/NdoleStudio/httpmock/pkg/matchers"
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/thedevsaddam/govalidator"
@@ -15,8 +18,13 @@ type validator struct{}
 const (
 	requestHeaders = "requestHeaders"
 	requestPath    = "requestPath"
+	scenarioName   = "scenarioName"
+	upstreamURL    = "upstreamURL"
+	webhookURL     = "webhookURL"
 )
 
+var scenarioNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_\-]*$`)
+
 func init() {
 	govalidator.AddCustomRule(requestHeaders, func(field string, rule string, message string, value interface{}) error {
 		input, ok := value.(string)
@@ -41,6 +49,63 @@ func init() {
 			return fmt.Errorf("the %s field must be a valid URI like /post/1", field)
 		}
 
+		if _, err := matchers.ParsePath(input); err != nil {
+			return fmt.Errorf("the %s field must be a valid path template like /posts/{id} or /files/*", field)
+		}
+
+		return nil
+	})
+
+	govalidator.AddCustomRule(upstreamURL, func(field string, rule string, message string, value interface{}) error {
+		input, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("the %s field must be a string", field)
+		}
+
+		if input == "" {
+			return nil
+		}
+
+		u, err := url.Parse(input)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+			return fmt.Errorf("the %s field must be a valid base URL like https://api.example.com", field)
+		}
+
+		if strings.HasSuffix(u.Hostname(), "httpmock.dev") {
+			return fmt.Errorf("the %s field cannot point to an httpmock.dev URL", field)
+		}
+
+		return nil
+	})
+
+	govalidator.AddCustomRule(webhookURL, func(field string, rule string, message string, value interface{}) error {
+		input, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("the %s field must be a string", field)
+		}
+
+		u, err := url.Parse(input)
+		if err != nil || u.Scheme != "https" || u.Host == "" || u.Fragment != "" {
+			return fmt.Errorf("the %s field must be a valid HTTPS URL like https://ci.example.com/webhooks", field)
+		}
+
+		if strings.HasSuffix(u.Hostname(), "httpmock.dev") {
+			return fmt.Errorf("the %s field cannot point to an httpmock.dev URL", field)
+		}
+
+		return nil
+	})
+
+	govalidator.AddCustomRule(scenarioName, func(field string, rule string, message string, value interface{}) error {
+		input, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("the %s field must be a string", field)
+		}
+
+		if !scenarioNameRegex.MatchString(input) {
+			return fmt.Errorf("the %s field must contain only letters, numbers, dashes and underscores", field)
+		}
+
 		return nil
 	})
 }